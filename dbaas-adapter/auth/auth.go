@@ -0,0 +1,112 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+)
+
+const (
+	// FullAccess allows all APIs of the adapter
+	FullAccess = "full"
+	// ReadOnlyAccess allows only APIs which do not change anything, e.g. list, describe and track ones
+	ReadOnlyAccess = "readonly"
+
+	BasicMethod       = "basic"
+	CertificateMethod = "certificate"
+	TokenMethod       = "token"
+)
+
+var (
+	logger = common.GetLogger()
+
+	errInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is the authenticated client of the adapter API
+type Principal struct {
+	Name   string
+	Method string
+	Access string
+}
+
+// Allows checks that the principal has the required access
+func (p Principal) Allows(access string) bool {
+	return p.Access == FullAccess || p.Access == access
+}
+
+// Authenticator is one of authentication strategies. It returns nil principal without error
+// if the request does not contain credentials of its kind, so the next strategy can be tried.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Chain authenticates requests with the first strategy which finds credentials of its kind in the request
+type Chain struct {
+	authenticators []Authenticator
+	realm          string
+}
+
+func NewChain(realm string, authenticators ...Authenticator) *Chain {
+	return &Chain{authenticators: authenticators, realm: realm}
+}
+
+func (c *Chain) Authenticate(r *http.Request) (*Principal, error) {
+	for _, authenticator := range c.authenticators {
+		principal, err := authenticator.Authenticate(r)
+		if err != nil || principal != nil {
+			return principal, err
+		}
+	}
+	return nil, nil
+}
+
+// Authorizer returns the wrapper of handlers which allows only requests of principals with the required access
+func (c *Chain) Authorizer(access string) func(func(w http.ResponseWriter, r *http.Request)) http.Handler {
+	return func(f func(w http.ResponseWriter, r *http.Request)) http.Handler {
+		h := http.HandlerFunc(f)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			principal, err := c.Authenticate(r)
+			if err != nil || principal == nil {
+				if err != nil {
+					logger.WarnContext(ctx, fmt.Sprintf("Request to '%s' is not authenticated", r.URL.Path), slog.Any("error", err))
+				}
+				w.Header().Set("WWW-Authenticate", `Basic realm="`+c.realm+`"`)
+				common.ProcessResponseBody(ctx, w, []byte("Not authorized to use this API, only DBaaS aggregator can use it.\n"), http.StatusUnauthorized)
+				return
+			}
+			if !principal.Allows(access) {
+				logger.WarnContext(ctx, fmt.Sprintf("'%s' authenticated with %s method does not have %s access to '%s'",
+					principal.Name, principal.Method, access, r.URL.Path))
+				common.ProcessResponseBody(ctx, w, []byte(fmt.Sprintf("'%s' is not allowed to use this API.\n", principal.Name)), http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+func validateAccess(access string) error {
+	if access != FullAccess && access != ReadOnlyAccess {
+		return fmt.Errorf("access '%s' is not supported, it must be '%s' or '%s'", access, FullAccess, ReadOnlyAccess)
+	}
+	return nil
+}
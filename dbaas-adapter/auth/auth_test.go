@@ -0,0 +1,152 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSecret(t *testing.T, dir string, key string, value string) {
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, key), []byte(value), 0600))
+}
+
+func newSecretsDir(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv(common.OpenSearchDbaasAdapterSecretsDirEnv, dir)
+	writeSecret(t, dir, usernameKey, "dbaas-aggregator")
+	writeSecret(t, dir, passwordKey, "secret")
+	writeSecret(t, dir, CredentialsKey, `[{"username":"monitoring","password":"monitoring-secret","access":"readonly"}]`)
+	return dir
+}
+
+func serve(chain *Chain, access string, request *http.Request) int {
+	recorder := httptest.NewRecorder()
+	chain.Authorizer(access)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func TestBasicAuthorizerWithAccess(t *testing.T) {
+	newSecretsDir(t)
+	basicAuthenticator, err := NewBasicAuthenticator("default", "default")
+	assert.Nil(t, err)
+	chain := NewChain("test", basicAuthenticator)
+
+	request := httptest.NewRequest(http.MethodGet, "/databases", nil)
+	assert.Equal(t, http.StatusUnauthorized, serve(chain, ReadOnlyAccess, request))
+
+	request.SetBasicAuth("dbaas-aggregator", "secret")
+	assert.Equal(t, http.StatusOK, serve(chain, FullAccess, request))
+
+	request.SetBasicAuth("monitoring", "monitoring-secret")
+	assert.Equal(t, http.StatusOK, serve(chain, ReadOnlyAccess, request))
+	assert.Equal(t, http.StatusForbidden, serve(chain, FullAccess, request))
+
+	request.SetBasicAuth("monitoring", "secret")
+	assert.Equal(t, http.StatusUnauthorized, serve(chain, ReadOnlyAccess, request))
+	request.SetBasicAuth("default", "default")
+	assert.Equal(t, http.StatusUnauthorized, serve(chain, ReadOnlyAccess, request))
+}
+
+func TestReloadBasicCredentials(t *testing.T) {
+	dir := newSecretsDir(t)
+	basicAuthenticator, err := NewBasicAuthenticator("default", "default")
+	assert.Nil(t, err)
+
+	changed, err := basicAuthenticator.Reload()
+	assert.Nil(t, err)
+	assert.False(t, changed)
+
+	writeSecret(t, dir, passwordKey, "new-secret")
+	writeSecret(t, dir, CredentialsKey, `[{"username":"backup","password":"backup-secret","access":"full"}]`)
+	changed, err = basicAuthenticator.Reload()
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	request := httptest.NewRequest(http.MethodGet, "/databases", nil)
+	request.SetBasicAuth("dbaas-aggregator", "secret")
+	_, err = basicAuthenticator.Authenticate(request)
+	assert.ErrorIs(t, err, errInvalidCredentials)
+	request.SetBasicAuth("backup", "backup-secret")
+	principal, err := basicAuthenticator.Authenticate(request)
+	assert.Nil(t, err)
+	assert.Equal(t, FullAccess, principal.Access)
+	request.SetBasicAuth("monitoring", "monitoring-secret")
+	_, err = basicAuthenticator.Authenticate(request)
+	assert.ErrorIs(t, err, errInvalidCredentials)
+
+	// invalid credentials are not applied
+	writeSecret(t, dir, CredentialsKey, `[{"username":"backup","password":"backup-secret","access":"write"}]`)
+	_, err = basicAuthenticator.Reload()
+	assert.NotNil(t, err)
+	request.SetBasicAuth("backup", "backup-secret")
+	principal, err = basicAuthenticator.Authenticate(request)
+	assert.Nil(t, err)
+	assert.Equal(t, FullAccess, principal.Access)
+}
+
+func TestCertificateAuthenticator(t *testing.T) {
+	newSecretsDir(t)
+	basicAuthenticator, err := NewBasicAuthenticator("default", "default")
+	assert.Nil(t, err)
+	certificateAuthenticator := NewCertificateAuthenticator(ClientCertificateConfig{
+		Enabled:  true,
+		Subjects: map[string]string{"dbaas-aggregator": FullAccess, "monitoring": ReadOnlyAccess},
+	})
+	chain := NewChain("test", certificateAuthenticator, basicAuthenticator)
+	withCertificate := func(commonName string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, "/databases", nil)
+		certificate := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}}
+		return request
+	}
+
+	assert.Equal(t, http.StatusOK, serve(chain, FullAccess, withCertificate("dbaas-aggregator")))
+	assert.Equal(t, http.StatusForbidden, serve(chain, FullAccess, withCertificate("monitoring")))
+	assert.Equal(t, http.StatusUnauthorized, serve(chain, ReadOnlyAccess, withCertificate("unknown")))
+
+	request := withCertificate("unknown")
+	request.SetBasicAuth("dbaas-aggregator", "secret")
+	assert.Equal(t, http.StatusOK, serve(chain, FullAccess, request))
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(filepath.Join(t.TempDir(), "absent.json"))
+	assert.Nil(t, err)
+	assert.False(t, config.ClientCertificate.Enabled)
+	assert.False(t, config.TokenReview.Enabled)
+
+	path := filepath.Join(t.TempDir(), "dbaas.auth.json")
+	writeSecret(t, filepath.Dir(path), filepath.Base(path),
+		`{"clientCertificate":{"enabled":true,"subjects":{"dbaas-aggregator":"full"}},"tokenReview":{"enabled":true,"users":{"system:serviceaccount:monitoring:prometheus":"readonly"}}}`)
+	config, err = LoadConfig(path)
+	assert.Nil(t, err)
+	assert.True(t, config.ClientCertificate.Enabled)
+	assert.Equal(t, ReadOnlyAccess, config.TokenReview.Users["system:serviceaccount:monitoring:prometheus"])
+
+	writeSecret(t, filepath.Dir(path), filepath.Base(path), `{"tokenReview":{"enabled":true,"users":{"prometheus":"admin"}}}`)
+	_, err = LoadConfig(path)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,150 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+)
+
+const (
+	usernameKey = "DBAAS_ADAPTER_USERNAME"
+	passwordKey = "DBAAS_ADAPTER_PASSWORD"
+	// CredentialsKey is the name of the file in the secrets directory with additional credential sets
+	CredentialsKey = "DBAAS_ADAPTER_CREDENTIALS"
+)
+
+// Credentials is the credential set of basic authentication with the access of its user
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Access   string `json:"access"`
+}
+
+// BasicAuthenticator checks basic authentication against the main credentials of DBaaS aggregator
+// with full access and additional credential sets. All of them are read from the secrets directory
+// and reloaded periodically, so changed secrets are applied without restart.
+type BasicAuthenticator struct {
+	lock            sync.RWMutex
+	credentials     map[string]Credentials
+	defaultUsername string
+	defaultPassword string
+}
+
+// NewBasicAuthenticator loads credentials, the given username and password are used
+// if the main credentials are not found in the secrets directory
+func NewBasicAuthenticator(defaultUsername string, defaultPassword string) (*BasicAuthenticator, error) {
+	authenticator := &BasicAuthenticator{defaultUsername: defaultUsername, defaultPassword: defaultPassword}
+	if _, err := authenticator.Reload(); err != nil {
+		return nil, err
+	}
+	return authenticator, nil
+}
+
+func (ba *BasicAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+	ba.lock.RLock()
+	credentials, found := ba.credentials[username]
+	ba.lock.RUnlock()
+	if !found || subtle.ConstantTimeCompare([]byte(password), []byte(credentials.Password)) != 1 {
+		return nil, fmt.Errorf("%w of '%s' user", errInvalidCredentials, username)
+	}
+	return &Principal{Name: username, Method: BasicMethod, Access: credentials.Access}, nil
+}
+
+// Reload reads credentials from the secrets directory and returns true if they are changed.
+// Previous credentials are kept if new ones are not valid.
+func (ba *BasicAuthenticator) Reload() (bool, error) {
+	credentials, err := ba.loadCredentials()
+	if err != nil {
+		return false, err
+	}
+	ba.lock.Lock()
+	defer ba.lock.Unlock()
+	if maps.Equal(ba.credentials, credentials) {
+		return false, nil
+	}
+	ba.credentials = credentials
+	return true, nil
+}
+
+// Start reloads credentials in background with the given interval until the context is done
+func (ba *BasicAuthenticator) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		logger.Info("Reload of adapter credentials is disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				changed, err := ba.Reload()
+				if err != nil {
+					logger.Error("Failed to reload adapter credentials, previous ones are used", slog.Any("error", err))
+				} else if changed {
+					logger.Info("Adapter credentials are reloaded")
+				}
+			}
+		}
+	}()
+}
+
+func (ba *BasicAuthenticator) loadCredentials() (map[string]Credentials, error) {
+	username := common.GetSecretValue(common.OpenSearchDbaasAdapterSecretsDirEnv, usernameKey, ba.defaultUsername)
+	password := common.GetSecretValue(common.OpenSearchDbaasAdapterSecretsDirEnv, passwordKey, ba.defaultPassword)
+	credentials := map[string]Credentials{
+		username: {Username: username, Password: password, Access: FullAccess},
+	}
+	data := common.GetSecretValue(common.OpenSearchDbaasAdapterSecretsDirEnv, CredentialsKey, "")
+	if data == "" {
+		return credentials, nil
+	}
+	var additionalCredentials []Credentials
+	if err := json.Unmarshal([]byte(data), &additionalCredentials); err != nil {
+		return nil, fmt.Errorf("failed to parse additional credentials: %w", err)
+	}
+	for _, credential := range additionalCredentials {
+		if credential.Username == "" || credential.Password == "" {
+			return nil, fmt.Errorf("username and password of additional credentials must not be empty")
+		}
+		if credential.Access == "" {
+			credential.Access = ReadOnlyAccess
+		}
+		if err := validateAccess(credential.Access); err != nil {
+			return nil, fmt.Errorf("credentials of '%s' user are not valid: %w", credential.Username, err)
+		}
+		if _, ok := credentials[credential.Username]; ok {
+			return nil, fmt.Errorf("credentials of '%s' user are duplicated", credential.Username)
+		}
+		credentials[credential.Username] = credential
+	}
+	return credentials, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// CertificateAuthenticator authenticates clients by common names of their certificates verified during TLS handshake
+type CertificateAuthenticator struct {
+	subjects map[string]string
+}
+
+func NewCertificateAuthenticator(config ClientCertificateConfig) *CertificateAuthenticator {
+	return &CertificateAuthenticator{subjects: config.Subjects}
+}
+
+// Authenticate skips requests without verified certificates and certificates with unknown subjects,
+// so such clients can still use other authentication methods
+func (ca *CertificateAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	subject := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	access, ok := ca.subjects[subject]
+	if !ok {
+		logger.DebugContext(r.Context(), fmt.Sprintf("Client certificate subject '%s' is not configured", subject))
+		return nil, nil
+	}
+	return &Principal{Name: subject, Method: CertificateMethod, Access: access}, nil
+}
+
+// ServerTLSConfig returns TLS configuration of the server which verifies client certificates signed by the CA
+// if they are provided. Certificates are optional to keep other authentication methods available.
+func ServerTLSConfig(caPath string) (*tls.Config, error) {
+	caData, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate '%s' for client certificates: %w", caPath, err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("CA certificate '%s' for client certificates is not valid", caPath)
+	}
+	return &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  clientCAs,
+	}, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Config describes authentication strategies in addition to basic authentication which is always enabled
+type Config struct {
+	ClientCertificate ClientCertificateConfig `json:"clientCertificate"`
+	TokenReview       TokenReviewConfig       `json:"tokenReview"`
+}
+
+type ClientCertificateConfig struct {
+	Enabled bool `json:"enabled"`
+	// Subjects maps common names of client certificates to their access
+	Subjects map[string]string `json:"subjects"`
+}
+
+type TokenReviewConfig struct {
+	Enabled   bool     `json:"enabled"`
+	Audiences []string `json:"audiences,omitempty"`
+	// Users maps Kubernetes usernames, e.g. `system:serviceaccount:<namespace>:<name>`, to their access
+	Users map[string]string `json:"users"`
+}
+
+// LoadConfig reads authentication configuration from the file. Only basic authentication is enabled
+// if the file does not exist.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Info(fmt.Sprintf("Authentication configuration '%s' does not exist, only basic authentication is used", path))
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("failed to read authentication configuration '%s': %w", path, err)
+	}
+	if strings.TrimSpace(string(data)) != "" {
+		if err = json.Unmarshal(data, &config); err != nil {
+			return config, fmt.Errorf("failed to parse authentication configuration '%s': %w", path, err)
+		}
+	}
+	for subject, access := range config.ClientCertificate.Subjects {
+		if err = validateAccess(access); err != nil {
+			return config, fmt.Errorf("client certificate subject '%s' is not valid: %w", subject, err)
+		}
+	}
+	for user, access := range config.TokenReview.Users {
+		if err = validateAccess(access); err != nil {
+			return config, fmt.Errorf("token review user '%s' is not valid: %w", user, err)
+		}
+	}
+	return config, nil
+}
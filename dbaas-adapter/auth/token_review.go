@@ -0,0 +1,213 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	tokenReviewPath   = "/apis/authentication.k8s.io/v1/tokenreviews"
+	// tokenReviewCacheTTL limits how long results of token reviews are reused to reduce the load on Kubernetes API
+	tokenReviewCacheTTL = time.Minute
+	// tokenReviewFailureCacheTTL is shorter, so rejected tokens and failures of Kubernetes API are retried soon
+	tokenReviewFailureCacheTTL = 10 * time.Second
+	tokenReviewTimeout         = 10 * time.Second
+	// tokenReviewRate and tokenReviewBurst limit requests to Kubernetes API for tokens which are not cached,
+	// so random tokens cannot flood it
+	tokenReviewRate  = rate.Limit(10)
+	tokenReviewBurst = 20
+)
+
+var errTooManyTokenReviews = errors.New("too many token reviews, try again later")
+
+type tokenReview struct {
+	ApiVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Spec       tokenReviewSpec   `json:"spec"`
+	Status     tokenReviewStatus `json:"status,omitempty"`
+}
+
+type tokenReviewSpec struct {
+	Token     string   `json:"token"`
+	Audiences []string `json:"audiences,omitempty"`
+}
+
+type tokenReviewStatus struct {
+	Authenticated bool `json:"authenticated"`
+	User          struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Error string `json:"error,omitempty"`
+}
+
+type reviewedToken struct {
+	username string
+	err      error
+	expires  time.Time
+}
+
+// TokenReviewAuthenticator validates bearer tokens with Kubernetes TokenReview API
+// and grants access configured for the username of the token
+type TokenReviewAuthenticator struct {
+	users     map[string]string
+	audiences []string
+	address   string
+	tokenPath string
+	client    *http.Client
+	limiter   *rate.Limiter
+
+	lock  sync.Mutex
+	cache map[string]reviewedToken
+}
+
+// NewTokenReviewAuthenticator creates authenticator which uses the service account of the adapter pod
+// to call Kubernetes API
+func NewTokenReviewAuthenticator(config TokenReviewConfig) (*TokenReviewAuthenticator, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("token review requires Kubernetes API, but KUBERNETES_SERVICE_HOST or KUBERNETES_SERVICE_PORT is not set")
+	}
+	caData, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate of Kubernetes API: %w", err)
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AppendCertsFromPEM(caData)
+	client := &http.Client{
+		Timeout:   tokenReviewTimeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs}},
+	}
+	return newTokenReviewAuthenticator(config, "https://"+net.JoinHostPort(host, port), serviceAccountDir+"/token", client), nil
+}
+
+func newTokenReviewAuthenticator(config TokenReviewConfig, address string, tokenPath string, client *http.Client) *TokenReviewAuthenticator {
+	return &TokenReviewAuthenticator{
+		users:     config.Users,
+		audiences: config.Audiences,
+		address:   address,
+		tokenPath: tokenPath,
+		client:    client,
+		limiter:   rate.NewLimiter(tokenReviewRate, tokenReviewBurst),
+		cache:     make(map[string]reviewedToken),
+	}
+}
+
+func (ta *TokenReviewAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	if len(header) < len("Bearer ") || !strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return nil, nil
+	}
+	token := strings.TrimSpace(header[len("Bearer "):])
+	username, err := ta.review(token)
+	if err != nil {
+		return nil, err
+	}
+	access, ok := ta.users[username]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' user of the token is not allowed", errInvalidCredentials, username)
+	}
+	return &Principal{Name: username, Method: TokenMethod, Access: access}, nil
+}
+
+// review returns the username of the token using the cached result if it is not expired.
+// Failed reviews are cached too, and reviews of uncached tokens are rate limited.
+func (ta *TokenReviewAuthenticator) review(token string) (string, error) {
+	hash := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(hash[:])
+	now := time.Now()
+	ta.lock.Lock()
+	reviewed, ok := ta.cache[key]
+	ta.lock.Unlock()
+	if ok && now.Before(reviewed.expires) {
+		return reviewed.username, reviewed.err
+	}
+
+	if !ta.limiter.Allow() {
+		return "", errTooManyTokenReviews
+	}
+	username, err := ta.requestReview(token)
+	ttl := tokenReviewCacheTTL
+	if err != nil {
+		ttl = tokenReviewFailureCacheTTL
+	}
+	ta.lock.Lock()
+	defer ta.lock.Unlock()
+	for cachedKey, cached := range ta.cache {
+		if now.After(cached.expires) {
+			delete(ta.cache, cachedKey)
+		}
+	}
+	ta.cache[key] = reviewedToken{username: username, err: err, expires: now.Add(ttl)}
+	return username, err
+}
+
+func (ta *TokenReviewAuthenticator) requestReview(token string) (string, error) {
+	serviceAccountToken, err := os.ReadFile(ta.tokenPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read service account token: %w", err)
+	}
+	body, err := json.Marshal(tokenReview{
+		ApiVersion: "authentication.k8s.io/v1",
+		Kind:       "TokenReview",
+		Spec:       tokenReviewSpec{Token: token, Audiences: ta.audiences},
+	})
+	if err != nil {
+		return "", err
+	}
+	request, err := http.NewRequest(http.MethodPost, ta.address+tokenReviewPath, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(serviceAccountToken)))
+	response, err := ta.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to review token: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to review token: [%d] %s", response.StatusCode, string(responseBody))
+	}
+	var review tokenReview
+	if err = json.Unmarshal(responseBody, &review); err != nil {
+		return "", fmt.Errorf("failed to parse token review: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return "", fmt.Errorf("%w: token is not authenticated by Kubernetes: %s", errInvalidCredentials, review.Status.Error)
+	}
+	return review.Status.User.Username, nil
+}
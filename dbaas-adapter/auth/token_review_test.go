@@ -0,0 +1,105 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func newKubernetesServer(t *testing.T, reviews *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, tokenReviewPath, r.URL.Path)
+		assert.Equal(t, "Bearer adapter-token", r.Header.Get("Authorization"))
+		reviews.Add(1)
+		var review tokenReview
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&review))
+		switch review.Spec.Token {
+		case "prometheus-token":
+			review.Status.Authenticated = true
+			review.Status.User.Username = "system:serviceaccount:monitoring:prometheus"
+		case "other-token":
+			review.Status.Authenticated = true
+			review.Status.User.Username = "system:serviceaccount:default:other"
+		default:
+			review.Status.Error = "invalid bearer token"
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+}
+
+func TestTokenReviewAuthenticator(t *testing.T) {
+	var reviews atomic.Int32
+	server := newKubernetesServer(t, &reviews)
+	defer server.Close()
+	dir := t.TempDir()
+	writeSecret(t, dir, "token", "adapter-token\n")
+	authenticator := newTokenReviewAuthenticator(TokenReviewConfig{
+		Enabled: true,
+		Users:   map[string]string{"system:serviceaccount:monitoring:prometheus": ReadOnlyAccess},
+	}, server.URL, filepath.Join(dir, "token"), server.Client())
+	chain := NewChain("test", authenticator)
+
+	request := httptest.NewRequest(http.MethodGet, "/databases", nil)
+	request.Header.Set("Authorization", "Bearer prometheus-token")
+	assert.Equal(t, http.StatusOK, serve(chain, ReadOnlyAccess, request))
+	assert.Equal(t, http.StatusForbidden, serve(chain, FullAccess, request))
+	// the result of the review is cached
+	assert.Equal(t, int32(1), reviews.Load())
+
+	request.Header.Set("Authorization", "Bearer other-token")
+	assert.Equal(t, http.StatusUnauthorized, serve(chain, ReadOnlyAccess, request))
+	request.Header.Set("Authorization", "Bearer invalid-token")
+	assert.Equal(t, http.StatusUnauthorized, serve(chain, ReadOnlyAccess, request))
+	assert.Equal(t, http.StatusUnauthorized, serve(chain, ReadOnlyAccess, request))
+	assert.Equal(t, int32(3), reviews.Load())
+
+	request.Header.Del("Authorization")
+	principal, err := authenticator.Authenticate(request)
+	assert.Nil(t, err)
+	assert.Nil(t, principal)
+}
+
+func TestTokenReviewAuthenticatorCachesFailuresAndLimitsReviews(t *testing.T) {
+	var reviews atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reviews.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	writeSecret(t, dir, "token", "adapter-token")
+	authenticator := newTokenReviewAuthenticator(TokenReviewConfig{Enabled: true}, server.URL, filepath.Join(dir, "token"), server.Client())
+
+	_, err := authenticator.review("failed-token")
+	assert.NotNil(t, err)
+	_, err = authenticator.review("failed-token")
+	assert.NotNil(t, err)
+	// the failure of Kubernetes API is cached
+	assert.Equal(t, int32(1), reviews.Load())
+
+	for i := 0; i < 2*tokenReviewBurst; i++ {
+		_, err = authenticator.review(fmt.Sprintf("random-token-%d", i))
+	}
+	assert.ErrorIs(t, err, errTooManyTokenReviews)
+	assert.Less(t, reviews.Load(), int32(2*tokenReviewBurst))
+}
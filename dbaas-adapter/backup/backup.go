@@ -139,6 +139,7 @@ type BackupProvider struct {
 	repoRoot             string
 	Curator              *Curator
 	DefaultBackupService service.BackupAdministrationService
+	clones               *cloneTracker
 }
 
 func NewBackupProvider(opensearchClient common.Client, curatorClient *http.Client, repoRoot string) *BackupProvider {
@@ -167,6 +168,7 @@ func NewBackupProvider(opensearchClient common.Client, curatorClient *http.Clien
 			common.DbMaxLength,
 			[]string{},
 		),
+		clones: newCloneTracker(),
 	}
 }
 
@@ -251,6 +253,7 @@ func (bp *BackupProvider) CollectBackupV2Handler() func(w http.ResponseWriter, r
 			databaseNames = append(databaseNames, db.DatabaseName)
 		}
 		backupResponse, found := bp.DefaultBackupService.CollectBackupV2(ctx, backupRequest.StorageName, backupRequest.BlobPath, databaseNames)
+		recordOperationFound(backupOperation, found)
 		if !found {
 			logger.InfoContext(ctx, "Database not found")
 			w.WriteHeader(http.StatusNotFound)
@@ -282,6 +285,7 @@ func (bp *BackupProvider) TrackBackupV2Handler() func(w http.ResponseWriter, r *
 			_, _ = w.Write([]byte("Backup not found"))
 			return
 		}
+		recordTrackedStatus(backupOperation, backupID, string(backupResponse.Status))
 		_ = writeJSONResponse(ctx, w, http.StatusOK, backupResponse)
 	}
 }
@@ -300,6 +304,7 @@ func (bp *BackupProvider) DeleteBackupV2Handler() func(w http.ResponseWriter, r
 		}
 
 		found := bp.DefaultBackupService.EvictBackupV2(ctx, backupID, blobPath)
+		recordOperationFound(deleteOperation, found)
 		if !found {
 			logger.InfoContext(ctx, "Backup not found", slog.String("backupId", backupID))
 			w.WriteHeader(http.StatusNotFound)
@@ -334,6 +339,7 @@ func (bp *BackupProvider) RestoreBackupV2Handler() func(w http.ResponseWriter, r
 
 		restoreResponse, found, err := bp.restoreBackupV2WithSkipUsersRecovery(ctx, backupID, restoreRequest, dryRun)
 		if err != nil {
+			recordOperation(restoreOperation, err)
 			logger.ErrorContext(ctx, "Failed to create restore", slog.Any("error", err))
 			_ = writeJSONResponse(ctx, w, http.StatusInternalServerError, dao.ServerErrorResponse{
 				Error:     "Failed to create restore",
@@ -345,8 +351,10 @@ func (bp *BackupProvider) RestoreBackupV2Handler() func(w http.ResponseWriter, r
 			logger.InfoContext(ctx, "Backup not found", slog.String("backupId", backupID))
 			w.WriteHeader(http.StatusNotFound)
 			_, _ = w.Write([]byte("Backup not found"))
+			recordOperation(restoreOperation, ErrBackupNotFound)
 			return
 		}
+		recordOperation(restoreOperation, nil)
 		_ = writeJSONResponse(ctx, w, http.StatusAccepted, restoreResponse)
 	}
 }
@@ -441,6 +449,7 @@ func (bp *BackupProvider) TrackRestoreV2Handler() func(w http.ResponseWriter, r
 			_, _ = w.Write([]byte("Restore not found"))
 			return
 		}
+		recordTrackedStatus(restoreOperation, restoreID, string(restoreResponse.Status))
 		_ = writeJSONResponse(ctx, w, http.StatusOK, restoreResponse)
 	}
 }
@@ -500,6 +509,7 @@ func (bp BackupProvider) CollectBackupHandler() func(w http.ResponseWriter, r *h
 		}(r.Body)
 
 		backupID, err := bp.CollectBackup(databases, ctx)
+		recordOperation(backupOperation, err)
 		if err != nil {
 			logger.ErrorContext(ctx, "Failed to create snapshot", slog.String("error", err.Error()))
 			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
@@ -533,6 +543,7 @@ func (bp BackupProvider) DeleteBackupHandler() func(w http.ResponseWriter, r *ht
 		backupID := vars["backupID"]
 
 		responseBody, status, err := bp.DeleteBackup(backupID, ctx)
+		recordOperation(deleteOperation, err)
 		if err != nil {
 			logger.ErrorContext(ctx, "failed to delete backup", slog.String("error", err.Error()))
 			statusCode := http.StatusInternalServerError
@@ -568,10 +579,13 @@ func (bp BackupProvider) TrackBackupHandler() func(w http.ResponseWriter, r *htt
 			if errors.Is(err, ErrBackupNotFound) {
 				w.WriteHeader(http.StatusNotFound)
 			} else {
+				recordTrackedStatus(backupOperation, trackID, response.Status)
 				logger.ErrorContext(ctx, "Failed to marshal response to JSON", slog.Any("error", err))
 				common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
 				return
 			}
+		} else {
+			recordTrackedStatus(backupOperation, trackID, response.Status)
 		}
 
 		responseBody, err := json.Marshal(response)
@@ -603,6 +617,7 @@ func (bp BackupProvider) RestoreBackupHandler(repo string, basePath string) func
 
 		regenerateNames := r.URL.Query().Get("regenerateNames") == "true"
 		changedNameDb, err := bp.RestoreBackup(backupID, databases, repo, regenerateNames, ctx)
+		recordOperation(restoreOperation, err)
 		if err != nil {
 			logMsg := "failed to restore backup, internal server error occur"
 			statusCode := http.StatusInternalServerError
@@ -681,6 +696,7 @@ func (bp BackupProvider) RestorationBackupHandler(repo string, basePath string)
 		}
 
 		changedNameDb, err, trackId := bp.ProcessRestorationRequest(backupID, req, ctx)
+		recordOperation(restoreOperation, err)
 		if err != nil {
 			logger.ErrorContext(ctx, "failed to process restoration", slog.String("error", err.Error()))
 			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
@@ -726,6 +742,9 @@ func (bp BackupProvider) TrackRestoreFromTrackIdHandler(fromRepo string) func(w
 			logger.ErrorContext(ctx, logMsg, slog.String("error", err.Error()))
 			w.WriteHeader(errStatusCode)
 		}
+		if !errors.Is(err, ErrBackupNotFound) {
+			recordTrackedStatus(restoreOperation, backupID, response.Status)
+		}
 		var responseBody []byte
 		responseBody, err = json.Marshal(response)
 		if err != nil {
@@ -747,6 +766,7 @@ func (bp BackupProvider) TrackRestoreFromIndicesHandler(fromRepo string) func(w
 		indicesLine := vars["indices"]
 		indices := strings.Split(indicesLine, ",")
 		response := bp.TrackRestoreIndices(ctx, backupID, indices, fromRepo, nil)
+		recordTrackedStatus(restoreOperation, backupID+"/"+indicesLine, response.Status)
 		responseBody, err := json.Marshal(response)
 		if err != nil {
 			logger.ErrorContext(ctx, "Failed to marshal response to JSON", slog.Any("error", err))
@@ -1280,10 +1300,13 @@ func namesRegenerateRequestPart(pattern string, replacement string) string {
 	if pattern == "" {
 		return ""
 	}
+	// Pattern and replacement are marshalled to escape backslashes of quoted regular expressions
+	quotedPattern, _ := json.Marshal(pattern)
+	quotedReplacement, _ := json.Marshal(replacement)
 	return fmt.Sprintf(`
-		,"rename_pattern": "%s",
-		"rename_replacement": "%s"
-	`, pattern, replacement)
+		,"rename_pattern": %s,
+		"rename_replacement": %s
+	`, quotedPattern, quotedReplacement)
 }
 
 func prepareChangeNameRequestPart(renames []string) string {
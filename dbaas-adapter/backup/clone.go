@@ -0,0 +1,343 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/basic"
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	core "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/gorilla/mux"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+var (
+	// cloneBackupCheckInterval and cloneBackupCheckAttempts limit waiting for the backup of the source database
+	cloneBackupCheckInterval = time.Second
+	cloneBackupCheckAttempts = 600
+	// cloneTrackRetention is the time during which the clone can be tracked after its creation
+	cloneTrackRetention = 24 * time.Hour
+
+	errCloneSourceNotFound = errors.New("source database is not found")
+	errInvalidCloneRequest = errors.New("invalid clone request")
+)
+
+// cloneState is the state of indices restoration of the clone
+type cloneState struct {
+	backupID      string
+	indices       []string
+	changedNameDb map[string]string
+	restoring     bool
+	err           error
+	creationTime  time.Time
+}
+
+// cloneTracker keeps states of clones by their prefixes until the retention is over
+type cloneTracker struct {
+	lock   sync.Mutex
+	states map[string]*cloneState
+}
+
+func newCloneTracker() *cloneTracker {
+	return &cloneTracker{states: make(map[string]*cloneState)}
+}
+
+func (ct *cloneTracker) add(prefix string, state *cloneState) {
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	for trackID, previous := range ct.states {
+		if time.Since(previous.creationTime) > cloneTrackRetention {
+			delete(ct.states, trackID)
+		}
+	}
+	state.creationTime = time.Now()
+	ct.states[prefix] = state
+}
+
+func (ct *cloneTracker) update(prefix string, change func(state *cloneState)) {
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	if state, ok := ct.states[prefix]; ok {
+		change(state)
+	}
+}
+
+// get returns the copy of the clone state, so it can be read without the lock
+func (ct *cloneTracker) get(prefix string) (cloneState, bool) {
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	state, ok := ct.states[prefix]
+	if !ok {
+		return cloneState{}, false
+	}
+	return *state, true
+}
+
+// CloneRequest contains the classifier of the database clone and optionally the backup to restore the clone from
+type CloneRequest struct {
+	Classifier map[string]interface{} `json:"classifier"`
+	BackupId   string                 `json:"backupId,omitempty"`
+}
+
+// CloneResponse contains connection properties and resources of the database clone
+// and the track of restoration of its indices
+type CloneResponse struct {
+	basic.DbCreateResponseMultiUser
+	Restore ActionTrack `json:"restore"`
+}
+
+// CloneDatabaseHandler copies indices of the database to the new database with the prefix generated by the classifier
+func (bp BackupProvider) CloneDatabaseHandler(baseProvider *basic.BaseProvider, repo string, basePath string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		dbName := mux.Vars(r)["dbName"]
+		logger.InfoContext(ctx, fmt.Sprintf("Request to clone '%s' database is received", dbName))
+		var request CloneRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.ErrorContext(ctx, "Failed to decode request from JSON", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusBadRequest)
+			return
+		}
+		defer func() { _ = r.Body.Close() }()
+
+		response, err := bp.CloneDatabase(baseProvider, dbName, request, repo, basePath, ctx)
+		recordOperation(cloneOperation, err)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, errCloneSourceNotFound), errors.Is(err, ErrBackupNotFound):
+				statusCode = http.StatusNotFound
+			case errors.Is(err, errInvalidCloneRequest):
+				statusCode = http.StatusBadRequest
+			}
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to clone '%s' database", dbName), slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), statusCode)
+			return
+		}
+		responseBody, err := json.Marshal(response)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to marshal response to JSON", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		common.ProcessResponseBody(ctx, w, responseBody, http.StatusCreated)
+	}
+}
+
+// CloneTrackHandler returns the track of indices restoration of the clone by its prefix
+func (bp BackupProvider) CloneTrackHandler(repo string, basePath string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		trackID := mux.Vars(r)["trackID"]
+		logger.InfoContext(ctx, fmt.Sprintf("Request to track '%s' clone is received", trackID))
+		track, found := bp.TrackClone(trackID, repo, basePath, ctx)
+		if !found {
+			common.ProcessResponseBody(ctx, w, []byte(fmt.Sprintf("'%s' clone is not found", trackID)), http.StatusNotFound)
+			return
+		}
+		recordTrackedStatus(cloneOperation, trackID, track.Status)
+		responseBody, err := json.Marshal(track)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to marshal response to JSON", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		common.ProcessResponseBody(ctx, w, responseBody, http.StatusOK)
+	}
+}
+
+// CloneDatabase creates users and metadata of the clone with the prefix generated by the classifier,
+// and then restores indices of the database from the given or the new backup in background.
+// The returned track is identified by the prefix of the clone.
+func (bp BackupProvider) CloneDatabase(baseProvider *basic.BaseProvider, dbName string, request CloneRequest,
+	repo string, basePath string, ctx context.Context) (CloneResponse, error) {
+	sourceMetadata, err := baseProvider.GetMetadata(dbName, ctx)
+	if err != nil {
+		return CloneResponse{}, err
+	}
+	if sourceMetadata == nil {
+		return CloneResponse{}, fmt.Errorf("%w: '%s'", errCloneSourceNotFound, dbName)
+	}
+	sourcePrefix := basic.ResourcePrefix(dbName, sourceMetadata)
+	metadata := basic.CloneMetadata(sourceMetadata, request.Classifier)
+	namespace := common.ConvertAnyToString(request.Classifier["namespace"])
+	microserviceName := common.ConvertAnyToString(metadata["microserviceName"])
+	if namespace == "" || microserviceName == "" {
+		return CloneResponse{}, fmt.Errorf("%w: 'namespace' and 'microserviceName' of the classifier must be specified", errInvalidCloneRequest)
+	}
+	prefix, err := core.PrepareDatabaseName(namespace, microserviceName, 64)
+	if err != nil {
+		return CloneResponse{}, err
+	}
+	if err = bp.checkPrefixUniqueness(prefix, ctx); err != nil {
+		return CloneResponse{}, err
+	}
+
+	state := &cloneState{backupID: request.BackupId}
+	if state.backupID != "" {
+		state.changedNameDb, state.indices, err = bp.cloneIndices(state.backupID, repo, sourcePrefix, prefix, ctx)
+		if err != nil {
+			return CloneResponse{}, err
+		}
+	} else {
+		state.backupID, err = bp.CollectBackup([]string{sourcePrefix}, ctx)
+		if err != nil {
+			return CloneResponse{}, err
+		}
+	}
+	created, err := baseProvider.CreateClone(prefix, metadata, ctx)
+	if err != nil {
+		return CloneResponse{}, err
+	}
+	track := bp.cloneTrack(prefix, "PROCEEDING", *state, basePath)
+	bp.clones.add(prefix, state)
+	// Restoration must not be interrupted when the request is completed
+	go bp.restoreClone(prefix, sourcePrefix, repo, context.WithoutCancel(ctx))
+	return CloneResponse{DbCreateResponseMultiUser: created, Restore: track}, nil
+}
+
+// TrackClone returns the track of indices restoration of the clone, it is PROCEEDING until the restoration is requested
+func (bp BackupProvider) TrackClone(trackID string, repo string, basePath string, ctx context.Context) (ActionTrack, bool) {
+	state, found := bp.clones.get(trackID)
+	if !found {
+		return ActionTrack{}, false
+	}
+	status := "PROCEEDING"
+	switch {
+	case state.err != nil:
+		logger.ErrorContext(ctx, fmt.Sprintf("Restoration of '%s' clone is failed", trackID), slog.Any("error", state.err))
+		status = "FAIL"
+	case state.restoring:
+		status = bp.TrackRestoreIndices(ctx, state.backupID, state.indices, repo, state.changedNameDb).Status
+	}
+	return bp.cloneTrack(trackID, status, state, basePath), true
+}
+
+func (bp BackupProvider) cloneTrack(trackID string, status string, state cloneState, basePath string) ActionTrack {
+	track := restoreTrack(state.backupID, status, state.changedNameDb)
+	track.TrackID = trackID
+	trackPath := fmt.Sprintf("%s/backups/track/clone/%s", basePath, trackID)
+	track.TrackPath = &trackPath
+	return track
+}
+
+// restoreClone restores indices of the source database with the prefix of the clone. If the restoration fails,
+// indices of the clone are deleted, users and metadata of the clone are kept until the clone is dropped by DBaaS aggregator.
+func (bp BackupProvider) restoreClone(prefix string, sourcePrefix string, repo string, ctx context.Context) {
+	err := bp.restoreCloneIndices(prefix, sourcePrefix, repo, ctx)
+	if err == nil {
+		return
+	}
+	logger.ErrorContext(ctx, fmt.Sprintf("Failed to restore indices of '%s' clone", prefix), slog.Any("error", err))
+	bp.clones.update(prefix, func(state *cloneState) { state.err = err })
+	deleteRequest := opensearchapi.IndicesDeleteRequest{
+		Index:             []string{prefix + "_*"},
+		AllowNoIndices:    opensearchapi.BoolPtr(true),
+		IgnoreUnavailable: opensearchapi.BoolPtr(true),
+	}
+	response, err := deleteRequest.Do(ctx, bp.client)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Failed to delete indices of '%s' clone", prefix), slog.Any("error", err))
+		return
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.IsError() {
+		logger.ErrorContext(ctx, fmt.Sprintf("Failed to delete indices of '%s' clone: %s", prefix, response.String()))
+	}
+}
+
+func (bp BackupProvider) restoreCloneIndices(prefix string, sourcePrefix string, repo string, ctx context.Context) error {
+	state, _ := bp.clones.get(prefix)
+	if state.indices == nil {
+		if err := bp.waitForBackup(state.backupID, ctx); err != nil {
+			return err
+		}
+		changedNameDb, indices, err := bp.cloneIndices(state.backupID, repo, sourcePrefix, prefix, ctx)
+		if err != nil {
+			return err
+		}
+		state.changedNameDb, state.indices = changedNameDb, indices
+		bp.clones.update(prefix, func(current *cloneState) {
+			current.changedNameDb, current.indices = changedNameDb, indices
+		})
+	}
+	logger.InfoContext(ctx, fmt.Sprintf("Restoring %d indices of '%s' database from '%s' backup with '%s' prefix",
+		len(state.indices), sourcePrefix, state.backupID, prefix))
+	// $1 is the rest of the index name after the source prefix
+	pattern := fmt.Sprintf("^%s_(.*)", regexp.QuoteMeta(sourcePrefix))
+	if err := bp.requestRestore(ctx, []string{sourcePrefix}, state.backupID, pattern, prefix+"_$1"); err != nil {
+		return err
+	}
+	bp.clones.update(prefix, func(current *cloneState) { current.restoring = true })
+	return nil
+}
+
+// cloneIndices returns names of the source database indices in the backup mapped to names of the clone indices
+// and the sorted list of the clone indices
+func (bp BackupProvider) cloneIndices(backupID string, repo string, sourcePrefix string, prefix string,
+	ctx context.Context) (map[string]string, []string, error) {
+	indices, err := bp.getActualIndices(backupID, repo, nil, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	changedNameDb := make(map[string]string)
+	clonedIndices := make([]string, 0)
+	for _, index := range indices {
+		if name, ok := strings.CutPrefix(index, sourcePrefix+"_"); ok {
+			changedNameDb[index] = prefix + "_" + name
+			clonedIndices = append(clonedIndices, changedNameDb[index])
+		}
+	}
+	if len(clonedIndices) == 0 {
+		return nil, nil, fmt.Errorf("%w: '%s' backup does not contain indices of '%s' database", errInvalidCloneRequest, backupID, sourcePrefix)
+	}
+	sort.Strings(clonedIndices)
+	return changedNameDb, clonedIndices, nil
+}
+
+// waitForBackup checks the status of the backup until it is completed
+func (bp BackupProvider) waitForBackup(backupID string, ctx context.Context) error {
+	for attempt := 1; attempt <= cloneBackupCheckAttempts; attempt++ {
+		track, err := bp.TrackBackup(backupID, ctx)
+		if err != nil {
+			return err
+		}
+		switch track.Status {
+		case "SUCCESS":
+			return nil
+		case "PROCEEDING":
+			logger.DebugContext(ctx, fmt.Sprintf("Wait for '%s' backup to be completed, try: %d/%d", backupID, attempt, cloneBackupCheckAttempts))
+		default:
+			return fmt.Errorf("'%s' backup is failed with '%s' status", backupID, track.Status)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cloneBackupCheckInterval):
+		}
+	}
+	return fmt.Errorf("'%s' backup is not completed after %d checks", backupID, cloneBackupCheckAttempts)
+}
@@ -0,0 +1,276 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/Netcracker/dbaas-opensearch-adapter/basic"
+	"github.com/Netcracker/dbaas-opensearch-adapter/cluster"
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/gorilla/mux"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// cloneClient contains 'orders' database in 'dbaas-backups-repository' snapshots, 'orders_main' metadata of 'orders' database
+// and delegates other requests to the common stub
+type cloneClient struct {
+	*common.ClientStub
+	lock    sync.Mutex
+	deleted []string
+}
+
+func (c *cloneClient) Perform(req *http.Request) (*http.Response, error) {
+	body := ""
+	switch {
+	case req.URL.Path == "/dbaas_opensearch_metadata/_doc/missing":
+		body = `{"found":false}`
+	case req.URL.Path == "/dbaas_opensearch_metadata/_doc/orders_main":
+		body = `{"found":true,"_source":{"resourcePrefix":"orders","microserviceName":"orders"}}`
+	case strings.HasPrefix(req.URL.Path, "/_snapshot/dbaas-backups-repository/"):
+		snapshot := strings.Split(req.URL.Path, "/")[3]
+		body = `{"snapshots":[{"snapshot":"` + snapshot + `","state":"SUCCESS",
+"indices":{"orders_items":{},"orders_events":{},"ordersarchive_items":{},"payments_items":{}}}]}`
+	case req.Method == http.MethodDelete && !strings.HasPrefix(req.URL.Path, "/_plugins/"):
+		c.lock.Lock()
+		c.deleted = append(c.deleted, req.URL.Path)
+		c.lock.Unlock()
+		body = `{"acknowledged":true}`
+	default:
+		return c.ClientStub.Perform(req)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func (c *cloneClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *cloneClient) DiscoverNodes() error {
+	return nil
+}
+
+func (c *cloneClient) deletedPaths() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.deleted
+}
+
+// curatorStub returns 'new_backup' for new backups which is completed on the second status check
+type curatorStub struct {
+	lock          sync.Mutex
+	statusChecks  int
+	restoreBody   string
+	restoreStatus int
+}
+
+func (c *curatorStub) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	statusCode := http.StatusOK
+	body := ""
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/backup"):
+		body = "new_backup"
+	case strings.HasSuffix(req.URL.Path, "/jobstatus/new_backup"):
+		c.statusChecks++
+		body = `{"status":"Processing"}`
+		if c.statusChecks > 1 {
+			body = `{"status":"Successful"}`
+		}
+	case strings.HasSuffix(req.URL.Path, "/restore"):
+		data, _ := io.ReadAll(req.Body)
+		c.restoreBody = string(data)
+		body = "restore_track"
+		if c.restoreStatus != 0 {
+			statusCode = c.restoreStatus
+		}
+	}
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+func (c *curatorStub) state() (int, string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.statusChecks, c.restoreBody
+}
+
+func newCloneProviders() (*BackupProvider, *basic.BaseProvider, *curatorStub, *cloneClient) {
+	client := &cloneClient{ClientStub: common.NewClient()}
+	curator := &curatorStub{}
+	provider := NewBackupProvider(client, &http.Client{Transport: curator}, "snapshots")
+	baseProvider := basic.NewBaseProvider(&cluster.Opensearch{Host: "opensearch", Port: 9200, Protocol: "http", Client: client})
+	baseProvider.ApiVersion = common.ApiV2
+	return provider, baseProvider, curator, client
+}
+
+// waitForClone waits until restoration of the clone indices is requested or failed
+func waitForClone(t *testing.T, provider *BackupProvider, prefix string) cloneState {
+	var state cloneState
+	assert.Eventually(t, func() bool {
+		state, _ = provider.clones.get(prefix)
+		return state.restoring || state.err != nil
+	}, 5*time.Second, time.Millisecond)
+	return state
+}
+
+func TestCloneDatabaseFromBackup(t *testing.T) {
+	provider, baseProvider, curator, _ := newCloneProviders()
+	request := CloneRequest{
+		Classifier: map[string]interface{}{"namespace": "test", "microserviceName": "orders-copy"},
+		BackupId:   "orders_backup",
+	}
+	response, err := provider.CloneDatabase(baseProvider, "orders_main", request, "dbaas-backups-repository", "/api/v2/dbaas/adapter/opensearch", ctx)
+	assert.Nil(t, err)
+	assert.Len(t, response.ConnectionProperties, len(baseProvider.GetSupportedRoleTypes()))
+	prefix := response.ConnectionProperties[0].ResourcePrefix
+	assert.True(t, strings.HasPrefix(prefix, "orders-copy_test_"))
+	assert.Equal(t, "RESTORE", response.Restore.Action)
+	assert.Equal(t, "PROCEEDING", response.Restore.Status)
+	assert.Equal(t, prefix, response.Restore.TrackID)
+	assert.Equal(t, "orders_backup", response.Restore.Details.LocalId)
+	assert.Equal(t, map[string]string{"orders_items": prefix + "_items", "orders_events": prefix + "_events"}, response.Restore.ChangedNameDb)
+	assert.Equal(t, "/api/v2/dbaas/adapter/opensearch/backups/track/clone/"+prefix, *response.Restore.TrackPath)
+
+	state := waitForClone(t, provider, prefix)
+	assert.Nil(t, state.err)
+	assert.Equal(t, []string{prefix + "_events", prefix + "_items"}, state.indices)
+	statusChecks, restoreBody := curator.state()
+	assert.Equal(t, 0, statusChecks)
+	assert.Contains(t, restoreBody, `"vault": "orders_backup"`)
+	assert.Contains(t, restoreBody, `"dbs": ["orders"]`)
+	assert.Contains(t, restoreBody, `"rename_pattern": "^orders_(.*)"`)
+	assert.Contains(t, restoreBody, `"rename_replacement": "`+prefix+`_$1"`)
+}
+
+func TestCloneDatabaseWaitsForNewBackupInBackground(t *testing.T) {
+	cloneBackupCheckInterval = time.Millisecond
+	defer func() { cloneBackupCheckInterval = time.Second }()
+	provider, baseProvider, curator, _ := newCloneProviders()
+	request := CloneRequest{Classifier: map[string]interface{}{"namespace": "test", "microserviceName": "orders-copy"}}
+	response, err := provider.CloneDatabase(baseProvider, "orders", request, "dbaas-backups-repository", "", ctx)
+	assert.Nil(t, err)
+	prefix := response.ConnectionProperties[0].ResourcePrefix
+	assert.Equal(t, "PROCEEDING", response.Restore.Status)
+	assert.Equal(t, "new_backup", response.Restore.Details.LocalId)
+
+	state := waitForClone(t, provider, prefix)
+	assert.Nil(t, state.err)
+	assert.Equal(t, map[string]string{"orders_items": prefix + "_items", "orders_events": prefix + "_events"}, state.changedNameDb)
+	statusChecks, restoreBody := curator.state()
+	assert.Equal(t, 2, statusChecks)
+	assert.Contains(t, restoreBody, `"vault": "new_backup"`)
+}
+
+func TestCloneDatabaseDeletesIndicesIfRestorationFails(t *testing.T) {
+	provider, baseProvider, curator, client := newCloneProviders()
+	curator.restoreStatus = http.StatusInternalServerError
+	request := CloneRequest{
+		Classifier: map[string]interface{}{"namespace": "test", "microserviceName": "orders-copy"},
+		BackupId:   "orders_backup",
+	}
+	response, err := provider.CloneDatabase(baseProvider, "orders", request, "dbaas-backups-repository", "", ctx)
+	assert.Nil(t, err)
+	prefix := response.ConnectionProperties[0].ResourcePrefix
+
+	state := waitForClone(t, provider, prefix)
+	assert.ErrorIs(t, state.err, ErrCuratorUnavailable)
+	assert.Equal(t, []string{"/" + prefix + "_*"}, client.deletedPaths())
+	track, found := provider.TrackClone(prefix, "dbaas-backups-repository", "", ctx)
+	assert.True(t, found)
+	assert.Equal(t, "FAIL", track.Status)
+	assert.Equal(t, prefix, track.TrackID)
+}
+
+func TestCloneDatabaseHandler(t *testing.T) {
+	provider, baseProvider, curator, _ := newCloneProviders()
+	handler := provider.CloneDatabaseHandler(baseProvider, "dbaas-backups-repository", "")
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/databases/orders/clone",
+		strings.NewReader(`{"classifier":{"namespace":"test","microserviceName":"orders-copy"},"backupId":"orders_backup"}`))
+	request = mux.SetURLVars(request, map[string]string{"dbName": "orders"})
+	handler(recorder, request)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var response CloneResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, response.ConnectionProperties)
+	assert.NotEmpty(t, response.Resources)
+	assert.NotNil(t, response.Restore.TrackPath)
+	waitForClone(t, provider, response.Restore.TrackID)
+
+	curator.lock.Lock()
+	curator.restoreBody = ""
+	curator.lock.Unlock()
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, "/databases/missing/clone",
+		strings.NewReader(`{"classifier":{"namespace":"test","microserviceName":"orders-copy"}}`))
+	request = mux.SetURLVars(request, map[string]string{"dbName": "missing"})
+	handler(recorder, request)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, "/databases/orders/clone", strings.NewReader(`{"classifier":{"namespace":"test"}}`))
+	request = mux.SetURLVars(request, map[string]string{"dbName": "orders"})
+	handler(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, "/databases/payments/clone",
+		strings.NewReader(`{"classifier":{"namespace":"test","microserviceName":"payments-copy"},"backupId":"orders_backup"}`))
+	request = mux.SetURLVars(request, map[string]string{"dbName": "unknown"})
+	handler(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	_, restoreBody := curator.state()
+	assert.Empty(t, restoreBody)
+}
+
+func TestCloneTrackHandler(t *testing.T) {
+	provider, baseProvider, _, _ := newCloneProviders()
+	request := CloneRequest{
+		Classifier: map[string]interface{}{"namespace": "test", "microserviceName": "orders-copy"},
+		BackupId:   "orders_backup",
+	}
+	response, err := provider.CloneDatabase(baseProvider, "orders", request, "dbaas-backups-repository", "", ctx)
+	assert.Nil(t, err)
+	waitForClone(t, provider, response.Restore.TrackID)
+	handler := provider.CloneTrackHandler("dbaas-backups-repository", "")
+
+	recorder := httptest.NewRecorder()
+	trackRequest := httptest.NewRequest(http.MethodGet, "/backups/track/clone/"+response.Restore.TrackID, nil)
+	trackRequest = mux.SetURLVars(trackRequest, map[string]string{"trackID": response.Restore.TrackID})
+	handler(recorder, trackRequest)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var track ActionTrack
+	err = json.Unmarshal(recorder.Body.Bytes(), &track)
+	assert.Nil(t, err)
+	assert.Equal(t, response.Restore.TrackID, track.TrackID)
+	assert.NotEqual(t, "FAIL", track.Status)
+
+	recorder = httptest.NewRecorder()
+	trackRequest = httptest.NewRequest(http.MethodGet, "/backups/track/clone/unknown", nil)
+	trackRequest = mux.SetURLVars(trackRequest, map[string]string{"trackID": "unknown"})
+	handler(recorder, trackRequest)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,107 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	backupOperation  = "backup"
+	restoreOperation = "restore"
+	deleteOperation  = "delete"
+	cloneOperation   = "clone"
+
+	successResult  = "success"
+	notFoundResult = "not_found"
+	failureResult  = "failure"
+)
+
+var (
+	backupOperationsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dbaas_opensearch_backup_operations_total",
+		Help: "Number of requested backup, restore, delete and clone operations by result",
+	}, []string{"operation", "result"})
+	backupTrackedStatusCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dbaas_opensearch_backup_tracked_statuses_total",
+		Help: "Number of backup, restore and clone operations finished with the status observed by track requests",
+	}, []string{"operation", "status"})
+
+	// terminalTrackedStatuses are the final statuses of tracks of both API versions
+	terminalTrackedStatuses = []string{"SUCCESS", "FAIL", string(dao.CompletedStatus), string(dao.FailedStatus)}
+	// trackedOperationRetention limits how long finished operations are remembered to count each of them once
+	trackedOperationRetention = 24 * time.Hour
+	trackedOperations         = finishedOperations{operations: make(map[string]time.Time)}
+)
+
+// finishedOperations contains tracks whose terminal status is already counted
+type finishedOperations struct {
+	lock       sync.Mutex
+	operations map[string]time.Time
+}
+
+// add returns false if the operation is already counted and removes expired operations
+func (fo *finishedOperations) add(key string) bool {
+	fo.lock.Lock()
+	defer fo.lock.Unlock()
+	if _, ok := fo.operations[key]; ok {
+		return false
+	}
+	now := time.Now()
+	for operationKey, finishTime := range fo.operations {
+		if now.Sub(finishTime) > trackedOperationRetention {
+			delete(fo.operations, operationKey)
+		}
+	}
+	fo.operations[key] = now
+	return true
+}
+
+// recordOperation counts the requested operation as failed if there is an error
+// and as not found if the backup does not exist
+func recordOperation(operation string, err error) {
+	switch {
+	case err == nil:
+		backupOperationsCounter.WithLabelValues(operation, successResult).Inc()
+	case errors.Is(err, ErrBackupNotFound):
+		backupOperationsCounter.WithLabelValues(operation, notFoundResult).Inc()
+	default:
+		backupOperationsCounter.WithLabelValues(operation, failureResult).Inc()
+	}
+}
+
+func recordOperationFound(operation string, found bool) {
+	if found {
+		recordOperation(operation, nil)
+	} else {
+		recordOperation(operation, ErrBackupNotFound)
+	}
+}
+
+// recordTrackedStatus counts the operation when its track is finished for the first time,
+// so repeated polls of the same track are not counted
+func recordTrackedStatus(operation string, trackID string, status string) {
+	if !slices.Contains(terminalTrackedStatuses, status) || !trackedOperations.add(operation+"/"+trackID) {
+		return
+	}
+	backupTrackedStatusCounter.WithLabelValues(operation, status).Inc()
+}
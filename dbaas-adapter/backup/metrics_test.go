@@ -0,0 +1,33 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecordTrackedStatusCountsFinishedOperationsOnce(t *testing.T) {
+	recordTrackedStatus(deleteOperation, "first", "PROCEEDING")
+	recordTrackedStatus(deleteOperation, "first", "SUCCESS")
+	recordTrackedStatus(deleteOperation, "first", "SUCCESS")
+	recordTrackedStatus(deleteOperation, "second", "PROCEEDING")
+	recordTrackedStatus(deleteOperation, "second", "FAIL")
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(backupTrackedStatusCounter.WithLabelValues(deleteOperation, "PROCEEDING")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(backupTrackedStatusCounter.WithLabelValues(deleteOperation, "SUCCESS")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(backupTrackedStatusCounter.WithLabelValues(deleteOperation, "FAIL")))
+}
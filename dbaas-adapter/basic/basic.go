@@ -22,9 +22,9 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/Netcracker/dbaas-opensearch-adapter/cluster"
 	"github.com/Netcracker/dbaas-opensearch-adapter/common"
@@ -32,13 +32,15 @@ import (
 	core "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
 	"github.com/gorilla/mux"
 	"github.com/opensearch-project/opensearch-go/opensearchapi"
-	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 const (
 	DbaasMetadata        = "dbaas_opensearch_metadata"
 	DeletedStatus        = "DELETED"
 	DeletionFailedStatus = "DELETE_FAILED"
+	// resourcePrefixMetadataKey stores the prefix of the database in the metadata document,
+	// because the document ID is the name of the index if the index is created with the database
+	resourcePrefixMetadataKey = "resourcePrefix"
 )
 
 var logger = common.GetLogger()
@@ -48,7 +50,8 @@ type BaseProvider struct {
 	mutex             *sync.Mutex
 	passwordGenerator PasswordGenerator
 	ApiVersion        string
-	recoveryState     string
+	recovery          *usersRecovery
+	roleTypes         []RoleType
 }
 
 type DbCreateRequest struct {
@@ -64,6 +67,7 @@ type Settings struct {
 	ResourcePrefix bool        `json:"resourcePrefix,omitempty"`
 	CreateOnly     []string    `json:"createOnly,omitempty"`
 	IndexSettings  interface{} `json:"indexSettings,omitempty"`
+	Quota          *Quota      `json:"quota,omitempty"`
 }
 
 type DbCreateResponse struct {
@@ -93,7 +97,7 @@ func NewBaseProvider(opensearch *cluster.Opensearch) *BaseProvider {
 		opensearch:        opensearch,
 		mutex:             &sync.Mutex{},
 		passwordGenerator: NewPasswordGenerator(),
-		recoveryState:     RecoveryIdleState,
+		recovery:          newUsersRecovery(),
 	}
 }
 
@@ -147,48 +151,6 @@ func (bp BaseProvider) ListDatabasesHandler() func(w http.ResponseWriter, r *htt
 	}
 }
 
-func (bp BaseProvider) BulkDropResourceHandler() func(w http.ResponseWriter, r *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := common.PrepareContext(r)
-		logger.InfoContext(ctx, "Request to delete OpenSearch resources is received")
-		decoder := json.NewDecoder(r.Body)
-		var resources []dao.DbResource
-		err := decoder.Decode(&resources)
-		if err != nil {
-			logger.ErrorContext(ctx, "Failed to decode request in delete resources method", slog.Any("error", err))
-			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
-			return
-		}
-		defer func() { _ = r.Body.Close() }()
-
-		var resourcesToReturn []dao.DbResource
-		err = wait.PollUntilContextTimeout(ctx, 30*time.Second, 3*time.Minute, true, func(ctx context.Context) (done bool, err error) {
-			deletedResources := bp.deleteResources(resources, ctx)
-			successfulResources, failedResources := getResourcesWithSuccessfulAndFailedStatus(deletedResources)
-			resourcesToReturn = append(resourcesToReturn, successfulResources...)
-			if len(failedResources) > 0 {
-				logger.WarnContext(ctx, fmt.Sprintf("Some of the resources can't be deleted due to errors: %+v", failedResources))
-				resources = failedResources
-				return false, nil
-			}
-			return true, nil
-		})
-		if err != nil {
-			resourcesToReturn = resources
-			w.WriteHeader(http.StatusInternalServerError)
-		} else {
-			w.WriteHeader(http.StatusOK)
-		}
-		bytesResult, err := json.Marshal(resourcesToReturn)
-		if err != nil {
-			logger.ErrorContext(ctx, "Failed to serialize resources list", slog.Any("error", err))
-			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
-			return
-		}
-		common.ProcessResponseBody(ctx, w, bytesResult, 0)
-	}
-}
-
 func (bp BaseProvider) UpdateMetadataHandler() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := common.PrepareContext(r)
@@ -219,7 +181,7 @@ func (bp BaseProvider) SupportsHandler() func(w http.ResponseWriter, r *http.Req
 		supports := common.Supports{
 			Settings:          true,
 			Users:             true,
-			DescribeDatabases: false,
+			DescribeDatabases: true,
 		}
 		responseBody, err := json.Marshal(supports)
 		if err != nil {
@@ -233,41 +195,49 @@ func (bp BaseProvider) SupportsHandler() func(w http.ResponseWriter, r *http.Req
 }
 
 func (bp BaseProvider) EnsureAggregationIndex(ctx context.Context) error {
+	return bp.ensureIndex(DbaasMetadata, "", ctx)
+}
+
+// ensureIndex creates the service index of the adapter with the given settings and mappings if it does not exist
+func (bp BaseProvider) ensureIndex(name string, body string, ctx context.Context) error {
 	bp.mutex.Lock()
 	defer bp.mutex.Unlock()
 	existsRequest := opensearchapi.IndicesExistsRequest{
-		Index: []string{DbaasMetadata},
+		Index: []string{name},
 	}
 
 	childCtx := context.WithValue(ctx, common.RequestIdKey, common.GenerateUUID())
 	exist, err := existsRequest.Do(childCtx, bp.opensearch.Client)
 	if err != nil {
-		logger.ErrorContext(childCtx, fmt.Sprintf("Failed to check if '%s' index exists", DbaasMetadata), slog.String("error", err.Error()))
-		return fmt.Errorf("failed to check if '%s' index exists %w", DbaasMetadata, err)
+		logger.ErrorContext(childCtx, fmt.Sprintf("Failed to check if '%s' index exists", name), slog.String("error", err.Error()))
+		return fmt.Errorf("failed to check if '%s' index exists %w", name, err)
 	}
 
 	logger.DebugContext(childCtx, fmt.Sprintf("Check if index exists: %v", exist))
 	if exist.StatusCode == 200 {
-		logger.DebugContext(childCtx, fmt.Sprintf("'%s' index already exists", DbaasMetadata))
+		logger.DebugContext(childCtx, fmt.Sprintf("'%s' index already exists", name))
 		return nil
 	}
 	createRequest := opensearchapi.IndicesCreateRequest{
-		Index: DbaasMetadata,
+		Index: name,
+	}
+	if body != "" {
+		createRequest.Body = strings.NewReader(body)
 	}
 	createResponse, err := createRequest.Do(childCtx, bp.opensearch.Client)
 	if err != nil {
 		exist, err = existsRequest.Do(childCtx, bp.opensearch.Client)
 		if err != nil {
-			logger.ErrorContext(childCtx, fmt.Sprintf("failed to check if '%s' index exists", DbaasMetadata), slog.Any("error", err))
-			return fmt.Errorf("failed to check if '%s' index exists %w", DbaasMetadata, err)
+			logger.ErrorContext(childCtx, fmt.Sprintf("failed to check if '%s' index exists", name), slog.Any("error", err))
+			return fmt.Errorf("failed to check if '%s' index exists %w", name, err)
 		}
 		logger.DebugContext(childCtx, fmt.Sprintf("Check if index exists: %v", exist))
 		if exist.StatusCode == 200 {
-			logger.DebugContext(childCtx, fmt.Sprintf("'%s' index already exists", DbaasMetadata))
+			logger.DebugContext(childCtx, fmt.Sprintf("'%s' index already exists", name))
 			return nil
 		}
-		logger.ErrorContext(childCtx, fmt.Sprintf("failed to create '%s' index", DbaasMetadata), slog.Any("error", err))
-		return fmt.Errorf("failed to create '%s' index %w", DbaasMetadata, err)
+		logger.ErrorContext(childCtx, fmt.Sprintf("failed to create '%s' index", name), slog.Any("error", err))
+		return fmt.Errorf("failed to create '%s' index %w", name, err)
 	}
 	defer func() { _ = createResponse.Body.Close() }()
 
@@ -278,12 +248,12 @@ func (bp BaseProvider) EnsureAggregationIndex(ctx context.Context) error {
 			logger.ErrorContext(childCtx, "failed to read from http response body", slog.String("error", err.Error()))
 			return err
 		}
-		logger.ErrorContext(childCtx, fmt.Sprintf("%s index cannot be created because of error: [%d] %s", DbaasMetadata,
+		logger.ErrorContext(childCtx, fmt.Sprintf("%s index cannot be created because of error: [%d] %s", name,
 			createResponse.StatusCode, string(body)))
-		return fmt.Errorf("%s index cannot be created because of error: [%d]", DbaasMetadata,
+		return fmt.Errorf("%s index cannot be created because of error: [%d]", name,
 			createResponse.StatusCode)
 	}
-	logger.DebugContext(childCtx, fmt.Sprintf("'%s' index is created", DbaasMetadata))
+	logger.DebugContext(childCtx, fmt.Sprintf("'%s' index is created", name))
 	return nil
 }
 
@@ -309,6 +279,9 @@ func (bp BaseProvider) createDatabase(requestOnCreateDb DbCreateRequest, ctx con
 	if requestMicroserviceName, ok := requestOnCreateDb.Metadata["microserviceName"]; ok {
 		microserviceName = common.ConvertAnyToString(requestMicroserviceName)
 	}
+	if err := validateQuota(requestOnCreateDb.Settings); err != nil {
+		return nil, err
+	}
 
 	if requestOnCreateDb.Settings.ResourcePrefix {
 		err := checkForbiddenSymbolPrefix(requestOnCreateDb.NamePrefix)
@@ -355,6 +328,12 @@ func (bp BaseProvider) createDatabase(requestOnCreateDb DbCreateRequest, ctx con
 		if bp.ApiVersion == common.ApiV2 {
 			resourcesToCreate = []string{common.UserKind}
 		}
+		if common.DashboardsTenantsEnabled && requestOnCreateDb.Settings.ResourcePrefix {
+			resourcesToCreate = append(resourcesToCreate, common.TenantKind)
+		}
+	}
+	if slices.Contains(resourcesToCreate, common.TenantKind) && !requestOnCreateDb.Settings.ResourcePrefix {
+		return nil, fmt.Errorf("'%s' can be created only for databases with 'resourcePrefix' set to 'true'", common.TenantKind)
 	}
 
 	logger.InfoContext(ctx, fmt.Sprintf("Creating the following resource for database '%t': [%v]",
@@ -372,6 +351,13 @@ func (bp BaseProvider) createDatabase(requestOnCreateDb DbCreateRequest, ctx con
 			}
 			resources = append(resources, dao.DbResource{Kind: common.IndexKind, Name: indexName})
 		}
+		if resource == common.TenantKind {
+			err = bp.createTenant(prefix, ctx)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, dao.DbResource{Kind: common.TenantKind, Name: prefix})
+		}
 		if resource == common.UserKind {
 			var dbName string
 			username = requestOnCreateDb.Username
@@ -414,7 +400,17 @@ func (bp BaseProvider) createDatabase(requestOnCreateDb DbCreateRequest, ctx con
 	if indexName != "" {
 		metadataID = indexName
 	}
-	_, err = bp.CreateMetadata(metadataID, requestOnCreateDb.Metadata, ctx)
+	metadata := requestOnCreateDb.Metadata
+	if requestOnCreateDb.Settings.Quota != nil {
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+		}
+		metadata[quotaMetadataKey] = requestOnCreateDb.Settings.Quota
+	}
+	if requestOnCreateDb.Settings.ResourcePrefix && metadata != nil {
+		metadata[resourcePrefixMetadataKey] = prefix
+	}
+	_, err = bp.CreateMetadata(metadataID, metadata, ctx)
 	if err != nil {
 		if indexName != "" {
 			err = bp.deleteDatabase(indexName, ctx)
@@ -574,6 +570,10 @@ func (bp BaseProvider) CreateMetadata(identifier string, metadata map[string]int
 		return "", err
 	}
 	logger.InfoContext(ctx, fmt.Sprintf("%d: %s", response.StatusCode, string(responseBody)))
+	if response.IsError() {
+		return "", fmt.Errorf("during insert metadata for '%s' ID to '%s' index error occurred: [%d] %s",
+			identifier, DbaasMetadata, response.StatusCode, string(responseBody))
+	}
 	return string(responseBody), nil
 }
 
@@ -783,24 +783,27 @@ func (bp BaseProvider) deleteResources(resources []dao.DbResource, ctx context.C
 	resources = append(resources, additionalResources...)
 	deletedResources = append(deletedResources, failedResources...)
 
-	users := bp.deleteResourcesByKind(resources, common.UserKind)
+	users := bp.deleteResourcesByKind(resources, common.UserKind, ctx)
 	deletedResources = append(deletedResources, users...)
 
-	databases := bp.deleteResourcesByKind(resources, common.IndexKind)
+	databases := bp.deleteResourcesByKind(resources, common.IndexKind, ctx)
 	deletedResources = append(deletedResources, databases...)
 
-	metadata := bp.deleteResourcesByKind(resources, common.MetadataKind)
+	metadata := bp.deleteResourcesByKind(resources, common.MetadataKind, ctx)
 	deletedResources = append(deletedResources, metadata...)
 
-	templates := bp.deleteResourcesByKind(resources, common.TemplateKind)
+	templates := bp.deleteResourcesByKind(resources, common.TemplateKind, ctx)
 	deletedResources = append(deletedResources, templates...)
 
-	indexTemplates := bp.deleteResourcesByKind(resources, common.IndexTemplateKind)
+	indexTemplates := bp.deleteResourcesByKind(resources, common.IndexTemplateKind, ctx)
 	deletedResources = append(deletedResources, indexTemplates...)
 
-	aliases := bp.deleteResourcesByKind(resources, common.AliasKind)
+	aliases := bp.deleteResourcesByKind(resources, common.AliasKind, ctx)
 	deletedResources = append(deletedResources, aliases...)
 
+	tenants := bp.deleteResourcesByKind(resources, common.TenantKind, ctx)
+	deletedResources = append(deletedResources, tenants...)
+
 	return deletedResources
 }
 
@@ -818,12 +821,13 @@ func (bp BaseProvider) processResourcePrefixKind(resources []dao.DbResource, ctx
 					{Kind: common.TemplateKind, Name: namePattern},
 					{Kind: common.IndexTemplateKind, Name: namePattern},
 					{Kind: common.AliasKind, Name: namePattern},
+					{Kind: common.TenantKind, Name: resource.Name},
 				}...)
 			} else if bp.ApiVersion == common.ApiV2 {
 				users, err := bp.getUsersByPrefix(resource.Name)
 				if err != nil {
 					logger.ErrorContext(ctx, fmt.Sprintf("Failed to receive users with prefix %s ", resource.Name), slog.Any("error", err))
-					failedResources = append(additionalResources, *getResourceDeletionFailedStatus(resource, err))
+					failedResources = append(failedResources, *getResourceDeletionFailedStatus(resource, err))
 				} else {
 					for _, user := range users {
 						additionalResources = append(additionalResources,
@@ -835,6 +839,7 @@ func (bp BaseProvider) processResourcePrefixKind(resources []dao.DbResource, ctx
 						{Kind: common.TemplateKind, Name: namePattern},
 						{Kind: common.IndexTemplateKind, Name: namePattern},
 						{Kind: common.AliasKind, Name: namePattern},
+						{Kind: common.TenantKind, Name: resource.Name},
 					}...)
 				}
 			}
@@ -843,21 +848,20 @@ func (bp BaseProvider) processResourcePrefixKind(resources []dao.DbResource, ctx
 	return additionalResources, failedResources
 }
 
-func (bp BaseProvider) deleteResourcesByKind(resources []dao.DbResource, kind string) []dao.DbResource {
+func (bp BaseProvider) deleteResourcesByKind(resources []dao.DbResource, kind string, ctx context.Context) []dao.DbResource {
 	var result []dao.DbResource
 	for _, resource := range resources {
 		if resource.Kind == kind {
-			deletedResource := bp.deleteResource(resource, context.Background())
+			deletedResource := bp.deleteResource(resource, ctx)
 			result = append(result, *deletedResource)
 		}
 	}
 	return result
 }
 
+// deleteResource deletes the resource if it exists. It does not take the provider mutex, so deletions of different
+// resources do not wait for each other, concurrent deletion of the same resource fails and is retried by the caller.
 func (bp BaseProvider) deleteResource(resource dao.DbResource, ctx context.Context) *dao.DbResource {
-	bp.mutex.Lock()
-	defer bp.mutex.Unlock()
-
 	if resource.Kind == common.IndexKind {
 		database, err := bp.getDatabase(resource.Name)
 		if err != nil {
@@ -948,6 +952,26 @@ func (bp BaseProvider) deleteResource(resource dao.DbResource, ctx context.Conte
 			logger.ErrorContext(ctx, fmt.Sprintf("Failed to delete '%s' alias", resource.Name), slog.Any("error", err))
 			return getResourceDeletionFailedStatus(resource, err)
 		}
+	} else if resource.Kind == common.TenantKind {
+		tenant, err := bp.getTenant(resource.Name)
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to receive '%s' tenant information", resource.Name), slog.Any("error", err))
+			return getResourceDeletionFailedStatus(resource, err)
+		}
+		if tenant == nil {
+			logger.InfoContext(ctx, fmt.Sprintf("'%s' tenant does not exist, skip deletion", resource.Name))
+			return getResourceDeletionSuccessStatus(resource)
+		}
+		if tenant.Reserved {
+			err = fmt.Errorf("'%s' tenant is reserved", resource.Name)
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to delete '%s' tenant", resource.Name), slog.Any("error", err))
+			return getResourceDeletionFailedStatus(resource, err)
+		}
+		err = bp.deleteTenant(resource.Name, ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to delete '%s' tenant", resource.Name), slog.Any("error", err))
+			return getResourceDeletionFailedStatus(resource, err)
+		}
 	}
 	return getResourceDeletionSuccessStatus(resource)
 }
@@ -969,19 +993,6 @@ func getResourceDeletionFailedStatus(resource dao.DbResource, err error) *dao.Db
 	}
 }
 
-func getResourcesWithSuccessfulAndFailedStatus(resources []dao.DbResource) ([]dao.DbResource, []dao.DbResource) {
-	var deletedResources []dao.DbResource
-	var failedResources []dao.DbResource
-	for _, resource := range resources {
-		if resource.Status == DeletionFailedStatus {
-			failedResources = append(failedResources, resource)
-		} else {
-			deletedResources = append(deletedResources, resource)
-		}
-	}
-	return deletedResources, failedResources
-}
-
 func buildIndexName(dbName string, prefix string) string {
 	var indexName string
 	if dbName == "" {
@@ -435,6 +435,7 @@ func TestDeleteResourcesByPrefix(t *testing.T) {
 		{Kind: common.TemplateKind, Name: "test*", Status: DeletedStatus, ErrorMessage: ""},
 		{Kind: common.IndexTemplateKind, Name: "test*", Status: DeletedStatus, ErrorMessage: ""},
 		{Kind: common.AliasKind, Name: "test*", Status: DeletedStatus, ErrorMessage: ""},
+		{Kind: common.TenantKind, Name: "test", Status: DeletedStatus, ErrorMessage: ""},
 	}
 	assert.Equal(t, expectedDeletedResources, deletedResources)
 }
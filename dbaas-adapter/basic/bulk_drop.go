@@ -0,0 +1,467 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/gorilla/mux"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	BulkDropOperations = "dbaas_opensearch_bulk_drop_operations"
+
+	BulkDropProceedingStatus = "PROCEEDING"
+	BulkDropSuccessStatus    = "SUCCESS"
+	BulkDropFailStatus       = "FAIL"
+
+	// bulkDropAttempts with bulkDropRetryInterval between them keep the duration of retries
+	// of the previous synchronous bulk drop
+	bulkDropAttempts      = 7
+	bulkDropRetryInterval = 30 * time.Second
+	// bulkDropRetention is the time finished operations are kept in BulkDropOperations index
+	bulkDropRetention       = "7d"
+	maxBulkDropOperations   = 10000
+	bulkDropOperationsIndex = `{
+  "mappings": {
+    "properties": {
+      "operationId": {"type": "keyword"},
+      "status": {"type": "keyword"},
+      "requestKey": {"type": "keyword"},
+      "startTime": {"type": "date"},
+      "finishTime": {"type": "date"}
+    }
+  }
+}`
+)
+
+var (
+	errBulkDropOperationNotFound = errors.New("bulk drop operation is not found")
+
+	bulkDropOperationsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dbaas_opensearch_bulk_drop_operations_total",
+		Help: "Number of finished bulk drop operations by their status",
+	}, []string{"status"})
+)
+
+// BulkDropOperation is the state of the bulk drop which is stored in BulkDropOperations index,
+// so unfinished operations are resumed after restart of the adapter
+type BulkDropOperation struct {
+	OperationId string `json:"operationId"`
+	Status      string `json:"status"`
+	// RequestKey identifies the set of requested resources to not start the second drop of the same resources
+	RequestKey         string           `json:"requestKey"`
+	StartTime          string           `json:"startTime"`
+	FinishTime         string           `json:"finishTime,omitempty"`
+	Attempts           int              `json:"attempts"`
+	RequestedResources []dao.DbResource `json:"requestedResources"`
+	// Resources contains statuses of requested resources and resources found by resource prefixes
+	Resources []BulkDropResource `json:"resources"`
+}
+
+// BulkDropResource is the deletion status of the resource with the number of attempts to delete it
+type BulkDropResource struct {
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	Attempts     int    `json:"attempts"`
+}
+
+type bulkDropSearchResponse struct {
+	Hits struct {
+		Hits []struct {
+			Source BulkDropOperation `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+type bulkDropGetResponse struct {
+	Found  bool              `json:"found"`
+	Source BulkDropOperation `json:"_source"`
+}
+
+// BulkDropTracker runs bulk drop operations in background and tracks their progress.
+// Always use constructor NewBulkDropTracker() to create new instance of the BulkDropTracker.
+type BulkDropTracker struct {
+	provider *BaseProvider
+	lock     sync.Mutex
+	// ctx is the context of background operations which is cancelled when the adapter is stopped
+	ctx context.Context
+	// active contains proceeding operations by their request keys
+	active map[string]BulkDropOperation
+	// retryInterval is the delay between attempts to delete resources which are failed to be deleted
+	retryInterval time.Duration
+}
+
+func NewBulkDropTracker(provider *BaseProvider) *BulkDropTracker {
+	return &BulkDropTracker{
+		provider:      provider,
+		ctx:           context.Background(),
+		active:        make(map[string]BulkDropOperation),
+		retryInterval: bulkDropRetryInterval,
+	}
+}
+
+// Start creates the index of operations, removes outdated operations and resumes unfinished ones.
+// Background operations are stopped when the context is cancelled.
+func (bt *BulkDropTracker) Start(ctx context.Context) error {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+	bt.ctx = ctx
+	startCtx := context.WithValue(ctx, common.RequestIdKey, common.GenerateUUID())
+	if err := bt.provider.ensureIndex(BulkDropOperations, bulkDropOperationsIndex, startCtx); err != nil {
+		return err
+	}
+	if err := bt.provider.deleteOutdatedBulkDropOperations(startCtx); err != nil {
+		logger.ErrorContext(startCtx, "Failed to delete outdated bulk drop operations", slog.Any("error", err))
+	}
+	operations, err := bt.provider.getProceedingBulkDropOperations(startCtx)
+	if err != nil {
+		return err
+	}
+	for _, operation := range operations {
+		if _, ok := bt.active[operation.RequestKey]; ok {
+			continue
+		}
+		logger.InfoContext(startCtx, fmt.Sprintf("Bulk drop operation '%s' is resumed after %d attempts", operation.OperationId, operation.Attempts))
+		bt.active[operation.RequestKey] = operation
+		go bt.run(ctx, operation)
+	}
+	return nil
+}
+
+// BulkDropResourceHandler starts deletion of resources in background and returns the operation
+// which can be tracked by the path in `Location` header
+func (bt *BulkDropTracker) BulkDropResourceHandler(basePath string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		logger.InfoContext(ctx, "Request to delete OpenSearch resources is received")
+		decoder := json.NewDecoder(r.Body)
+		var resources []dao.DbResource
+		err := decoder.Decode(&resources)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to decode request in delete resources method", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusBadRequest)
+			return
+		}
+		defer func() { _ = r.Body.Close() }()
+
+		operation, err := bt.start(resources, ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to start bulk drop operation", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		responseBody, err := json.Marshal(operation)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to serialize bulk drop operation", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Location", fmt.Sprintf("%s/resources/bulk-drop/%s", basePath, operation.OperationId))
+		common.ProcessResponseBody(ctx, w, responseBody, http.StatusAccepted)
+	}
+}
+
+// TrackBulkDropHandler returns the current state of the bulk drop operation
+func (bt *BulkDropTracker) TrackBulkDropHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		operationId := mux.Vars(r)["operationId"]
+		logger.InfoContext(ctx, fmt.Sprintf("Request to track '%s' bulk drop operation is received", operationId))
+		operation, err := bt.Get(operationId, ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to get '%s' bulk drop operation", operationId), slog.Any("error", err))
+			status := http.StatusInternalServerError
+			if errors.Is(err, errBulkDropOperationNotFound) {
+				status = http.StatusNotFound
+			}
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), status)
+			return
+		}
+		responseBody, err := json.Marshal(operation)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to serialize bulk drop operation", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		common.ProcessResponseBody(ctx, w, responseBody, http.StatusOK)
+	}
+}
+
+// Get returns the operation from memory if it is proceeding, otherwise from BulkDropOperations index
+func (bt *BulkDropTracker) Get(operationId string, ctx context.Context) (BulkDropOperation, error) {
+	bt.lock.Lock()
+	for _, operation := range bt.active {
+		if operation.OperationId == operationId {
+			bt.lock.Unlock()
+			return operation, nil
+		}
+	}
+	bt.lock.Unlock()
+	return bt.provider.getBulkDropOperation(operationId, ctx)
+}
+
+// start returns the proceeding operation for the same set of resources if it exists, so repeated requests
+// of DBaaS aggregator do not start the second drop. Otherwise, it stores and starts the new operation.
+func (bt *BulkDropTracker) start(resources []dao.DbResource, ctx context.Context) (BulkDropOperation, error) {
+	requestKey := getBulkDropRequestKey(resources)
+	bt.lock.Lock()
+	if operation, ok := bt.active[requestKey]; ok {
+		bt.lock.Unlock()
+		logger.InfoContext(ctx, fmt.Sprintf("Bulk drop operation '%s' for the same resources is already proceeding", operation.OperationId))
+		return operation, nil
+	}
+	operation := BulkDropOperation{
+		OperationId:        common.GenerateUUID(),
+		Status:             BulkDropProceedingStatus,
+		RequestKey:         requestKey,
+		StartTime:          time.Now().UTC().Format(time.RFC3339),
+		RequestedResources: resources,
+		Resources:          make([]BulkDropResource, 0),
+	}
+	// The operation is reserved before it is stored, so requests for the same resources are not blocked by the storage
+	bt.active[requestKey] = operation
+	bt.lock.Unlock()
+
+	if err := bt.provider.saveBulkDropOperation(operation, ctx); err != nil {
+		bt.lock.Lock()
+		delete(bt.active, requestKey)
+		bt.lock.Unlock()
+		return operation, err
+	}
+	logger.InfoContext(ctx, fmt.Sprintf("Bulk drop operation '%s' is started", operation.OperationId))
+	go bt.run(bt.ctx, operation)
+	return operation, nil
+}
+
+// run deletes resources and retries deletion of failed ones until all of them are deleted or attempts are exhausted.
+// The state is stored after each attempt.
+func (bt *BulkDropTracker) run(ctx context.Context, operation BulkDropOperation) {
+	ctx = context.WithValue(ctx, common.RequestIdKey, operation.OperationId)
+	for {
+		if operation.Attempts > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(bt.retryInterval):
+			}
+		}
+		resources := operation.pendingResources()
+		operation.applyResults(resources, bt.provider.deleteResources(resources, ctx))
+		failed := len(operation.pendingResources())
+		if failed == 0 {
+			operation.finish(BulkDropSuccessStatus)
+			logger.InfoContext(ctx, fmt.Sprintf("Bulk drop operation '%s' is finished, all resources are deleted", operation.OperationId))
+		} else if operation.Attempts >= bulkDropAttempts {
+			operation.finish(BulkDropFailStatus)
+			logger.ErrorContext(ctx, fmt.Sprintf("Bulk drop operation '%s' is failed, %d resources are not deleted after %d attempts",
+				operation.OperationId, failed, operation.Attempts))
+		} else {
+			logger.WarnContext(ctx, fmt.Sprintf("%d resources of '%s' bulk drop operation can't be deleted due to errors, deletion will be retried",
+				failed, operation.OperationId))
+		}
+		if err := bt.provider.saveBulkDropOperation(operation, ctx); err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to store state of '%s' bulk drop operation", operation.OperationId), slog.Any("error", err))
+		}
+		bt.update(operation)
+		if operation.Status != BulkDropProceedingStatus {
+			bulkDropOperationsCounter.WithLabelValues(operation.Status).Inc()
+			return
+		}
+	}
+}
+
+func (bt *BulkDropTracker) update(operation BulkDropOperation) {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+	if operation.Status != BulkDropProceedingStatus {
+		delete(bt.active, operation.RequestKey)
+		return
+	}
+	operation.Resources = slices.Clone(operation.Resources)
+	bt.active[operation.RequestKey] = operation
+}
+
+// pendingResources returns requested resources before the first attempt and failed resources after it
+func (o *BulkDropOperation) pendingResources() []dao.DbResource {
+	if o.Attempts == 0 {
+		return o.RequestedResources
+	}
+	var resources []dao.DbResource
+	for _, resource := range o.Resources {
+		if resource.Status == DeletionFailedStatus {
+			resources = append(resources, dao.DbResource{Kind: resource.Kind, Name: resource.Name})
+		}
+	}
+	return resources
+}
+
+func (o *BulkDropOperation) applyResults(attempted []dao.DbResource, results []dao.DbResource) {
+	o.Attempts++
+	for _, resource := range attempted {
+		// Resource prefixes do not have own statuses if they are expanded to resources, so previous failures are removed
+		if resource.Kind == common.ResourcePrefixKind && !slices.ContainsFunc(results, func(result dao.DbResource) bool {
+			return result.Kind == resource.Kind && result.Name == resource.Name
+		}) {
+			o.Resources = slices.DeleteFunc(o.Resources, func(existing BulkDropResource) bool {
+				return existing.Kind == resource.Kind && existing.Name == resource.Name
+			})
+		}
+	}
+	for _, result := range results {
+		i := slices.IndexFunc(o.Resources, func(existing BulkDropResource) bool {
+			return existing.Kind == result.Kind && existing.Name == result.Name
+		})
+		if i < 0 {
+			o.Resources = append(o.Resources, BulkDropResource{Kind: result.Kind, Name: result.Name})
+			i = len(o.Resources) - 1
+		}
+		o.Resources[i].Status = string(result.Status)
+		o.Resources[i].ErrorMessage = result.ErrorMessage
+		o.Resources[i].Attempts++
+	}
+}
+
+func (o *BulkDropOperation) finish(status string) {
+	o.Status = status
+	o.FinishTime = time.Now().UTC().Format(time.RFC3339)
+}
+
+// getBulkDropRequestKey returns the hash of sorted kinds and names of resources which does not depend on their order
+func getBulkDropRequestKey(resources []dao.DbResource) string {
+	keys := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		keys = append(keys, fmt.Sprintf("%s/%s", resource.Kind, resource.Name))
+	}
+	sort.Strings(keys)
+	keys = slices.Compact(keys)
+	hash := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+func (bp BaseProvider) saveBulkDropOperation(operation BulkDropOperation, ctx context.Context) error {
+	body, err := json.Marshal(operation)
+	if err != nil {
+		return err
+	}
+	indexRequest := opensearchapi.IndexRequest{
+		Index:      BulkDropOperations,
+		DocumentID: operation.OperationId,
+		Body:       strings.NewReader(string(body)),
+	}
+	response, err := indexRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return fmt.Errorf("failed to store '%s' bulk drop operation: %w", operation.OperationId, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.IsError() {
+		return fmt.Errorf("during storing '%s' bulk drop operation error occurred: [%d] %s",
+			operation.OperationId, response.StatusCode, response.String())
+	}
+	return nil
+}
+
+func (bp BaseProvider) getBulkDropOperation(operationId string, ctx context.Context) (BulkDropOperation, error) {
+	getRequest := opensearchapi.GetRequest{
+		Index:      BulkDropOperations,
+		DocumentID: operationId,
+	}
+	response, err := getRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return BulkDropOperation{}, fmt.Errorf("failed to receive '%s' bulk drop operation: %w", operationId, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusNotFound {
+		return BulkDropOperation{}, fmt.Errorf("%w: '%s'", errBulkDropOperationNotFound, operationId)
+	}
+	if response.StatusCode != http.StatusOK {
+		return BulkDropOperation{}, fmt.Errorf("during receiving '%s' bulk drop operation error occurred: [%d] %s",
+			operationId, response.StatusCode, response.String())
+	}
+	var getResponse bulkDropGetResponse
+	if err = common.ProcessBody(response.Body, &getResponse); err != nil {
+		return BulkDropOperation{}, err
+	}
+	if !getResponse.Found {
+		return BulkDropOperation{}, fmt.Errorf("%w: '%s'", errBulkDropOperationNotFound, operationId)
+	}
+	return getResponse.Source, nil
+}
+
+func (bp BaseProvider) getProceedingBulkDropOperations(ctx context.Context) ([]BulkDropOperation, error) {
+	query := fmt.Sprintf(`{"query":{"match":{"status":"%s"}},"sort":[{"startTime":"asc"}]}`, BulkDropProceedingStatus)
+	size := maxBulkDropOperations
+	searchRequest := opensearchapi.SearchRequest{
+		Index: []string{BulkDropOperations},
+		Body:  strings.NewReader(query),
+		Size:  &size,
+	}
+	response, err := searchRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search proceeding bulk drop operations: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("during searching proceeding bulk drop operations error occurred: [%d] %s", response.StatusCode, response.String())
+	}
+	var searchResponse bulkDropSearchResponse
+	if err = common.ProcessBody(response.Body, &searchResponse); err != nil {
+		return nil, err
+	}
+	operations := make([]BulkDropOperation, 0, len(searchResponse.Hits.Hits))
+	for _, hit := range searchResponse.Hits.Hits {
+		operations = append(operations, hit.Source)
+	}
+	return operations, nil
+}
+
+func (bp BaseProvider) deleteOutdatedBulkDropOperations(ctx context.Context) error {
+	query := fmt.Sprintf(`{"query":{"range":{"finishTime":{"lt":"now-%s"}}}}`, bulkDropRetention)
+	deleteRequest := opensearchapi.DeleteByQueryRequest{
+		Index: []string{BulkDropOperations},
+		Body:  strings.NewReader(query),
+	}
+	response, err := deleteRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.IsError() && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("during deleting outdated bulk drop operations error occurred: [%d] %s", response.StatusCode, response.String())
+	}
+	return nil
+}
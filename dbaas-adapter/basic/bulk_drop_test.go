@@ -0,0 +1,270 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/gorilla/mux"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bulkDropClient stores bulk drop operations in memory and fails to receive users the given number of times
+type bulkDropClient struct {
+	lock          sync.Mutex
+	userFailures  int
+	storeFailures int
+	operations    map[string]string
+	proceeding    []BulkDropOperation
+}
+
+func newBulkDropClient(userFailures int) *bulkDropClient {
+	return &bulkDropClient{userFailures: userFailures, operations: make(map[string]string)}
+}
+
+func (c *bulkDropClient) Perform(req *http.Request) (*http.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	path := req.URL.Path
+	statusCode := http.StatusOK
+	body := `{"acknowledged":true}`
+	operationPath := fmt.Sprintf("/%s/_doc/", BulkDropOperations)
+	switch {
+	case req.Method == http.MethodPut && strings.HasPrefix(path, operationPath) && c.storeFailures > 0:
+		c.storeFailures--
+		statusCode = http.StatusServiceUnavailable
+		body = `{"error":"cluster is not available"}`
+	case req.Method == http.MethodPut && strings.HasPrefix(path, operationPath):
+		data, _ := io.ReadAll(req.Body)
+		c.operations[strings.TrimPrefix(path, operationPath)] = string(data)
+		statusCode = http.StatusCreated
+		body = `{"result":"created"}`
+	case req.Method == http.MethodGet && strings.HasPrefix(path, operationPath):
+		operation, ok := c.operations[strings.TrimPrefix(path, operationPath)]
+		if !ok {
+			statusCode = http.StatusNotFound
+			body = `{"found":false}`
+		} else {
+			body = fmt.Sprintf(`{"found":true,"_source":%s}`, operation)
+		}
+	case path == fmt.Sprintf("/%s/_search", BulkDropOperations):
+		var response bulkDropSearchResponse
+		for _, operation := range c.proceeding {
+			response.Hits.Hits = append(response.Hits.Hits, struct {
+				Source BulkDropOperation `json:"_source"`
+			}{Source: operation})
+		}
+		data, _ := json.Marshal(response)
+		body = string(data)
+	case req.Method == http.MethodGet && strings.Contains(path, "/internalusers/"):
+		if c.userFailures > 0 {
+			c.userFailures--
+			statusCode = http.StatusInternalServerError
+			body = `{"error":"security index is not available"}`
+		} else {
+			body = `{"orders_user":{"hash":""}}`
+		}
+	case req.Method == http.MethodGet && path == "/orders_items":
+		statusCode = http.StatusNotFound
+		body = `{}`
+	}
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func (c *bulkDropClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *bulkDropClient) DiscoverNodes() error {
+	return nil
+}
+
+func (c *bulkDropClient) storedOperation(operationId string) BulkDropOperation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var operation BulkDropOperation
+	_ = json.Unmarshal([]byte(c.operations[operationId]), &operation)
+	return operation
+}
+
+func waitBulkDropOperation(t *testing.T, tracker *BulkDropTracker, operationId string) BulkDropOperation {
+	var operation BulkDropOperation
+	assert.Eventually(t, func() bool {
+		var err error
+		operation, err = tracker.Get(operationId, ctx)
+		return err == nil && operation.Status != BulkDropProceedingStatus
+	}, 5*time.Second, 10*time.Millisecond)
+	return operation
+}
+
+func TestBulkDropRetriesFailedResources(t *testing.T) {
+	client := newBulkDropClient(2)
+	tracker := NewBulkDropTracker(newRecoveryProvider(client))
+	tracker.retryInterval = 10 * time.Millisecond
+	resources := []dao.DbResource{
+		{Kind: common.UserKind, Name: "orders_user"},
+		{Kind: common.IndexKind, Name: "orders_items"},
+	}
+	operation, err := tracker.start(resources, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, BulkDropProceedingStatus, operation.Status)
+
+	operation = waitBulkDropOperation(t, tracker, operation.OperationId)
+	assert.Equal(t, BulkDropSuccessStatus, operation.Status)
+	assert.NotEmpty(t, operation.FinishTime)
+	assert.Equal(t, 3, operation.Attempts)
+	expectedResources := []BulkDropResource{
+		{Kind: common.UserKind, Name: "orders_user", Status: DeletedStatus, Attempts: 3},
+		{Kind: common.IndexKind, Name: "orders_items", Status: DeletedStatus, Attempts: 1},
+	}
+	assert.Equal(t, expectedResources, operation.Resources)
+	assert.Equal(t, operation, client.storedOperation(operation.OperationId))
+}
+
+func TestBulkDropFailsAfterAllAttempts(t *testing.T) {
+	tracker := NewBulkDropTracker(newRecoveryProvider(newBulkDropClient(bulkDropAttempts)))
+	tracker.retryInterval = time.Millisecond
+	operation, err := tracker.start([]dao.DbResource{{Kind: common.UserKind, Name: "orders_user"}}, ctx)
+	assert.Nil(t, err)
+
+	operation = waitBulkDropOperation(t, tracker, operation.OperationId)
+	assert.Equal(t, BulkDropFailStatus, operation.Status)
+	assert.Equal(t, bulkDropAttempts, operation.Attempts)
+	assert.Len(t, operation.Resources, 1)
+	assert.Equal(t, DeletionFailedStatus, operation.Resources[0].Status)
+	assert.NotEmpty(t, operation.Resources[0].ErrorMessage)
+}
+
+func TestBulkDropDoesNotStartDuplicateOperation(t *testing.T) {
+	tracker := NewBulkDropTracker(newRecoveryProvider(newBulkDropClient(1)))
+	tracker.retryInterval = time.Minute
+	trackerCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tracker.ctx = trackerCtx
+	resources := []dao.DbResource{
+		{Kind: common.UserKind, Name: "orders_user"},
+		{Kind: common.IndexKind, Name: "orders_items"},
+	}
+	operation, err := tracker.start(resources, ctx)
+	assert.Nil(t, err)
+
+	duplicate, err := tracker.start([]dao.DbResource{resources[1], resources[0]}, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, operation.OperationId, duplicate.OperationId)
+
+	other, err := tracker.start(resources[:1], ctx)
+	assert.Nil(t, err)
+	assert.NotEqual(t, operation.OperationId, other.OperationId)
+}
+
+func TestBulkDropReleasesOperationIfItIsNotStored(t *testing.T) {
+	client := newBulkDropClient(0)
+	client.storeFailures = 1
+	tracker := NewBulkDropTracker(newRecoveryProvider(client))
+	resources := []dao.DbResource{{Kind: common.IndexKind, Name: "orders_items"}}
+	failed, err := tracker.start(resources, ctx)
+	assert.NotNil(t, err)
+	_, err = tracker.Get(failed.OperationId, ctx)
+	assert.ErrorIs(t, err, errBulkDropOperationNotFound)
+
+	operation, err := tracker.start(resources, ctx)
+	assert.Nil(t, err)
+	assert.NotEqual(t, failed.OperationId, operation.OperationId)
+	operation = waitBulkDropOperation(t, tracker, operation.OperationId)
+	assert.Equal(t, BulkDropSuccessStatus, operation.Status)
+}
+
+func TestBulkDropTrackerResumesProceedingOperations(t *testing.T) {
+	client := newBulkDropClient(0)
+	client.proceeding = []BulkDropOperation{{
+		OperationId:        "resumed",
+		Status:             BulkDropProceedingStatus,
+		RequestKey:         "key",
+		Attempts:           1,
+		RequestedResources: []dao.DbResource{{Kind: common.UserKind, Name: "orders_user"}},
+		Resources: []BulkDropResource{
+			{Kind: common.UserKind, Name: "orders_user", Status: DeletionFailedStatus, ErrorMessage: "timeout", Attempts: 1},
+		},
+	}}
+	tracker := NewBulkDropTracker(newRecoveryProvider(client))
+	tracker.retryInterval = time.Millisecond
+	err := tracker.Start(context.Background())
+	assert.Nil(t, err)
+
+	operation := waitBulkDropOperation(t, tracker, "resumed")
+	assert.Equal(t, BulkDropSuccessStatus, operation.Status)
+	assert.Equal(t, 2, operation.Attempts)
+	assert.Equal(t, []BulkDropResource{{Kind: common.UserKind, Name: "orders_user", Status: DeletedStatus, Attempts: 2}}, operation.Resources)
+}
+
+func TestApplyResultsOfExpandedResourcePrefix(t *testing.T) {
+	operation := BulkDropOperation{
+		Attempts: 1,
+		Resources: []BulkDropResource{
+			{Kind: common.ResourcePrefixKind, Name: "orders", Status: DeletionFailedStatus, ErrorMessage: "timeout", Attempts: 1},
+		},
+	}
+	attempted := operation.pendingResources()
+	assert.Equal(t, []dao.DbResource{{Kind: common.ResourcePrefixKind, Name: "orders"}}, attempted)
+	operation.applyResults(attempted, []dao.DbResource{{Kind: common.IndexKind, Name: "orders*", Status: DeletedStatus}})
+	assert.Equal(t, []BulkDropResource{{Kind: common.IndexKind, Name: "orders*", Status: DeletedStatus, Attempts: 1}}, operation.Resources)
+	assert.Empty(t, operation.pendingResources())
+}
+
+func TestBulkDropResourceHandler(t *testing.T) {
+	tracker := NewBulkDropTracker(newRecoveryProvider(newBulkDropClient(0)))
+	tracker.retryInterval = time.Millisecond
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/resources/bulk-drop", strings.NewReader(`[{"kind":"user","name":"orders_user"}]`))
+	tracker.BulkDropResourceHandler("/api/v2/dbaas/adapter/opensearch")(recorder, request)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	var operation BulkDropOperation
+	err := json.Unmarshal(recorder.Body.Bytes(), &operation)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, operation.OperationId)
+	assert.Equal(t, "/api/v2/dbaas/adapter/opensearch/resources/bulk-drop/"+operation.OperationId, recorder.Header().Get("Location"))
+	waitBulkDropOperation(t, tracker, operation.OperationId)
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, "/resources/bulk-drop/"+operation.OperationId, nil)
+	request = mux.SetURLVars(request, map[string]string{"operationId": operation.OperationId})
+	tracker.TrackBulkDropHandler()(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	err = json.Unmarshal(recorder.Body.Bytes(), &operation)
+	assert.Nil(t, err)
+	assert.Equal(t, BulkDropSuccessStatus, operation.Status)
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, "/resources/bulk-drop/unknown", nil)
+	request = mux.SetURLVars(request, map[string]string{"operationId": "unknown"})
+	tracker.TrackBulkDropHandler()(recorder, request)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, "/resources/bulk-drop", strings.NewReader(`{"kind":"user"}`))
+	tracker.BulkDropResourceHandler("")(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
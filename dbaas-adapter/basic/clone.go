@@ -0,0 +1,81 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+)
+
+const (
+	classifierMetadataKey       = "classifier"
+	microserviceNameMetadataKey = "microserviceName"
+)
+
+// adapterMetadataKeys are keys of the metadata document which are written by the adapter to store the state
+// of the database, so they are not copied to the clone
+var adapterMetadataKeys = []string{
+	resourcePrefixMetadataKey,
+	quotaMetadataKey,
+	quotaWriteBlockedMetadataKey,
+	settingsChangesMetadataKey,
+	passwordRotationMetadataKey,
+}
+
+// CloneMetadata returns the metadata of the database clone. It is the copy of the metadata received from DBaaS aggregator
+// for the source database with the target classifier and the microservice name of the classifier if it is specified.
+func CloneMetadata(sourceMetadata map[string]interface{}, classifier map[string]interface{}) map[string]interface{} {
+	metadata := make(map[string]interface{}, len(sourceMetadata)+1)
+	for key, value := range sourceMetadata {
+		if !slices.Contains(adapterMetadataKeys, key) {
+			metadata[key] = value
+		}
+	}
+	metadata[classifierMetadataKey] = classifier
+	if microserviceName := common.ConvertAnyToString(classifier[microserviceNameMetadataKey]); microserviceName != "" {
+		metadata[microserviceNameMetadataKey] = microserviceName
+	}
+	return metadata
+}
+
+// ResourcePrefix returns the resource prefix of the database stored in its metadata,
+// the name of the database is returned for metadata created before the prefix was stored
+func ResourcePrefix(dbName string, metadata map[string]interface{}) string {
+	if prefix := common.ConvertAnyToString(metadata[resourcePrefixMetadataKey]); prefix != "" {
+		return prefix
+	}
+	return dbName
+}
+
+// CreateClone creates users of all supported role types and the metadata document for the database
+// which indices are restored with the given prefix
+func (bp BaseProvider) CreateClone(prefix string, metadata map[string]interface{}, ctx context.Context) (DbCreateResponseMultiUser, error) {
+	if bp.ApiVersion != common.ApiV2 {
+		return DbCreateResponseMultiUser{}, fmt.Errorf("cloning of databases is supported only in %s version of OpenSearch DBaaS adapter", common.ApiV2)
+	}
+	request := DbCreateRequest{
+		Metadata:   metadata,
+		NamePrefix: prefix,
+		Settings:   Settings{ResourcePrefix: true},
+	}
+	result, err := bp.createDatabase(request, ctx)
+	if err != nil {
+		return DbCreateResponseMultiUser{}, err
+	}
+	return result.(DbCreateResponseMultiUser), nil
+}
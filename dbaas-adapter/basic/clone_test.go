@@ -0,0 +1,59 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCloneMetadata(t *testing.T) {
+	source := map[string]interface{}{
+		"classifier":        map[string]interface{}{"namespace": "prod", "microserviceName": "orders"},
+		"microserviceName":  "orders",
+		"dbOwner":           "orders",
+		"resourcePrefix":    "orders",
+		"quota":             map[string]interface{}{"maxIndices": 10},
+		"quotaWriteBlocked": true,
+		"settingsChanges":   []interface{}{map[string]interface{}{"status": "SUCCESS"}},
+		"passwordRotation":  map[string]interface{}{"temporaryUsers": []interface{}{"orders_tmp"}},
+	}
+	classifier := map[string]interface{}{"namespace": "test", "microserviceName": "orders-copy"}
+	metadata := CloneMetadata(source, classifier)
+	assert.Equal(t, classifier, metadata["classifier"])
+	assert.Equal(t, "orders-copy", metadata["microserviceName"])
+	assert.Equal(t, "orders", metadata["dbOwner"])
+	assert.Len(t, metadata, 3)
+	assert.Equal(t, "orders", source["microserviceName"])
+
+	metadata = CloneMetadata(source, map[string]interface{}{"namespace": "test"})
+	assert.Equal(t, "orders", metadata["microserviceName"])
+}
+
+func TestCreateClone(t *testing.T) {
+	response, err := bp.CreateClone("orders-copy", map[string]interface{}{"microserviceName": "orders-copy"}, ctx)
+	assert.Nil(t, err)
+	assert.Len(t, response.ConnectionProperties, len(bp.GetSupportedRoleTypes()))
+	for _, connectionProperties := range response.ConnectionProperties {
+		assert.Equal(t, "orders-copy", connectionProperties.ResourcePrefix)
+	}
+	assert.Contains(t, response.Resources, dao.DbResource{Kind: common.ResourcePrefixKind, Name: "orders-copy"})
+	assert.Contains(t, response.Resources, dao.DbResource{Kind: common.MetadataKind, Name: "orders-copy"})
+
+	_, err = baseProvider.CreateClone("orders-copy", nil, ctx)
+	assert.NotNil(t, err)
+}
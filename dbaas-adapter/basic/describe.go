@@ -0,0 +1,355 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// DatabaseDescription contains resources owned by the logical database
+type DatabaseDescription struct {
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	Indices        []IndexDescription     `json:"indices"`
+	Templates      []string               `json:"templates"`
+	IndexTemplates []string               `json:"indexTemplates"`
+	Aliases        []string               `json:"aliases"`
+	Users          []UserDescription      `json:"users"`
+	Resources      []dao.DbResource       `json:"resources"`
+}
+
+type IndexDescription struct {
+	Name string `json:"name"`
+	// DocsCount is the number of documents in primary shards, it is empty for closed indices
+	DocsCount *int64 `json:"docsCount,omitempty"`
+	// StoreSize is the size of primary and replica shards in bytes, it is empty for closed indices
+	StoreSize *int64 `json:"storeSize,omitempty"`
+}
+
+type UserDescription struct {
+	Username string `json:"username"`
+	RoleType string `json:"roleType"`
+}
+
+type catIndex struct {
+	Index     string  `json:"index"`
+	DocsCount *string `json:"docs.count"`
+	StoreSize *string `json:"store.size"`
+}
+
+// DescribeDatabasesHandler returns resources owned by the requested logical databases.
+// Databases are requested by their names or resource prefixes, not found databases are not returned.
+func (bp BaseProvider) DescribeDatabasesHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		logger.InfoContext(ctx, "Request to describe databases is received")
+		var databases []string
+		if r.ContentLength != 0 {
+			decoder := json.NewDecoder(r.Body)
+			err := decoder.Decode(&databases)
+			if err != nil {
+				logger.ErrorContext(ctx, "Failed to decode request in describe databases handler", slog.Any("error", err))
+				common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusBadRequest)
+				return
+			}
+		}
+		defer func() { _ = r.Body.Close() }()
+		descriptions, err := bp.describeDatabases(databases, ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to describe databases", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		responseBody, err := json.Marshal(descriptions)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to serialize databases descriptions", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		common.ProcessResponseBody(ctx, w, responseBody, http.StatusOK)
+	}
+}
+
+func (bp BaseProvider) describeDatabases(databases []string, ctx context.Context) (map[string]DatabaseDescription, error) {
+	descriptions := make(map[string]DatabaseDescription)
+	if len(databases) == 0 {
+		return descriptions, nil
+	}
+	// Users are received once for all databases because there is no way to filter them by attributes
+	users, err := bp.getUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, database := range databases {
+		name := strings.TrimRight(database, "*")
+		if name == "" {
+			return nil, fmt.Errorf("database name must not be empty")
+		}
+		description, err := bp.describeDatabase(name, users, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe '%s' database: %w", name, err)
+		}
+		if description != nil {
+			descriptions[database] = *description
+		}
+	}
+	return descriptions, nil
+}
+
+// describeDatabase collects resources which names start with the given prefix and users related to it.
+// It returns nil if the database does not own any resource.
+func (bp BaseProvider) describeDatabase(prefix string, users map[string]User, ctx context.Context) (*DatabaseDescription, error) {
+	namePattern := fmt.Sprintf("%s*", prefix)
+	metadata, err := bp.GetMetadata(prefix, ctx)
+	if err != nil {
+		return nil, err
+	}
+	indices, err := bp.describeIndices(namePattern, ctx)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := bp.getTemplateNames(namePattern, ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexTemplates, err := bp.getIndexTemplateNames(namePattern, ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := bp.getAliasNames(namePattern, ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := bp.getTenant(prefix)
+	if err != nil {
+		return nil, err
+	}
+	// Temporary users of the password rotation are deleted with the rotation, so they are not resources of the database
+	temporaryUsers, err := getTemporaryUsers(metadata)
+	if err != nil {
+		return nil, err
+	}
+	description := &DatabaseDescription{
+		Metadata:       metadata,
+		Indices:        indices,
+		Templates:      templates,
+		IndexTemplates: indexTemplates,
+		Aliases:        aliases,
+		Users:          bp.describeUsers(prefix, users, temporaryUsers),
+		Resources:      make([]dao.DbResource, 0),
+	}
+	for _, user := range description.Users {
+		description.Resources = append(description.Resources, dao.DbResource{Kind: common.UserKind, Name: user.Username})
+	}
+	for _, index := range description.Indices {
+		description.Resources = append(description.Resources, dao.DbResource{Kind: common.IndexKind, Name: index.Name})
+	}
+	for _, template := range description.Templates {
+		description.Resources = append(description.Resources, dao.DbResource{Kind: common.TemplateKind, Name: template})
+	}
+	for _, template := range description.IndexTemplates {
+		description.Resources = append(description.Resources, dao.DbResource{Kind: common.IndexTemplateKind, Name: template})
+	}
+	for _, alias := range description.Aliases {
+		description.Resources = append(description.Resources, dao.DbResource{Kind: common.AliasKind, Name: alias})
+	}
+	if tenant != nil {
+		description.Resources = append(description.Resources, dao.DbResource{Kind: common.TenantKind, Name: prefix})
+	}
+	if metadata != nil {
+		description.Resources = append(description.Resources, dao.DbResource{Kind: common.MetadataKind, Name: prefix})
+	}
+	if len(description.Resources) == 0 {
+		logger.InfoContext(ctx, fmt.Sprintf("There are no resources for '%s' database", prefix))
+		return nil, nil
+	}
+	return description, nil
+}
+
+// describeUsers returns users with the resource prefix attribute of the database
+// or with the name built from the database prefix except temporary users of the password rotation
+func (bp BaseProvider) describeUsers(prefix string, users map[string]User, temporaryUsers []string) []UserDescription {
+	descriptions := make([]UserDescription, 0)
+	for username, user := range users {
+		if user.Attributes[resourcePrefixAttributeName] != prefix && username != prefix &&
+			!strings.HasPrefix(username, fmt.Sprintf("%s_", prefix)) {
+			continue
+		}
+		if slices.Contains(temporaryUsers, username) {
+			continue
+		}
+		roleType := AdminRoleType
+		if len(user.Roles) > 0 {
+			roleType = bp.DefineRoleType(user.Roles[0])
+		}
+		descriptions = append(descriptions, UserDescription{Username: username, RoleType: roleType})
+	}
+	sort.Slice(descriptions, func(i, j int) bool {
+		return descriptions[i].Username < descriptions[j].Username
+	})
+	return descriptions
+}
+
+func (bp BaseProvider) describeIndices(pattern string, ctx context.Context) ([]IndexDescription, error) {
+	indicesRequest := opensearchapi.CatIndicesRequest{
+		Index:  []string{pattern},
+		Bytes:  "b",
+		Format: "json",
+		H:      []string{"index", "docs.count", "store.size"},
+	}
+	response, err := indicesRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return nil, fmt.Errorf("error occurred during retrieving indices by '%s' pattern: %+v", pattern, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	indices := make([]IndexDescription, 0)
+	if response.StatusCode == http.StatusNotFound {
+		return indices, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("during receiving indices by '%s' pattern error occurred: [%d]", pattern, response.StatusCode)
+	}
+	var catIndices []catIndex
+	err = common.ProcessBody(response.Body, &catIndices)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range catIndices {
+		if strings.HasPrefix(index.Index, ".") {
+			continue
+		}
+		indices = append(indices, IndexDescription{
+			Name:      index.Index,
+			DocsCount: parseCatNumber(index.DocsCount),
+			StoreSize: parseCatNumber(index.StoreSize),
+		})
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i].Name < indices[j].Name
+	})
+	return indices, nil
+}
+
+func (bp BaseProvider) getTemplateNames(pattern string, ctx context.Context) ([]string, error) {
+	getTemplateRequest := opensearchapi.IndicesGetTemplateRequest{
+		Name: []string{pattern},
+	}
+	response, err := getTemplateRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	names := make([]string, 0)
+	if response.StatusCode == http.StatusNotFound {
+		return names, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("during receiving templates by '%s' pattern error occurred: [%d]", pattern, response.StatusCode)
+	}
+	var templates map[string]interface{}
+	err = common.ProcessBody(response.Body, &templates)
+	if err != nil {
+		return nil, err
+	}
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (bp BaseProvider) getIndexTemplateNames(pattern string, ctx context.Context) ([]string, error) {
+	getIndexTemplateRequest := opensearchapi.IndicesGetIndexTemplateRequest{
+		Name: []string{pattern},
+	}
+	response, err := getIndexTemplateRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	names := make([]string, 0)
+	if response.StatusCode == http.StatusNotFound {
+		return names, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("during receiving index templates by '%s' pattern error occurred: [%d]", pattern, response.StatusCode)
+	}
+	var templates map[string][]IndexTemplate
+	err = common.ProcessBody(response.Body, &templates)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates["index_templates"] {
+		names = append(names, template.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (bp BaseProvider) getAliasNames(pattern string, ctx context.Context) ([]string, error) {
+	getAliasRequest := opensearchapi.IndicesGetAliasRequest{
+		Name: []string{pattern},
+	}
+	response, err := getAliasRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	names := make([]string, 0)
+	if response.StatusCode == http.StatusNotFound {
+		return names, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("during receiving aliases by '%s' pattern error occurred: [%d]", pattern, response.StatusCode)
+	}
+	var indices map[string]map[string]map[string]interface{}
+	err = common.ProcessBody(response.Body, &indices)
+	if err != nil {
+		return nil, err
+	}
+	unique := make(map[string]bool)
+	for _, index := range indices {
+		for alias := range index["aliases"] {
+			if !unique[alias] {
+				unique[alias] = true
+				names = append(names, alias)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func parseCatNumber(value *string) *int64 {
+	if value == nil {
+		return nil
+	}
+	number, err := strconv.ParseInt(*value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &number
+}
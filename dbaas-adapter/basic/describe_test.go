@@ -0,0 +1,136 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"encoding/json"
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// describeClient returns resources of `orders` database and nothing for other ones
+type describeClient struct{}
+
+func (c *describeClient) Perform(req *http.Request) (*http.Response, error) {
+	path := req.URL.Path
+	statusCode := http.StatusOK
+	body := "{}"
+	ordersRequested := strings.Contains(path, "/orders")
+	switch {
+	case path == "/_plugins/_security/api/internalusers":
+		body = `{"orders_a1":{"hash":"","backend_roles":["dbaas_readonly"],"attributes":{"resource_prefix":"orders"}},
+"orders_b2":{"hash":"","backend_roles":["dbaas_dml"],"attributes":{"resource_prefix":"orders"}},
+"legacy":{"hash":"","backend_roles":[],"attributes":{"resource_prefix":"orders"}},
+"payments_c3":{"hash":"","backend_roles":["dbaas_admin"],"attributes":{"resource_prefix":"payments"}}}`
+	case strings.HasPrefix(path, "/dbaas_opensearch_metadata/_doc/"):
+		body = `{"found":false}`
+		if ordersRequested {
+			body = `{"found":true,"_source":{"microserviceName":"orders-service"}}`
+		}
+	case strings.HasPrefix(path, "/_cat/indices/"):
+		body = "[]"
+		if ordersRequested {
+			body = `[{"index":"orders_items","docs.count":"10","store.size":"2048"},{"index":"orders_closed","docs.count":null,"store.size":null}]`
+		}
+	case strings.HasPrefix(path, "/_template/"):
+		if ordersRequested {
+			body = `{"orders_template":{"index_patterns":["orders_*"]}}`
+		}
+	case strings.HasPrefix(path, "/_index_template/"):
+		statusCode = http.StatusNotFound
+		if ordersRequested {
+			statusCode = http.StatusOK
+			body = `{"index_templates":[{"name":"orders_index_template","index_template":{}}]}`
+		}
+	case strings.HasPrefix(path, "/_plugins/_security/api/tenants/"):
+		statusCode = http.StatusNotFound
+		if ordersRequested {
+			statusCode = http.StatusOK
+			body = `{"orders":{"reserved":false,"description":"Tenant of 'orders' database"}}`
+		}
+	case strings.HasPrefix(path, "/_alias/"):
+		statusCode = http.StatusNotFound
+		if ordersRequested {
+			statusCode = http.StatusOK
+			body = `{"orders_items":{"aliases":{"orders_alias":{}}},"orders_closed":{"aliases":{"orders_alias":{}}}}`
+		}
+	default:
+		statusCode = http.StatusNotFound
+	}
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func (c *describeClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *describeClient) DiscoverNodes() error {
+	return nil
+}
+
+func TestDescribeDatabases(t *testing.T) {
+	provider := newRecoveryProvider(&describeClient{})
+	descriptions, err := provider.describeDatabases([]string{"orders", "unknown"}, ctx)
+	assert.Nil(t, err)
+	assert.Len(t, descriptions, 1)
+
+	description := descriptions["orders"]
+	assert.Equal(t, "orders-service", description.Metadata["microserviceName"])
+	assert.Len(t, description.Indices, 2)
+	assert.Equal(t, "orders_closed", description.Indices[0].Name)
+	assert.Nil(t, description.Indices[0].DocsCount)
+	assert.Equal(t, "orders_items", description.Indices[1].Name)
+	assert.Equal(t, int64(10), *description.Indices[1].DocsCount)
+	assert.Equal(t, int64(2048), *description.Indices[1].StoreSize)
+	assert.Equal(t, []string{"orders_template"}, description.Templates)
+	assert.Equal(t, []string{"orders_index_template"}, description.IndexTemplates)
+	assert.Equal(t, []string{"orders_alias"}, description.Aliases)
+	expectedUsers := []UserDescription{
+		{Username: "legacy", RoleType: AdminRoleType},
+		{Username: "orders_a1", RoleType: ReadOnlyRoleType},
+		{Username: "orders_b2", RoleType: DmlRoleType},
+	}
+	assert.Equal(t, expectedUsers, description.Users)
+	assert.Contains(t, description.Resources, dao.DbResource{Kind: common.UserKind, Name: "orders_a1"})
+	assert.Contains(t, description.Resources, dao.DbResource{Kind: common.IndexKind, Name: "orders_items"})
+	assert.Contains(t, description.Resources, dao.DbResource{Kind: common.MetadataKind, Name: "orders"})
+	assert.Contains(t, description.Resources, dao.DbResource{Kind: common.TenantKind, Name: "orders"})
+	assert.Len(t, description.Resources, 10)
+}
+
+func TestDescribeDatabasesHandler(t *testing.T) {
+	provider := newRecoveryProvider(&describeClient{})
+	request := httptest.NewRequest(http.MethodPost, "/describe/databases", strings.NewReader(`["orders*"]`))
+	recorder := httptest.NewRecorder()
+	provider.DescribeDatabasesHandler()(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var descriptions map[string]DatabaseDescription
+	err := json.Unmarshal(recorder.Body.Bytes(), &descriptions)
+	assert.Nil(t, err)
+	assert.Contains(t, descriptions, "orders*")
+	assert.Len(t, descriptions["orders*"].Users, 3)
+
+	request = httptest.NewRequest(http.MethodPost, "/describe/databases", strings.NewReader(`{"name":"orders"}`))
+	recorder = httptest.NewRecorder()
+	provider.DescribeDatabasesHandler()(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
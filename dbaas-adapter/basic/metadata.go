@@ -0,0 +1,126 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+const (
+	// metadataSearchPageSize is the number of metadata documents received by one search or scroll request
+	metadataSearchPageSize  = 1000
+	metadataScrollKeepAlive = time.Minute
+)
+
+var errMetadataIndexNotFound = errors.New("metadata index is not found")
+
+type metadataSearchResponse struct {
+	ScrollID string `json:"_scroll_id"`
+	Hits     struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []metadataDocument `json:"hits"`
+	} `json:"hits"`
+}
+
+type metadataDocument struct {
+	ID     string                 `json:"_id"`
+	Source map[string]interface{} `json:"_source"`
+}
+
+// searchMetadata returns all metadata documents matching the query. Documents are received page by page with the scroll.
+// It returns errMetadataIndexNotFound if the metadata index does not exist and the error if not all matching documents
+// are received, so callers never work with the incomplete list of databases.
+func (bp BaseProvider) searchMetadata(query string, source []string, ctx context.Context) ([]metadataDocument, error) {
+	size := metadataSearchPageSize
+	searchRequest := opensearchapi.SearchRequest{
+		Index:          []string{DbaasMetadata},
+		Body:           strings.NewReader(query),
+		Source:         source,
+		Size:           &size,
+		Scroll:         metadataScrollKeepAlive,
+		TrackTotalHits: true,
+	}
+	response, err := searchRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search metadata documents: %w", err)
+	}
+	if response.StatusCode == http.StatusNotFound {
+		_ = response.Body.Close()
+		return nil, errMetadataIndexNotFound
+	}
+	page, err := readMetadataPage(response)
+	if err != nil {
+		return nil, err
+	}
+	scrollID := page.ScrollID
+	defer func() { bp.clearMetadataScroll(scrollID, ctx) }()
+	total := page.Hits.Total.Value
+	documents := page.Hits.Hits
+	for len(page.Hits.Hits) == size && scrollID != "" {
+		scrollRequest := opensearchapi.ScrollRequest{
+			Body: strings.NewReader(fmt.Sprintf(`{"scroll":"%dms","scroll_id":%q}`, metadataScrollKeepAlive.Milliseconds(), scrollID)),
+		}
+		response, err = scrollRequest.Do(ctx, bp.opensearch.Client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scroll metadata documents: %w", err)
+		}
+		if page, err = readMetadataPage(response); err != nil {
+			return nil, err
+		}
+		if page.ScrollID != "" {
+			scrollID = page.ScrollID
+		}
+		documents = append(documents, page.Hits.Hits...)
+	}
+	if total > len(documents) {
+		return nil, fmt.Errorf("only %d of %d metadata documents are received", len(documents), total)
+	}
+	return documents, nil
+}
+
+func readMetadataPage(response *opensearchapi.Response) (metadataSearchResponse, error) {
+	defer func() { _ = response.Body.Close() }()
+	var page metadataSearchResponse
+	if response.StatusCode != http.StatusOK {
+		return page, fmt.Errorf("during searching metadata documents error occurred: [%d] %s", response.StatusCode, response.String())
+	}
+	err := common.ProcessBody(response.Body, &page)
+	return page, err
+}
+
+// clearMetadataScroll releases the search context of the scroll, failures are ignored because it expires anyway
+func (bp BaseProvider) clearMetadataScroll(scrollID string, ctx context.Context) {
+	if scrollID == "" {
+		return
+	}
+	clearRequest := opensearchapi.ClearScrollRequest{
+		Body: strings.NewReader(fmt.Sprintf(`{"scroll_id":[%q]}`, scrollID)),
+	}
+	response, err := clearRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return
+	}
+	_ = response.Body.Close()
+}
@@ -0,0 +1,69 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"fmt"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// scrollClient serves the given number of metadata documents page by page and records cleared scrolls
+type scrollClient struct {
+	lock      sync.Mutex
+	documents int
+	served    int
+	cleared   []string
+}
+
+func (c *scrollClient) Perform(req *http.Request) (*http.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if req.Method == http.MethodDelete && req.URL.Path == "/_search/scroll" {
+		body, _ := io.ReadAll(req.Body)
+		c.cleared = append(c.cleared, string(body))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"succeeded":true}`))}, nil
+	}
+	var hits []string
+	for ; c.served < c.documents && len(hits) < metadataSearchPageSize; c.served++ {
+		hits = append(hits, fmt.Sprintf(`{"_id":"db%d","_source":{"resourcePrefix":"db%d"}}`, c.served, c.served))
+	}
+	body := fmt.Sprintf(`{"_scroll_id":"scroll-%d","hits":{"total":{"value":%d},"hits":[%s]}}`,
+		c.served, c.documents, strings.Join(hits, ","))
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func (c *scrollClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *scrollClient) DiscoverNodes() error {
+	return nil
+}
+
+func TestSearchMetadataScrollsAllDocuments(t *testing.T) {
+	client := &scrollClient{documents: 2*metadataSearchPageSize + 1}
+	provider := newRecoveryProvider(client)
+	prefixes, err := provider.getMetadataPrefixes(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, prefixes, 2*metadataSearchPageSize+1)
+	assert.Equal(t, "db2000", prefixes["db2000"])
+	assert.Equal(t, []string{fmt.Sprintf(`{"scroll_id":["scroll-%d"]}`, client.documents)}, client.cleared)
+}
@@ -0,0 +1,83 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const databasesCountTimeout = 10 * time.Second
+
+// databasesCollector reports the number of managed logical databases, i.e. metadata documents, on each scrape
+type databasesCollector struct {
+	provider *BaseProvider
+	desc     *prometheus.Desc
+}
+
+func NewDatabasesCollector(provider *BaseProvider) prometheus.Collector {
+	return &databasesCollector{
+		provider: provider,
+		desc: prometheus.NewDesc("dbaas_opensearch_logical_databases",
+			"Number of logical databases managed by the adapter", nil, nil),
+	}
+}
+
+func (c *databasesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *databasesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), databasesCountTimeout)
+	defer cancel()
+	count, err := c.provider.countDatabases(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to count logical databases", slog.Any("error", err))
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count))
+}
+
+func (bp BaseProvider) countDatabases(ctx context.Context) (int64, error) {
+	countRequest := opensearchapi.CountRequest{
+		Index: []string{DbaasMetadata},
+	}
+	response, err := countRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to count metadata documents: [%d] %s", response.StatusCode, response.String())
+	}
+	var result struct {
+		Count int64 `json:"count"`
+	}
+	if err = common.ProcessBody(response.Body, &result); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// countClient returns the given status code and body on count of metadata documents
+type countClient struct {
+	statusCode int
+	body       string
+}
+
+func (c *countClient) Perform(req *http.Request) (*http.Response, error) {
+	if req.URL.Path != "/dbaas_opensearch_metadata/_count" {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	}
+	return &http.Response{StatusCode: c.statusCode, Body: io.NopCloser(strings.NewReader(c.body))}, nil
+}
+
+func (c *countClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *countClient) DiscoverNodes() error {
+	return nil
+}
+
+func TestDatabasesCollector(t *testing.T) {
+	client := &countClient{statusCode: http.StatusOK, body: `{"count":7,"_shards":{"total":1,"successful":1}}`}
+	collector := NewDatabasesCollector(newRecoveryProvider(client))
+	expected := `# HELP dbaas_opensearch_logical_databases Number of logical databases managed by the adapter
+# TYPE dbaas_opensearch_logical_databases gauge
+dbaas_opensearch_logical_databases 7
+`
+	assert.Nil(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func TestCountDatabasesWithoutMetadataIndex(t *testing.T) {
+	provider := newRecoveryProvider(&countClient{statusCode: http.StatusNotFound, body: `{"error":"index_not_found_exception"}`})
+	count, err := provider.countDatabases(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	provider = newRecoveryProvider(&countClient{statusCode: http.StatusServiceUnavailable, body: `{}`})
+	_, err = provider.countDatabases(ctx)
+	assert.NotNil(t, err)
+}
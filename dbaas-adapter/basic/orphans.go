@@ -0,0 +1,401 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	orphanedPrefixReason   = "resource prefix does not have metadata document"
+	orphanedMetadataReason = "metadata document does not have any resource"
+)
+
+var (
+	// orphanKinds are kinds of resources which can be orphaned in the order they are reported
+	orphanKinds = []string{common.UserKind, common.MetadataKind, common.IndexKind, common.TemplateKind,
+		common.IndexTemplateKind, common.AliasKind, common.TenantKind}
+
+	orphanedResourcesGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dbaas_opensearch_orphaned_resources",
+		Help: "Number of orphaned resources found by the last check",
+	}, []string{"kind"})
+	collectedOrphansCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dbaas_opensearch_orphaned_resources_collected_total",
+		Help: "Number of orphaned resources deleted by garbage collection",
+	}, []string{"kind"})
+)
+
+// OrphanedResource is the resource which does not belong to any logical database
+type OrphanedResource struct {
+	Kind string `json:"-"`
+	Name string `json:"name"`
+	// Prefix is the resource prefix of the database the resource was created for
+	Prefix string `json:"prefix"`
+	Reason string `json:"reason"`
+	// DetectionTime is the time of the first check which found the resource orphaned
+	DetectionTime string `json:"detectionTime"`
+}
+
+// OrphansReport contains orphaned resources by their kinds and results of their deletion
+type OrphansReport struct {
+	CheckTime         string                        `json:"checkTime,omitempty"`
+	Orphans           map[string][]OrphanedResource `json:"orphans"`
+	Collected         []dao.DbResource              `json:"collected,omitempty"`
+	FailedCheckReason string                        `json:"failedCheckReason,omitempty"`
+}
+
+// OrphansCollector periodically finds resources which are left without logical databases, e.g. after failed creation
+// or partial drop, and deletes them if garbage collection is enabled.
+// Always use constructor NewOrphansCollector() to create new instance of the OrphansCollector.
+type OrphansCollector struct {
+	provider *BaseProvider
+	interval time.Duration
+	// gracePeriod is the time the resource must stay orphaned before deletion, it protects databases being created
+	gracePeriod       time.Duration
+	garbageCollection bool
+	lock              sync.Mutex
+	// detected contains detection times of orphaned resources by their kinds and names
+	detected map[string]time.Time
+	report   OrphansReport
+}
+
+func NewOrphansCollector(provider *BaseProvider, interval time.Duration, gracePeriod time.Duration, garbageCollection bool) *OrphansCollector {
+	return &OrphansCollector{
+		provider:          provider,
+		interval:          interval,
+		gracePeriod:       gracePeriod,
+		garbageCollection: garbageCollection,
+		detected:          make(map[string]time.Time),
+		report:            OrphansReport{Orphans: newOrphans()},
+	}
+}
+
+// Start runs checks of orphaned resources with the interval until the context is cancelled.
+// Checks are disabled for non-positive interval.
+func (oc *OrphansCollector) Start(ctx context.Context) {
+	if oc.interval <= 0 {
+		logger.Info("Checks of orphaned resources are disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(oc.interval)
+		defer ticker.Stop()
+		for {
+			checkCtx := context.WithValue(ctx, common.RequestIdKey, common.GenerateUUID())
+			if _, err := oc.Check(oc.garbageCollection, checkCtx); err != nil {
+				logger.ErrorContext(checkCtx, "Failed to check orphaned resources", slog.Any("error", err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// AuditHandler checks orphaned resources and returns them without deletion
+func (oc *OrphansCollector) AuditHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		logger.InfoContext(ctx, "Request to audit orphaned resources is received")
+		oc.processCheck(w, false, ctx)
+	}
+}
+
+// CollectHandler checks orphaned resources and deletes ones which are orphaned longer than the grace period
+func (oc *OrphansCollector) CollectHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		logger.InfoContext(ctx, "Request to collect orphaned resources is received")
+		oc.processCheck(w, true, ctx)
+	}
+}
+
+func (oc *OrphansCollector) processCheck(w http.ResponseWriter, collect bool, ctx context.Context) {
+	report, err := oc.Check(collect, ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to check orphaned resources", slog.Any("error", err))
+		common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+		return
+	}
+	responseBody, err := json.Marshal(report)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to serialize orphaned resources report", slog.Any("error", err))
+		common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+		return
+	}
+	common.ProcessResponseBody(ctx, w, responseBody, http.StatusOK)
+}
+
+func (oc *OrphansCollector) Report() OrphansReport {
+	oc.lock.Lock()
+	defer oc.lock.Unlock()
+	return oc.report
+}
+
+// Check finds orphaned resources, updates their detection times and metrics. If collect is true,
+// resources which are orphaned longer than the grace period are deleted.
+func (oc *OrphansCollector) Check(collect bool, ctx context.Context) (OrphansReport, error) {
+	oc.lock.Lock()
+	defer oc.lock.Unlock()
+	now := time.Now().UTC()
+	report := OrphansReport{CheckTime: now.Format(time.RFC3339), Orphans: newOrphans()}
+	orphans, err := oc.provider.findOrphans(ctx)
+	if err != nil {
+		report.FailedCheckReason = err.Error()
+		oc.report = report
+		return report, err
+	}
+	detected := make(map[string]time.Time)
+	var expired []dao.DbResource
+	for _, orphan := range orphans {
+		key := fmt.Sprintf("%s/%s", orphan.Kind, orphan.Name)
+		detectionTime, ok := oc.detected[key]
+		if !ok {
+			detectionTime = now
+		}
+		detected[key] = detectionTime
+		orphan.DetectionTime = detectionTime.Format(time.RFC3339)
+		report.Orphans[orphan.Kind] = append(report.Orphans[orphan.Kind], orphan)
+		if now.Sub(detectionTime) >= oc.gracePeriod {
+			expired = append(expired, dao.DbResource{Kind: orphan.Kind, Name: orphan.Name})
+		}
+	}
+	oc.detected = detected
+	for _, kind := range orphanKinds {
+		orphanedResourcesGauge.WithLabelValues(kind).Set(float64(len(report.Orphans[kind])))
+	}
+	if len(orphans) > 0 {
+		logger.WarnContext(ctx, fmt.Sprintf("%d orphaned resources are found, %d of them are orphaned longer than %s",
+			len(orphans), len(expired), oc.gracePeriod))
+	}
+	if collect && len(expired) > 0 {
+		report.Collected = oc.provider.deleteResources(expired, ctx)
+		for _, resource := range report.Collected {
+			if resource.Status == DeletedStatus {
+				delete(oc.detected, fmt.Sprintf("%s/%s", resource.Kind, resource.Name))
+				collectedOrphansCounter.WithLabelValues(resource.Kind).Inc()
+			}
+		}
+		logger.InfoContext(ctx, fmt.Sprintf("Orphaned resources are collected: %+v", report.Collected))
+	}
+	oc.report = report
+	return report, nil
+}
+
+// findOrphans receives resources of all databases from OpenSearch and cross-references them with metadata documents
+func (bp BaseProvider) findOrphans(ctx context.Context) ([]OrphanedResource, error) {
+	metadataPrefixes, err := bp.getMetadataPrefixes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := bp.getUsers()
+	if err != nil {
+		return nil, err
+	}
+	indices, err := bp.describeIndices("*", ctx)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := bp.getTemplateNames("*", ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexTemplates, err := bp.getIndexTemplateNames("*", ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := bp.getAliasNames("*", ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenants, err := bp.getTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := make(map[string][]string)
+	for _, index := range indices {
+		// Service indices of the adapter do not belong to any database
+		if index.Name != DbaasMetadata && index.Name != BulkDropOperations {
+			resources[common.IndexKind] = append(resources[common.IndexKind], index.Name)
+		}
+	}
+	resources[common.TemplateKind] = withoutHidden(templates)
+	resources[common.IndexTemplateKind] = withoutHidden(indexTemplates)
+	resources[common.AliasKind] = withoutHidden(aliases)
+	for name, tenant := range tenants {
+		if !tenant.Reserved {
+			resources[common.TenantKind] = append(resources[common.TenantKind], name)
+		}
+	}
+	sort.Strings(resources[common.TenantKind])
+	return findOrphans(metadataPrefixes, users, resources), nil
+}
+
+// findOrphans returns users with resource prefixes which do not have metadata documents with resources starting
+// with these prefixes, and metadata documents without any resource starting with their prefixes.
+// Metadata documents are passed by identifiers with prefixes stored in them, the prefix is empty for documents
+// created before it is stored. Such documents are identified by the prefix or by the name of the index
+// starting with the prefix. Resources which do not belong to resource prefixes of users are not reported
+// because they can be created not by the adapter.
+func findOrphans(metadataPrefixes map[string]string, users map[string]User, resources map[string][]string) []OrphanedResource {
+	ids := make([]string, 0, len(metadataPrefixes))
+	known := make(map[string]bool)
+	for id, prefix := range metadataPrefixes {
+		ids = append(ids, id)
+		known[id] = true
+		if prefix != "" {
+			known[prefix] = true
+		}
+	}
+	sort.Strings(ids)
+	userPrefixes := make(map[string]bool)
+	for _, user := range users {
+		userPrefixes[user.Attributes[resourcePrefixAttributeName]] = true
+	}
+	// isKnownPrefix also matches documents without stored prefixes by the name of the index,
+	// if the identifier is not the prefix of another database
+	isKnownPrefix := func(prefix string) bool {
+		if known[prefix] {
+			return true
+		}
+		for _, id := range ids {
+			if metadataPrefixes[id] == "" && !userPrefixes[id] && strings.HasPrefix(id, prefix+"_") {
+				return true
+			}
+		}
+		return false
+	}
+	belongsTo := func(name string, kind string, prefixes map[string]bool) string {
+		if kind == common.TenantKind {
+			if prefixes[name] {
+				return name
+			}
+			return ""
+		}
+		var longest string
+		for prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) && len(prefix) > len(longest) {
+				longest = prefix
+			}
+		}
+		return longest
+	}
+
+	var orphans []OrphanedResource
+	usernames := make([]string, 0, len(users))
+	for username := range users {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	orphanedPrefixes := make(map[string]bool)
+	for _, username := range usernames {
+		prefix := users[username].Attributes[resourcePrefixAttributeName]
+		if prefix == "" || isKnownPrefix(prefix) {
+			continue
+		}
+		orphanedPrefixes[prefix] = true
+		orphans = append(orphans, OrphanedResource{Kind: common.UserKind, Name: username, Prefix: prefix, Reason: orphanedPrefixReason})
+	}
+
+	var metadataOrphans []OrphanedResource
+	for _, id := range ids {
+		prefix := metadataPrefixes[id]
+		if prefix == "" {
+			prefix = id
+		}
+		owned := false
+		for _, username := range usernames {
+			userPrefix := users[username].Attributes[resourcePrefixAttributeName]
+			if userPrefix == prefix || username == prefix || strings.HasPrefix(username, prefix+"_") ||
+				userPrefix != "" && !userPrefixes[id] && strings.HasPrefix(id, userPrefix+"_") {
+				owned = true
+				break
+			}
+		}
+		for kind, names := range resources {
+			for _, name := range names {
+				if owned {
+					break
+				}
+				owned = belongsTo(name, kind, map[string]bool{id: true, prefix: true}) != ""
+			}
+		}
+		if !owned {
+			metadataOrphans = append(metadataOrphans, OrphanedResource{Kind: common.MetadataKind, Name: id, Prefix: prefix, Reason: orphanedMetadataReason})
+		}
+	}
+	orphans = append(orphans, metadataOrphans...)
+
+	for _, kind := range orphanKinds {
+		for _, name := range resources[kind] {
+			if belongsTo(name, kind, known) != "" {
+				continue
+			}
+			if prefix := belongsTo(name, kind, orphanedPrefixes); prefix != "" {
+				orphans = append(orphans, OrphanedResource{Kind: kind, Name: name, Prefix: prefix, Reason: orphanedPrefixReason})
+			}
+		}
+	}
+	return orphans
+}
+
+// getMetadataPrefixes returns identifiers of all metadata documents which are names or resource prefixes of databases
+// with resource prefixes stored in these documents
+func (bp BaseProvider) getMetadataPrefixes(ctx context.Context) (map[string]string, error) {
+	documents, err := bp.searchMetadata(`{"query":{"match_all":{}}}`, []string{resourcePrefixMetadataKey}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := make(map[string]string, len(documents))
+	for _, document := range documents {
+		prefixes[document.ID] = common.ConvertAnyToString(document.Source[resourcePrefixMetadataKey])
+	}
+	return prefixes, nil
+}
+
+func newOrphans() map[string][]OrphanedResource {
+	orphans := make(map[string][]OrphanedResource)
+	for _, kind := range orphanKinds {
+		orphans[kind] = make([]OrphanedResource, 0)
+	}
+	return orphans
+}
+
+// withoutHidden skips system resources which names start with dot
+func withoutHidden(names []string) []string {
+	var result []string
+	for _, name := range names {
+		if !strings.HasPrefix(name, ".") {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"encoding/json"
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// orphansClient contains 'orders' database with all resources, 'invoices' database which metadata is stored
+// by the name of its index, 'payments' users and index without metadata and 'archive' metadata without resources
+type orphansClient struct {
+	lock    sync.Mutex
+	deleted []string
+	// metadataStatus and metadata override the response of the search of metadata documents
+	metadataStatus int
+	metadata       string
+}
+
+func (c *orphansClient) Perform(req *http.Request) (*http.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	path := req.URL.Path
+	statusCode := http.StatusOK
+	body := "{}"
+	switch {
+	case req.Method == http.MethodDelete:
+		c.deleted = append(c.deleted, path)
+		body = `{"acknowledged":true}`
+	case path == "/dbaas_opensearch_metadata/_search" && c.metadataStatus != 0:
+		statusCode = c.metadataStatus
+		body = c.metadata
+	case path == "/dbaas_opensearch_metadata/_search":
+		body = `{"hits":{"hits":[{"_id":"orders","_source":{"resourcePrefix":"orders"}},{"_id":"archive"},
+{"_id":"invoices_main","_source":{"resourcePrefix":"invoices"}}]}}`
+	case path == "/_plugins/_security/api/internalusers":
+		body = `{"orders_a1":{"hash":"","attributes":{"resource_prefix":"orders"}},
+"payments_c3":{"hash":"","attributes":{"resource_prefix":"payments"}},
+"invoices_e5":{"hash":"","attributes":{"resource_prefix":"invoices"}},
+"admin":{"hash":"","attributes":{}}}`
+	case strings.HasPrefix(path, "/_cat/indices/"):
+		body = `[{"index":"orders_items"},{"index":"payments_items"},{"index":"invoices_main"},{"index":"logs"},{"index":".kibana"},
+{"index":"dbaas_opensearch_metadata"},{"index":"dbaas_opensearch_bulk_drop_operations"}]`
+	case strings.HasPrefix(path, "/_template/"):
+		body = `{"payments_template":{},".monitoring":{}}`
+	case strings.HasPrefix(path, "/_index_template/"):
+		statusCode = http.StatusNotFound
+	case strings.HasPrefix(path, "/_alias/"):
+		body = `{"orders_items":{"aliases":{"orders_alias":{}}}}`
+	case path == "/_plugins/_security/api/tenants/":
+		body = `{"global_tenant":{"reserved":true},"payments":{"reserved":false},"orders":{"reserved":false}}`
+	}
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func (c *orphansClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *orphansClient) DiscoverNodes() error {
+	return nil
+}
+
+func TestFindOrphans(t *testing.T) {
+	users := map[string]User{
+		"orders_a1":       {Attributes: map[string]string{resourcePrefixAttributeName: "orders"}},
+		"payments_c3":     {Attributes: map[string]string{resourcePrefixAttributeName: "payments"}},
+		"invoices_e5":     {Attributes: map[string]string{resourcePrefixAttributeName: "invoices"}},
+		"billing_f6":      {Attributes: map[string]string{resourcePrefixAttributeName: "billing"}},
+		"payments_ext_d4": {Attributes: map[string]string{resourcePrefixAttributeName: "payments_ext"}},
+		"legacy":          {},
+	}
+	resources := map[string][]string{
+		common.IndexKind:  {"orders_items", "payments_items", "payments_ext_items", "invoices_main", "billing_main", "unknown"},
+		common.TenantKind: {"payments_ext", "payments", "invoices"},
+	}
+	metadataPrefixes := map[string]string{
+		"archive":       "",
+		"orders":        "",
+		"payments_ext":  "",
+		"invoices_main": "invoices",
+		"billing_main":  "",
+	}
+	orphans := findOrphans(metadataPrefixes, users, resources)
+	expected := []OrphanedResource{
+		{Kind: common.UserKind, Name: "payments_c3", Prefix: "payments", Reason: orphanedPrefixReason},
+		{Kind: common.MetadataKind, Name: "archive", Prefix: "archive", Reason: orphanedMetadataReason},
+		{Kind: common.IndexKind, Name: "payments_items", Prefix: "payments", Reason: orphanedPrefixReason},
+		{Kind: common.TenantKind, Name: "payments", Prefix: "payments", Reason: orphanedPrefixReason},
+	}
+	assert.Equal(t, expected, orphans)
+}
+
+func TestOrphansCollectorRespectsGracePeriod(t *testing.T) {
+	client := &orphansClient{}
+	collector := NewOrphansCollector(newRecoveryProvider(client), 0, time.Hour, true)
+	report, err := collector.Check(true, ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, report.FailedCheckReason)
+	assert.Empty(t, report.Collected)
+	assert.Empty(t, client.deleted)
+	names := func(orphans []OrphanedResource) []string {
+		var result []string
+		for _, orphan := range orphans {
+			result = append(result, orphan.Name)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"payments_c3"}, names(report.Orphans[common.UserKind]))
+	assert.Equal(t, []string{"archive"}, names(report.Orphans[common.MetadataKind]))
+	assert.Equal(t, []string{"payments_items"}, names(report.Orphans[common.IndexKind]))
+	assert.Equal(t, []string{"payments_template"}, names(report.Orphans[common.TemplateKind]))
+	assert.Equal(t, []string{"payments"}, names(report.Orphans[common.TenantKind]))
+	assert.Empty(t, report.Orphans[common.AliasKind])
+	assert.Empty(t, report.Orphans[common.IndexTemplateKind])
+
+	detectionTime := time.Now().UTC().Add(-2 * time.Hour)
+	for key := range collector.detected {
+		collector.detected[key] = detectionTime
+	}
+	collector.detected[common.UserKind+"/payments_c3"] = time.Now().UTC()
+	report, err = collector.Check(true, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, detectionTime.Format(time.RFC3339), report.Orphans[common.IndexKind][0].DetectionTime)
+	expectedCollected := []dao.DbResource{
+		{Kind: common.IndexKind, Name: "payments_items", Status: DeletedStatus},
+		{Kind: common.MetadataKind, Name: "archive", Status: DeletedStatus},
+		{Kind: common.TemplateKind, Name: "payments_template", Status: DeletedStatus},
+		{Kind: common.TenantKind, Name: "payments", Status: DeletedStatus},
+	}
+	assert.ElementsMatch(t, expectedCollected, report.Collected)
+	assert.Len(t, collector.detected, 1)
+	assert.Contains(t, collector.detected, common.UserKind+"/payments_c3")
+	assert.Equal(t, report, collector.Report())
+}
+
+func TestOrphansCollectorAbortsWithIncompleteMetadata(t *testing.T) {
+	for name, client := range map[string]*orphansClient{
+		"missing index": {metadataStatus: http.StatusNotFound, metadata: `{"error":"index_not_found_exception"}`},
+		"partial result": {metadataStatus: http.StatusOK,
+			metadata: `{"hits":{"total":{"value":2},"hits":[{"_id":"orders","_source":{"resourcePrefix":"orders"}}]}}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			collector := NewOrphansCollector(newRecoveryProvider(client), 0, 0, true)
+			report, err := collector.Check(true, ctx)
+			assert.NotNil(t, err)
+			assert.NotEmpty(t, report.FailedCheckReason)
+			assert.Empty(t, report.Collected)
+			assert.Empty(t, client.deleted)
+		})
+	}
+}
+
+func TestOrphansAuditHandlerDoesNotDeleteResources(t *testing.T) {
+	client := &orphansClient{}
+	collector := NewOrphansCollector(newRecoveryProvider(client), 0, 0, true)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/orphans", nil)
+	collector.AuditHandler()(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var report OrphansReport
+	err := json.Unmarshal(recorder.Body.Bytes(), &report)
+	assert.Nil(t, err)
+	assert.Len(t, report.Orphans[common.UserKind], 1)
+	assert.Empty(t, report.Collected)
+	assert.Empty(t, client.deleted)
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, "/orphans/gc", nil)
+	collector.CollectHandler()(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	err = json.Unmarshal(recorder.Body.Bytes(), &report)
+	assert.Nil(t, err)
+	assert.Len(t, report.Collected, 5)
+	assert.NotEmpty(t, client.deleted)
+}
@@ -0,0 +1,321 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/gorilla/mux"
+)
+
+const passwordRotationMetadataKey = "passwordRotation"
+
+var (
+	errPasswordRotationNotFound   = errors.New("password rotation is not found")
+	errPasswordRotationInProgress = errors.New("password rotation is in progress")
+)
+
+type PasswordRotationRequest struct {
+	// Grace enables temporary users, passwords of users of the database are rotated only after confirmation
+	Grace bool `json:"grace,omitempty"`
+}
+
+type PasswordRotationResponse struct {
+	ConnectionProperties          []common.ConnectionProperties `json:"connectionProperties"`
+	TemporaryConnectionProperties []common.ConnectionProperties `json:"temporaryConnectionProperties,omitempty"`
+}
+
+// PasswordRotationConfirmation contains new connection properties of users of the database after the first confirmation
+// and deleted temporary users after the second one
+type PasswordRotationConfirmation struct {
+	ConnectionProperties []common.ConnectionProperties `json:"connectionProperties,omitempty"`
+	DeletedUsers         []string                      `json:"deletedUsers,omitempty"`
+}
+
+// PasswordRotation is stored in the metadata document of the database until temporary users of the rotation with grace are deleted
+type PasswordRotation struct {
+	Time             string   `json:"time"`
+	TemporaryUsers   []string `json:"temporaryUsers"`
+	PasswordsRotated bool     `json:"passwordsRotated,omitempty"`
+}
+
+// RotatePasswordsHandler generates new passwords for all users of the database with the resource prefix
+func (bp BaseProvider) RotatePasswordsHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		prefix := mux.Vars(r)["dbName"]
+		logger.InfoContext(ctx, fmt.Sprintf("Request to rotate passwords of '%s' database is received", prefix))
+		var request PasswordRotationRequest
+		decoder := json.NewDecoder(r.Body)
+		if err := decoder.Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+			logger.ErrorContext(ctx, "Failed to decode request in rotate passwords handler", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusBadRequest)
+			return
+		}
+		defer func() { _ = r.Body.Close() }()
+		response, err := bp.rotatePasswords(prefix, request, ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to rotate passwords of '%s' database", prefix), slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), getPasswordRotationErrorStatus(err))
+			return
+		}
+		responseBody, err := json.Marshal(response)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to serialize response in rotate passwords handler", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		common.ProcessResponseBody(ctx, w, responseBody, http.StatusOK)
+	}
+}
+
+// ConfirmPasswordRotationHandler rotates passwords of users of the database on the first confirmation
+// of the rotation with grace and deletes temporary users on the second one
+func (bp BaseProvider) ConfirmPasswordRotationHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		prefix := mux.Vars(r)["dbName"]
+		logger.InfoContext(ctx, fmt.Sprintf("Request to confirm password rotation of '%s' database is received", prefix))
+		response, err := bp.confirmPasswordRotation(prefix, ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to confirm password rotation of '%s' database", prefix), slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), getPasswordRotationErrorStatus(err))
+			return
+		}
+		responseBody, err := json.Marshal(response)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to serialize response in confirm password rotation handler", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		common.ProcessResponseBody(ctx, w, responseBody, http.StatusOK)
+	}
+}
+
+func getPasswordRotationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errPasswordRotationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errPasswordRotationInProgress):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// rotatePasswords changes passwords of all users of the database in one request to the security plugin,
+// so either all users receive new passwords or none of them. With grace, only temporary users with the same
+// backend roles are created, so consumers can switch to them before passwords are rotated on confirmation.
+func (bp BaseProvider) rotatePasswords(prefix string, request PasswordRotationRequest, ctx context.Context) (*PasswordRotationResponse, error) {
+	if err := checkForbiddenSymbolPrefix(prefix); err != nil || prefix == "" {
+		return nil, fmt.Errorf("%w: database prefix '%s' is not valid", errPasswordRotationNotFound, prefix)
+	}
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+	metadata, err := bp.GetMetadata(prefix, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pending, ok := metadata[passwordRotationMetadataKey]; ok && pending != nil {
+		return nil, fmt.Errorf("%w: rotation of '%s' database with grace must be confirmed first", errPasswordRotationInProgress, prefix)
+	}
+	users, err := bp.getUsers()
+	if err != nil {
+		return nil, err
+	}
+	descriptions := bp.describeUsers(prefix, users, nil)
+	if len(descriptions) == 0 {
+		return nil, fmt.Errorf("%w: '%s' database does not have users", errPasswordRotationNotFound, prefix)
+	}
+	if !request.Grace {
+		connectionProperties, err := bp.rotateUserPasswords(prefix, descriptions, users, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &PasswordRotationResponse{ConnectionProperties: connectionProperties}, nil
+	}
+
+	response := &PasswordRotationResponse{ConnectionProperties: make([]common.ConnectionProperties, 0)}
+	var temporaryUsers []string
+	var temporaryChanges []Change
+	for _, description := range descriptions {
+		username := fmt.Sprintf("%s_%s", prefix, common.GenerateUUID())
+		change, password, err := bp.getPasswordChange(username, users[description.Username])
+		if err != nil {
+			return nil, err
+		}
+		temporaryChanges = append(temporaryChanges, change)
+		temporaryUsers = append(temporaryUsers, username)
+		response.TemporaryConnectionProperties = append(response.TemporaryConnectionProperties,
+			bp.GetExtendedConnectionProperties("", username, password, prefix, description.RoleType))
+	}
+	if err = bp.patchUsers(temporaryChanges, ctx); err != nil {
+		return nil, fmt.Errorf("failed to create temporary users of '%s' database: %w", prefix, err)
+	}
+	logger.InfoContext(ctx, fmt.Sprintf("Temporary users %v of '%s' database are created", temporaryUsers, prefix))
+	rotation := PasswordRotation{Time: time.Now().UTC().Format(time.RFC3339), TemporaryUsers: temporaryUsers}
+	if err = bp.recordPasswordRotation(prefix, metadata, &rotation, ctx); err != nil {
+		return nil, errors.Join(err, bp.deleteTemporaryUsers(temporaryUsers, ctx))
+	}
+	return response, nil
+}
+
+// confirmPasswordRotation moves the rotation with grace to the next phase. Passwords are rotated on the first confirmation,
+// if the rotation is not recorded, the next confirmation rotates passwords again while consumers still use temporary users.
+func (bp BaseProvider) confirmPasswordRotation(prefix string, ctx context.Context) (*PasswordRotationConfirmation, error) {
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+	metadata, err := bp.GetMetadata(prefix, ctx)
+	if err != nil {
+		return nil, err
+	}
+	rotation, err := getPasswordRotation(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if rotation == nil {
+		return nil, fmt.Errorf("%w: '%s' database does not have password rotation to confirm", errPasswordRotationNotFound, prefix)
+	}
+	if rotation.PasswordsRotated {
+		if err = bp.deleteTemporaryUsers(rotation.TemporaryUsers, ctx); err != nil {
+			return nil, err
+		}
+		if err = bp.recordPasswordRotation(prefix, metadata, nil, ctx); err != nil {
+			return nil, err
+		}
+		return &PasswordRotationConfirmation{DeletedUsers: rotation.TemporaryUsers}, nil
+	}
+
+	users, err := bp.getUsers()
+	if err != nil {
+		return nil, err
+	}
+	descriptions := bp.describeUsers(prefix, users, rotation.TemporaryUsers)
+	if len(descriptions) == 0 {
+		return nil, fmt.Errorf("%w: '%s' database does not have users", errPasswordRotationNotFound, prefix)
+	}
+	connectionProperties, err := bp.rotateUserPasswords(prefix, descriptions, users, ctx)
+	if err != nil {
+		return nil, err
+	}
+	rotation.PasswordsRotated = true
+	if err = bp.recordPasswordRotation(prefix, metadata, rotation, ctx); err != nil {
+		return nil, err
+	}
+	return &PasswordRotationConfirmation{ConnectionProperties: connectionProperties}, nil
+}
+
+// rotateUserPasswords changes passwords of the described users in one request to the security plugin
+func (bp BaseProvider) rotateUserPasswords(prefix string, descriptions []UserDescription, users map[string]User,
+	ctx context.Context) ([]common.ConnectionProperties, error) {
+	connectionProperties := make([]common.ConnectionProperties, 0, len(descriptions))
+	var changes []Change
+	for _, description := range descriptions {
+		change, password, err := bp.getPasswordChange(description.Username, users[description.Username])
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+		connectionProperties = append(connectionProperties,
+			bp.GetExtendedConnectionProperties("", description.Username, password, prefix, description.RoleType))
+	}
+	if err := bp.patchUsers(changes, ctx); err != nil {
+		return nil, fmt.Errorf("failed to rotate passwords of '%s' database: %w", prefix, err)
+	}
+	logger.InfoContext(ctx, fmt.Sprintf("Passwords of %d users of '%s' database are rotated", len(changes), prefix))
+	return connectionProperties, nil
+}
+
+// getPasswordRotation returns the rotation with grace stored in the metadata document or nil if there is no rotation
+func getPasswordRotation(metadata map[string]interface{}) (*PasswordRotation, error) {
+	pending, ok := metadata[passwordRotationMetadataKey]
+	if !ok || pending == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return nil, err
+	}
+	var rotation PasswordRotation
+	if err = json.Unmarshal(data, &rotation); err != nil {
+		return nil, err
+	}
+	return &rotation, nil
+}
+
+// getTemporaryUsers returns temporary users of the rotation with grace stored in the metadata document
+func getTemporaryUsers(metadata map[string]interface{}) ([]string, error) {
+	rotation, err := getPasswordRotation(metadata)
+	if err != nil || rotation == nil {
+		return nil, err
+	}
+	return rotation.TemporaryUsers, nil
+}
+
+// getPasswordChange returns the change which replaces the user with the same attributes and backend roles
+// and new generated password
+func (bp BaseProvider) getPasswordChange(username string, user User) (Change, string, error) {
+	password, err := bp.passwordGenerator.Generate()
+	if err != nil {
+		return Change{}, "", fmt.Errorf("cannot generate password for '%s' user: %w", username, err)
+	}
+	change := Change{
+		Operation: "add",
+		Path:      fmt.Sprintf("/%s", username),
+		Value: Content{
+			Attributes:   user.Attributes,
+			BackendRoles: user.Roles,
+			Password:     password,
+		},
+	}
+	return change, password, nil
+}
+
+func (bp BaseProvider) deleteTemporaryUsers(usernames []string, ctx context.Context) error {
+	var errs []error
+	for _, username := range usernames {
+		if err := bp.deleteUser(username, ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete temporary user '%s': %w", username, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// recordPasswordRotation stores the rotation in the metadata document or removes it if the rotation is nil
+func (bp BaseProvider) recordPasswordRotation(prefix string, metadata map[string]interface{}, rotation *PasswordRotation,
+	ctx context.Context) error {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	if rotation == nil {
+		delete(metadata, passwordRotationMetadataKey)
+	} else {
+		rotationMap, err := common.ConvertStructToMap(rotation)
+		if err != nil {
+			return err
+		}
+		metadata[passwordRotationMetadataKey] = rotationMap
+	}
+	_, err := bp.CreateMetadata(prefix, metadata, ctx)
+	return err
+}
@@ -0,0 +1,194 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rotationClient serves users of `orders` database and records bodies of modifying requests,
+// the rotation with grace is pending and 'orders_t1' temporary user exists if metadata contains it
+type rotationClient struct {
+	lock     sync.Mutex
+	metadata string
+	requests map[string][]string
+}
+
+func newRotationClient(metadata string) *rotationClient {
+	return &rotationClient{metadata: metadata, requests: make(map[string][]string)}
+}
+
+func (c *rotationClient) Perform(req *http.Request) (*http.Response, error) {
+	path := req.URL.Path
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if req.Method != http.MethodGet {
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		c.requests[req.Method+" "+path] = append(c.requests[req.Method+" "+path], string(body))
+	}
+	statusCode := http.StatusOK
+	body := `{"status":"OK"}`
+	switch {
+	case req.Method != http.MethodGet && strings.HasPrefix(path, "/dbaas_opensearch_metadata/_doc/"):
+		statusCode = http.StatusCreated
+		body = `{"result":"updated"}`
+	case req.Method != http.MethodGet:
+	case path == "/_plugins/_security/api/internalusers":
+		body = `{"orders_a1":{"hash":"","backend_roles":["dbaas_readonly"],"attributes":{"resource_prefix":"orders"}},
+"orders_b2":{"hash":"","backend_roles":["dbaas_dml"],"attributes":{"resource_prefix":"orders"}},`
+		if strings.Contains(c.metadata, "orders_t1") {
+			body += `"orders_t1":{"hash":"","backend_roles":["dbaas_readonly"],"attributes":{"resource_prefix":"orders"}},`
+		}
+		body += `"payments_c3":{"hash":"","backend_roles":["dbaas_admin"],"attributes":{"resource_prefix":"payments"}}}`
+	case path == "/dbaas_opensearch_metadata/_doc/orders":
+		body = c.metadata
+	default:
+		statusCode = http.StatusNotFound
+		body = `{}`
+	}
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func (c *rotationClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *rotationClient) DiscoverNodes() error {
+	return nil
+}
+
+const ordersMetadata = `{"found":true,"_source":{"microserviceName":"orders-service"}}`
+
+func TestRotatePasswords(t *testing.T) {
+	client := newRotationClient(ordersMetadata)
+	provider := newRecoveryProvider(client)
+	response, err := provider.rotatePasswords("orders", PasswordRotationRequest{}, ctx)
+	assert.Nil(t, err)
+	assert.Len(t, response.ConnectionProperties, 2)
+	assert.Equal(t, "orders_a1", response.ConnectionProperties[0].Username)
+	assert.Equal(t, ReadOnlyRoleType, response.ConnectionProperties[0].Role)
+	assert.Equal(t, "orders", response.ConnectionProperties[0].ResourcePrefix)
+	assert.NotEmpty(t, response.ConnectionProperties[0].Password)
+	assert.Empty(t, response.TemporaryConnectionProperties)
+
+	patches := client.requests["PATCH /_plugins/_security/api/internalusers"]
+	assert.Len(t, patches, 1)
+	var changes []Change
+	err = json.Unmarshal([]byte(patches[0]), &changes)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "/orders_b2", changes[1].Path)
+	content := changes[1].Value.(map[string]interface{})
+	assert.Equal(t, []interface{}{"dbaas_dml"}, content["backend_roles"])
+	assert.Equal(t, response.ConnectionProperties[1].Password, content["password"])
+	assert.Empty(t, client.requests["PUT /dbaas_opensearch_metadata/_doc/orders"])
+}
+
+func TestRotatePasswordsWithGrace(t *testing.T) {
+	client := newRotationClient(ordersMetadata)
+	provider := newRecoveryProvider(client)
+	response, err := provider.rotatePasswords("orders", PasswordRotationRequest{Grace: true}, ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, response.ConnectionProperties)
+	assert.Len(t, response.TemporaryConnectionProperties, 2)
+	temporaryUser := response.TemporaryConnectionProperties[1]
+	assert.True(t, strings.HasPrefix(temporaryUser.Username, "orders_"))
+	assert.Equal(t, DmlRoleType, temporaryUser.Role)
+
+	patches := client.requests["PATCH /_plugins/_security/api/internalusers"]
+	assert.Len(t, patches, 1)
+	var changes []Change
+	err = json.Unmarshal([]byte(patches[0]), &changes)
+	assert.Nil(t, err)
+	assert.Equal(t, "/"+temporaryUser.Username, changes[1].Path)
+	assert.Equal(t, []interface{}{"dbaas_dml"}, changes[1].Value.(map[string]interface{})["backend_roles"])
+
+	var metadata map[string]interface{}
+	err = json.Unmarshal([]byte(client.requests["PUT /dbaas_opensearch_metadata/_doc/orders"][0]), &metadata)
+	assert.Nil(t, err)
+	assert.Equal(t, "orders-service", metadata["microserviceName"])
+	rotation := metadata[passwordRotationMetadataKey].(map[string]interface{})
+	assert.Len(t, rotation["temporaryUsers"], 2)
+	assert.Nil(t, rotation["passwordsRotated"])
+}
+
+func TestConfirmPasswordRotationRotatesPasswords(t *testing.T) {
+	client := newRotationClient(`{"found":true,"_source":{"microserviceName":"orders-service",
+"passwordRotation":{"time":"2025-01-01T00:00:00Z","temporaryUsers":["orders_t1"]}}}`)
+	provider := newRecoveryProvider(client)
+	_, err := provider.rotatePasswords("orders", PasswordRotationRequest{Grace: true}, ctx)
+	assert.ErrorIs(t, err, errPasswordRotationInProgress)
+
+	confirmation, err := provider.confirmPasswordRotation("orders", ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, confirmation.DeletedUsers)
+	assert.Len(t, confirmation.ConnectionProperties, 2)
+	assert.Equal(t, "orders_a1", confirmation.ConnectionProperties[0].Username)
+	assert.Equal(t, "orders_b2", confirmation.ConnectionProperties[1].Username)
+	assert.Empty(t, client.requests["DELETE /_plugins/_security/api/internalusers/orders_t1"])
+	var changes []Change
+	err = json.Unmarshal([]byte(client.requests["PATCH /_plugins/_security/api/internalusers"][0]), &changes)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 2)
+	assert.JSONEq(t, `{"microserviceName":"orders-service",
+"passwordRotation":{"time":"2025-01-01T00:00:00Z","temporaryUsers":["orders_t1"],"passwordsRotated":true}}`,
+		client.requests["PUT /dbaas_opensearch_metadata/_doc/orders"][0])
+
+	descriptions, err := provider.describeDatabases([]string{"orders"}, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []UserDescription{{Username: "orders_a1", RoleType: ReadOnlyRoleType}, {Username: "orders_b2", RoleType: DmlRoleType}},
+		descriptions["orders"].Users)
+}
+
+func TestConfirmPasswordRotationDeletesTemporaryUsers(t *testing.T) {
+	client := newRotationClient(`{"found":true,"_source":{"microserviceName":"orders-service",
+"passwordRotation":{"time":"2025-01-01T00:00:00Z","temporaryUsers":["orders_t1","orders_t2"],"passwordsRotated":true}}}`)
+	provider := newRecoveryProvider(client)
+	confirmation, err := provider.confirmPasswordRotation("orders", ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"orders_t1", "orders_t2"}, confirmation.DeletedUsers)
+	assert.Empty(t, confirmation.ConnectionProperties)
+	assert.Empty(t, client.requests["PATCH /_plugins/_security/api/internalusers"])
+	assert.Len(t, client.requests["DELETE /_plugins/_security/api/internalusers/orders_t1"], 1)
+	assert.Len(t, client.requests["DELETE /_plugins/_security/api/internalusers/orders_t2"], 1)
+	assert.JSONEq(t, `{"microserviceName":"orders-service"}`, client.requests["PUT /dbaas_opensearch_metadata/_doc/orders"][0])
+}
+
+func TestConfirmPasswordRotationHandlerWithoutRotation(t *testing.T) {
+	provider := newRecoveryProvider(newRotationClient(ordersMetadata))
+	request := httptest.NewRequest(http.MethodPost, "/databases/orders/password-rotation/confirm", nil)
+	request = mux.SetURLVars(request, map[string]string{"dbName": "orders"})
+	recorder := httptest.NewRecorder()
+	provider.ConfirmPasswordRotationHandler()(recorder, request)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	request = httptest.NewRequest(http.MethodPost, "/databases/unknown/password-rotation", strings.NewReader(""))
+	request = mux.SetURLVars(request, map[string]string{"dbName": "unknown"})
+	recorder = httptest.NewRecorder()
+	provider.RotatePasswordsHandler()(recorder, request)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
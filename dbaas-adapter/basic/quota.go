@@ -0,0 +1,461 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	quotaMetadataKey             = "quota"
+	quotaWriteBlockedMetadataKey = "quotaWriteBlocked"
+
+	MaxIndicesQuota          = "maxIndices"
+	MaxPrimaryShardsQuota    = "maxPrimaryShards"
+	MaxStoreSizeQuota        = "maxStoreSize"
+	MaxFieldsPerMappingQuota = "maxFieldsPerMapping"
+
+	writeBlockSetting = "index.blocks.write"
+)
+
+var (
+	quotaUsageGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dbaas_opensearch_quota_usage",
+		Help: "Usage of the quota by the logical database",
+	}, []string{"database", "quota"})
+	quotaLimitGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dbaas_opensearch_quota_limit",
+		Help: "Limit of the quota of the logical database",
+	}, []string{"database", "quota"})
+	quotaViolatedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dbaas_opensearch_quota_violated",
+		Help: "Whether the quota of the logical database is violated (1) or not (0)",
+	}, []string{"database", "quota"})
+	quotaWriteBlockedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dbaas_opensearch_quota_write_blocked",
+		Help: "Whether writes to indices of the logical database are blocked because of quota violation",
+	}, []string{"database"})
+)
+
+// Quota limits resources of the logical database with the resource prefix. Zero value means the limit is not set.
+type Quota struct {
+	MaxIndices       int64 `json:"maxIndices,omitempty"`
+	MaxPrimaryShards int64 `json:"maxPrimaryShards,omitempty"`
+	// MaxStoreSize is the maximum size of primary and replica shards of all indices in bytes
+	MaxStoreSize        int64 `json:"maxStoreSize,omitempty"`
+	MaxFieldsPerMapping int64 `json:"maxFieldsPerMapping,omitempty"`
+	// WriteBlock enables write block on indices of the database while any quota is violated
+	WriteBlock bool `json:"writeBlock,omitempty"`
+}
+
+type QuotaViolation struct {
+	Database string `json:"database"`
+	Quota    string `json:"quota"`
+	Limit    int64  `json:"limit"`
+	Usage    int64  `json:"usage"`
+	// Index is the index with the largest mapping, it is filled for `maxFieldsPerMapping` quota only
+	Index string `json:"index,omitempty"`
+}
+
+// QuotaReport contains results of the last quota check
+type QuotaReport struct {
+	CheckTime         string           `json:"checkTime,omitempty"`
+	Databases         int              `json:"databases"`
+	Violations        []QuotaViolation `json:"violations"`
+	WriteBlocked      []string         `json:"writeBlocked"`
+	FailedDatabases   []string         `json:"failedDatabases,omitempty"`
+	FailedCheckReason string           `json:"failedCheckReason,omitempty"`
+}
+
+type quotaUsage struct {
+	indices          int64
+	primaryShards    int64
+	storeSize        int64
+	fieldsPerMapping int64
+	largestMapping   string
+}
+
+type quotaDatabase struct {
+	prefix       string
+	metadataID   string
+	quota        Quota
+	writeBlocked bool
+}
+
+type catIndexShards struct {
+	Index     string  `json:"index"`
+	Pri       *string `json:"pri"`
+	StoreSize *string `json:"store.size"`
+}
+
+// QuotaChecker periodically compares usage of logical databases with their quotas.
+// Always use constructor NewQuotaChecker() to create new instance of the QuotaChecker.
+type QuotaChecker struct {
+	provider *BaseProvider
+	interval time.Duration
+	lock     sync.RWMutex
+	report   QuotaReport
+}
+
+func NewQuotaChecker(provider *BaseProvider, interval time.Duration) *QuotaChecker {
+	return &QuotaChecker{
+		provider: provider,
+		interval: interval,
+		report:   QuotaReport{Violations: make([]QuotaViolation, 0), WriteBlocked: make([]string, 0)},
+	}
+}
+
+// Start runs quota checks with the interval until the context is cancelled. Checks are disabled for non-positive interval.
+func (qc *QuotaChecker) Start(ctx context.Context) {
+	if qc.interval <= 0 {
+		logger.Info("Quota checks are disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(qc.interval)
+		defer ticker.Stop()
+		for {
+			checkCtx := context.WithValue(ctx, common.RequestIdKey, common.GenerateUUID())
+			if err := qc.Check(checkCtx); err != nil {
+				logger.ErrorContext(checkCtx, "Failed to check quotas of databases", slog.Any("error", err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// ViolationsHandler returns results of the last quota check
+func (qc *QuotaChecker) ViolationsHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		logger.InfoContext(ctx, "Request to get quota violations is received")
+		responseBody, err := json.Marshal(qc.Report())
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to serialize response in quota violations handler", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		common.ProcessResponseBody(ctx, w, responseBody, http.StatusOK)
+	}
+}
+
+func (qc *QuotaChecker) Report() QuotaReport {
+	qc.lock.RLock()
+	defer qc.lock.RUnlock()
+	return qc.report
+}
+
+// Check compares usage of all databases with quotas, updates metrics and write blocks of databases
+func (qc *QuotaChecker) Check(ctx context.Context) error {
+	report := QuotaReport{
+		CheckTime:    time.Now().UTC().Format(time.RFC3339),
+		Violations:   make([]QuotaViolation, 0),
+		WriteBlocked: make([]string, 0),
+	}
+	databases, err := qc.provider.getQuotaDatabases(ctx)
+	if err != nil {
+		report.FailedCheckReason = err.Error()
+		qc.setReport(report)
+		return err
+	}
+	report.Databases = len(databases)
+	quotaUsageGauge.Reset()
+	quotaLimitGauge.Reset()
+	quotaViolatedGauge.Reset()
+	quotaWriteBlockedGauge.Reset()
+	for _, database := range databases {
+		violations, writeBlocked, err := qc.provider.checkQuota(database, ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to check quota of '%s' database", database.prefix), slog.Any("error", err))
+			report.FailedDatabases = append(report.FailedDatabases, database.prefix)
+		}
+		report.Violations = append(report.Violations, violations...)
+		if writeBlocked {
+			report.WriteBlocked = append(report.WriteBlocked, database.prefix)
+			quotaWriteBlockedGauge.WithLabelValues(database.prefix).Set(1)
+		} else {
+			quotaWriteBlockedGauge.WithLabelValues(database.prefix).Set(0)
+		}
+	}
+	if len(report.Violations) > 0 {
+		logger.WarnContext(ctx, fmt.Sprintf("Quotas are violated: %+v", report.Violations))
+	}
+	qc.setReport(report)
+	return nil
+}
+
+func (qc *QuotaChecker) setReport(report QuotaReport) {
+	qc.lock.Lock()
+	defer qc.lock.Unlock()
+	qc.report = report
+}
+
+// checkQuota returns quota violations of the database and whether writes to the database are blocked after the check
+func (bp BaseProvider) checkQuota(database quotaDatabase, ctx context.Context) ([]QuotaViolation, bool, error) {
+	pattern := fmt.Sprintf("%s*", database.prefix)
+	usage, err := bp.getQuotaUsage(pattern, database.quota.MaxFieldsPerMapping > 0, ctx)
+	if err != nil {
+		return nil, database.writeBlocked, err
+	}
+	limits := []struct {
+		name  string
+		limit int64
+		usage int64
+	}{
+		{MaxIndicesQuota, database.quota.MaxIndices, usage.indices},
+		{MaxPrimaryShardsQuota, database.quota.MaxPrimaryShards, usage.primaryShards},
+		{MaxStoreSizeQuota, database.quota.MaxStoreSize, usage.storeSize},
+		{MaxFieldsPerMappingQuota, database.quota.MaxFieldsPerMapping, usage.fieldsPerMapping},
+	}
+	var violations []QuotaViolation
+	for _, limit := range limits {
+		if limit.limit <= 0 {
+			continue
+		}
+		quotaUsageGauge.WithLabelValues(database.prefix, limit.name).Set(float64(limit.usage))
+		quotaLimitGauge.WithLabelValues(database.prefix, limit.name).Set(float64(limit.limit))
+		if limit.usage <= limit.limit {
+			quotaViolatedGauge.WithLabelValues(database.prefix, limit.name).Set(0)
+			continue
+		}
+		quotaViolatedGauge.WithLabelValues(database.prefix, limit.name).Set(1)
+		violation := QuotaViolation{Database: database.prefix, Quota: limit.name, Limit: limit.limit, Usage: limit.usage}
+		if limit.name == MaxFieldsPerMappingQuota {
+			violation.Index = usage.largestMapping
+		}
+		violations = append(violations, violation)
+	}
+
+	// Write block is applied on each check while the quota is violated to cover indices created after the previous check
+	if database.quota.WriteBlock && len(violations) > 0 {
+		if err = bp.setWriteBlock(database, true, ctx); err != nil {
+			return violations, database.writeBlocked, err
+		}
+		return violations, true, nil
+	}
+	if database.writeBlocked && len(violations) == 0 {
+		if err = bp.setWriteBlock(database, false, ctx); err != nil {
+			return violations, true, err
+		}
+	}
+	return violations, false, nil
+}
+
+// getQuotaDatabases returns databases which have quota in the metadata
+func (bp BaseProvider) getQuotaDatabases(ctx context.Context) ([]quotaDatabase, error) {
+	query := fmt.Sprintf(`{"query":{"exists":{"field":"%s"}}}`, quotaMetadataKey)
+	documents, err := bp.searchMetadata(query, nil, ctx)
+	if errors.Is(err, errMetadataIndexNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search databases with quotas: %w", err)
+	}
+	databases := make([]quotaDatabase, 0, len(documents))
+	for _, hit := range documents {
+		quota, err := parseQuota(hit.Source[quotaMetadataKey])
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Quota of '%s' database cannot be parsed", hit.ID), slog.Any("error", err))
+			continue
+		}
+		writeBlocked, _ := hit.Source[quotaWriteBlockedMetadataKey].(bool)
+		// Metadata documents created before the prefix is stored have the prefix as ID
+		prefix := common.ConvertAnyToString(hit.Source[resourcePrefixMetadataKey])
+		if prefix == "" {
+			prefix = hit.ID
+		}
+		databases = append(databases, quotaDatabase{prefix: prefix, metadataID: hit.ID, quota: quota, writeBlocked: writeBlocked})
+	}
+	sort.Slice(databases, func(i, j int) bool {
+		return databases[i].prefix < databases[j].prefix
+	})
+	return databases, nil
+}
+
+func (bp BaseProvider) getQuotaUsage(pattern string, withMappings bool, ctx context.Context) (*quotaUsage, error) {
+	indicesRequest := opensearchapi.CatIndicesRequest{
+		Index:  []string{pattern},
+		Bytes:  "b",
+		Format: "json",
+		H:      []string{"index", "pri", "store.size"},
+	}
+	response, err := indicesRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return nil, fmt.Errorf("error occurred during retrieving indices by '%s' pattern: %+v", pattern, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	usage := &quotaUsage{}
+	if response.StatusCode == http.StatusNotFound {
+		return usage, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("during receiving indices by '%s' pattern error occurred: [%d]", pattern, response.StatusCode)
+	}
+	var catIndices []catIndexShards
+	if err = common.ProcessBody(response.Body, &catIndices); err != nil {
+		return nil, err
+	}
+	for _, index := range catIndices {
+		if strings.HasPrefix(index.Index, ".") {
+			continue
+		}
+		usage.indices++
+		if shards := parseCatNumber(index.Pri); shards != nil {
+			usage.primaryShards += *shards
+		}
+		if storeSize := parseCatNumber(index.StoreSize); storeSize != nil {
+			usage.storeSize += *storeSize
+		}
+	}
+	if withMappings && usage.indices > 0 {
+		usage.fieldsPerMapping, usage.largestMapping, err = bp.getLargestMapping(pattern, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return usage, nil
+}
+
+// getLargestMapping returns the number of fields in the largest mapping of indices and the name of its index
+func (bp BaseProvider) getLargestMapping(pattern string, ctx context.Context) (int64, string, error) {
+	mappingRequest := opensearchapi.IndicesGetMappingRequest{
+		Index: []string{pattern},
+	}
+	response, err := mappingRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return 0, "", fmt.Errorf("error occurred during retrieving mappings by '%s' pattern: %+v", pattern, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusNotFound {
+		return 0, "", nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return 0, "", fmt.Errorf("during receiving mappings by '%s' pattern error occurred: [%d]", pattern, response.StatusCode)
+	}
+	var mappings map[string]struct {
+		Mappings map[string]interface{} `json:"mappings"`
+	}
+	if err = common.ProcessBody(response.Body, &mappings); err != nil {
+		return 0, "", err
+	}
+	var largest int64
+	var largestIndex string
+	for index, mapping := range mappings {
+		if strings.HasPrefix(index, ".") {
+			continue
+		}
+		properties, _ := mapping.Mappings["properties"].(map[string]interface{})
+		fields := countMappingFields(properties)
+		if fields > largest || (fields == largest && index < largestIndex) {
+			largest = fields
+			largestIndex = index
+		}
+	}
+	return largest, largestIndex, nil
+}
+
+// countMappingFields counts fields the same way as `index.mapping.total_fields.limit` does,
+// including object fields and multi-fields
+func countMappingFields(properties map[string]interface{}) int64 {
+	var count int64
+	for _, value := range properties {
+		field, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		count++
+		if nested, ok := field["properties"].(map[string]interface{}); ok {
+			count += countMappingFields(nested)
+		}
+		if multiFields, ok := field["fields"].(map[string]interface{}); ok {
+			count += countMappingFields(multiFields)
+		}
+	}
+	return count
+}
+
+// setWriteBlock sets or removes write block on indices of the database and stores its state in the metadata document
+func (bp BaseProvider) setWriteBlock(database quotaDatabase, block bool, ctx context.Context) error {
+	var value interface{}
+	if block {
+		value = true
+	}
+	indices, err := bp.putIndexSettings(fmt.Sprintf("%s*", database.prefix), map[string]interface{}{writeBlockSetting: value}, ctx)
+	if err != nil {
+		return err
+	}
+	metadata, err := bp.GetMetadata(database.metadataID, ctx)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		return fmt.Errorf("metadata of '%s' database does not exist", database.prefix)
+	}
+	if blocked, _ := metadata[quotaWriteBlockedMetadataKey].(bool); blocked == block {
+		return nil
+	}
+	metadata[quotaWriteBlockedMetadataKey] = block
+	if _, err = bp.CreateMetadata(database.metadataID, metadata, ctx); err != nil {
+		return err
+	}
+	if block {
+		logger.WarnContext(ctx, fmt.Sprintf("Writes to %v indices of '%s' database are blocked because of quota violation", indices, database.prefix))
+	} else {
+		logger.InfoContext(ctx, fmt.Sprintf("Writes to %v indices of '%s' database are unblocked", indices, database.prefix))
+	}
+	return nil
+}
+
+func validateQuota(settings Settings) error {
+	quota := settings.Quota
+	if quota == nil {
+		return nil
+	}
+	if !settings.ResourcePrefix {
+		return fmt.Errorf("quota can be set only for databases with 'resourcePrefix' set to 'true'")
+	}
+	if quota.MaxIndices < 0 || quota.MaxPrimaryShards < 0 || quota.MaxStoreSize < 0 || quota.MaxFieldsPerMapping < 0 {
+		return fmt.Errorf("quota limits must not be negative: %+v", *quota)
+	}
+	return nil
+}
+
+func parseQuota(value interface{}) (Quota, error) {
+	var quota Quota
+	data, err := json.Marshal(value)
+	if err != nil {
+		return quota, err
+	}
+	err = json.Unmarshal(data, &quota)
+	return quota, err
+}
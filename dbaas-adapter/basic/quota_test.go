@@ -0,0 +1,200 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"encoding/json"
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// quotaClient serves `orders` database which violates its quota and which metadata document is stored by the name
+// of its index, and `payments` database which is blocked, but does not violate its quota anymore.
+// Not empty metadata overrides the response of metadata search
+type quotaClient struct {
+	lock     sync.Mutex
+	requests map[string]string
+	metadata string
+}
+
+func newQuotaClient() *quotaClient {
+	return &quotaClient{requests: make(map[string]string)}
+}
+
+func (c *quotaClient) Perform(req *http.Request) (*http.Response, error) {
+	path := req.URL.Path
+	if req.Method == http.MethodPut {
+		body, _ := io.ReadAll(req.Body)
+		c.lock.Lock()
+		c.requests[req.Method+" "+path] = string(body)
+		c.lock.Unlock()
+	}
+	statusCode := http.StatusOK
+	body := `{"acknowledged":true}`
+	switch {
+	case path == "/dbaas_opensearch_metadata/_search" && c.metadata != "":
+		body = c.metadata
+	case path == "/dbaas_opensearch_metadata/_search":
+		body = `{"hits":{"hits":[
+{"_id":"payments","_source":{"quota":{"maxStoreSize":10000,"writeBlock":true},"quotaWriteBlocked":true}},
+{"_id":"orders_main","_source":{"microserviceName":"orders-service","resourcePrefix":"orders","quota":{"maxIndices":1,"maxPrimaryShards":5,"maxFieldsPerMapping":3,"writeBlock":true}}}]}}`
+	case req.Method == http.MethodPut && path == "/dbaas_opensearch_metadata/_doc/failed":
+		statusCode = http.StatusInternalServerError
+		body = `{"error":"failed"}`
+	case req.Method == http.MethodPut && strings.HasPrefix(path, "/dbaas_opensearch_metadata/_doc/"):
+		statusCode = http.StatusCreated
+		body = `{"result":"updated"}`
+	case req.Method == http.MethodPut:
+	case path == "/_cat/indices/orders*":
+		body = `[{"index":"orders_items","pri":"1","store.size":"2048"},{"index":"orders_events","pri":"3","store.size":"1024"}]`
+	case path == "/_cat/indices/payments*":
+		body = `[{"index":"payments_items","pri":"1","store.size":"100"}]`
+	case path == "/orders*/_mapping":
+		body = `{"orders_items":{"mappings":{"properties":{"name":{"type":"text","fields":{"keyword":{"type":"keyword"}}},
+"address":{"properties":{"city":{"type":"keyword"}}}}}},"orders_events":{"mappings":{"properties":{"time":{"type":"date"}}}}}`
+	case path == "/dbaas_opensearch_metadata/_doc/orders_main":
+		body = `{"found":true,"_source":{"microserviceName":"orders-service","resourcePrefix":"orders","quota":{"maxIndices":1}}}`
+	case path == "/dbaas_opensearch_metadata/_doc/payments":
+		body = `{"found":true,"_source":{"quota":{"maxStoreSize":10000},"quotaWriteBlocked":true}}`
+	default:
+		statusCode = http.StatusNotFound
+		body = `{}`
+	}
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func (c *quotaClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *quotaClient) DiscoverNodes() error {
+	return nil
+}
+
+func TestCountMappingFields(t *testing.T) {
+	var properties map[string]interface{}
+	err := json.Unmarshal([]byte(`{"name":{"type":"text","fields":{"keyword":{"type":"keyword"}}},
+"address":{"properties":{"city":{"type":"keyword"},"geo":{"properties":{"lat":{"type":"float"}}}}}}`), &properties)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), countMappingFields(properties))
+	assert.Equal(t, int64(0), countMappingFields(nil))
+}
+
+func TestCheckQuotas(t *testing.T) {
+	client := newQuotaClient()
+	checker := NewQuotaChecker(newRecoveryProvider(client), time.Minute)
+	err := checker.Check(ctx)
+	assert.Nil(t, err)
+
+	report := checker.Report()
+	assert.Equal(t, 2, report.Databases)
+	assert.Empty(t, report.FailedDatabases)
+	expectedViolations := []QuotaViolation{
+		{Database: "orders", Quota: MaxIndicesQuota, Limit: 1, Usage: 2},
+		{Database: "orders", Quota: MaxFieldsPerMappingQuota, Limit: 3, Usage: 4, Index: "orders_items"},
+	}
+	assert.Equal(t, expectedViolations, report.Violations)
+	assert.Equal(t, []string{"orders"}, report.WriteBlocked)
+
+	assert.JSONEq(t, `{"index.blocks.write":true}`, client.requests["PUT /orders*/_settings"])
+	assert.JSONEq(t, `{"index.blocks.write":null}`, client.requests["PUT /payments*/_settings"])
+	var metadata map[string]interface{}
+	err = json.Unmarshal([]byte(client.requests["PUT /dbaas_opensearch_metadata/_doc/orders_main"]), &metadata)
+	assert.Nil(t, err)
+	assert.Equal(t, "orders-service", metadata["microserviceName"])
+	assert.Equal(t, true, metadata[quotaWriteBlockedMetadataKey])
+	err = json.Unmarshal([]byte(client.requests["PUT /dbaas_opensearch_metadata/_doc/payments"]), &metadata)
+	assert.Nil(t, err)
+	assert.Equal(t, false, metadata[quotaWriteBlockedMetadataKey])
+
+	assert.Equal(t, float64(4), testutil.ToFloat64(quotaUsageGauge.WithLabelValues("orders", MaxPrimaryShardsQuota)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(quotaViolatedGauge.WithLabelValues("orders", MaxPrimaryShardsQuota)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(quotaViolatedGauge.WithLabelValues("orders", MaxIndicesQuota)))
+	assert.Equal(t, float64(100), testutil.ToFloat64(quotaUsageGauge.WithLabelValues("payments", MaxStoreSizeQuota)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(quotaWriteBlockedGauge.WithLabelValues("orders")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(quotaWriteBlockedGauge.WithLabelValues("payments")))
+}
+
+func TestCheckQuotasFailsWithIncompleteMetadata(t *testing.T) {
+	client := newQuotaClient()
+	client.metadata = `{"hits":{"total":{"value":2},"hits":[
+{"_id":"payments","_source":{"quota":{"maxStoreSize":10000,"writeBlock":true},"quotaWriteBlocked":true}}]}}`
+	checker := NewQuotaChecker(newRecoveryProvider(client), time.Minute)
+	err := checker.Check(ctx)
+	assert.NotNil(t, err)
+	assert.Empty(t, client.requests)
+}
+
+func TestQuotaViolationsHandler(t *testing.T) {
+	checker := NewQuotaChecker(newRecoveryProvider(newQuotaClient()), time.Minute)
+	err := checker.Check(ctx)
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	checker.ViolationsHandler()(recorder, httptest.NewRequest(http.MethodGet, "/quotas/violations", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var report QuotaReport
+	err = json.Unmarshal(recorder.Body.Bytes(), &report)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, report.CheckTime)
+	assert.Len(t, report.Violations, 2)
+}
+
+func TestCreateDatabaseWithQuotaWithoutResourcePrefix(t *testing.T) {
+	requestOnCreateDb := DbCreateRequest{
+		Settings: Settings{
+			Quota: &Quota{MaxIndices: 10},
+		},
+	}
+	_, err := baseProvider.createDatabase(requestOnCreateDb, ctx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "quota can be set only")
+
+	requestOnCreateDb.Settings = Settings{ResourcePrefix: true, Quota: &Quota{MaxStoreSize: -1}}
+	_, err = bp.createDatabase(requestOnCreateDb, ctx)
+	assert.NotNil(t, err)
+}
+
+func TestCreateDatabaseStoresPrefixInMetadata(t *testing.T) {
+	client := newQuotaClient()
+	requestOnCreateDb := DbCreateRequest{
+		DbName:     "main",
+		NamePrefix: "orders",
+		Metadata:   map[string]interface{}{"microserviceName": "orders-service"},
+		Settings: Settings{
+			ResourcePrefix: true,
+			CreateOnly:     []string{common.IndexKind},
+			Quota:          &Quota{MaxIndices: 10},
+		},
+	}
+	_, err := newRecoveryProvider(client).createDatabase(requestOnCreateDb, ctx)
+	assert.Nil(t, err)
+	var metadata map[string]interface{}
+	err = json.Unmarshal([]byte(client.requests["PUT /dbaas_opensearch_metadata/_doc/orders_main"]), &metadata)
+	assert.Nil(t, err)
+	assert.Equal(t, "orders", metadata[resourcePrefixMetadataKey])
+
+	_, err = newRecoveryProvider(client).CreateMetadata("failed", metadata, ctx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "[500]")
+}
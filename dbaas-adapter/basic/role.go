@@ -59,8 +59,9 @@ const (
 )
 
 type Role struct {
-	ClusterPermissions []string          `json:"cluster_permissions,omitempty"`
-	IndexPermissions   []IndexPermission `json:"index_permissions"`
+	ClusterPermissions []string           `json:"cluster_permissions,omitempty"`
+	IndexPermissions   []IndexPermission  `json:"index_permissions"`
+	TenantPermissions  []TenantPermission `json:"tenant_permissions,omitempty"`
 }
 
 type IndexPermission struct {
@@ -69,14 +70,29 @@ type IndexPermission struct {
 }
 
 func (bp BaseProvider) GetSupportedRoleTypes() []string {
-	return []string{ReadOnlyRoleType, DmlRoleType, AdminRoleType, IsmRoleType}
+	roleTypes := make([]string, 0, len(bp.getRoleTypes()))
+	for _, roleType := range bp.getRoleTypes() {
+		roleTypes = append(roleTypes, roleType.Name)
+	}
+	return roleTypes
 }
 
+// DefineRoleType defines the role type by the name of the role, the backend role or the legacy role with prefix
+// in `<prefix>_<role type>_role` format. The longest matching role type is used, so role types can contain names of each other.
 func (bp BaseProvider) DefineRoleType(roleName string) string {
+	result := ""
+	name := strings.TrimSuffix(roleName, "_role")
 	for _, roleType := range bp.GetSupportedRoleTypes() {
-		if strings.Contains(roleName, roleType) {
+		if roleName == roleType || roleName == fmt.Sprintf(BackendRolePattern, roleType) ||
+			roleName == fmt.Sprintf(common.RoleNamePattern, roleType) {
 			return roleType
 		}
+		if strings.HasSuffix(name, fmt.Sprintf("_%s", roleType)) && len(roleType) > len(result) {
+			result = roleType
+		}
+	}
+	if result != "" {
+		return result
 	}
 	return AdminRoleType
 }
@@ -86,89 +102,33 @@ func (bp BaseProvider) GetBackendRoles(roleType string) []string {
 }
 
 func (bp BaseProvider) CreateRoleWithISMPermissions() error {
-	clusterPermissions := []string{
-		ClusterAdminIsmPermissions,
-	}
-	indexGlobalPermissions := []string{
-		IndicesIsmManagedIndexPermission,
-	}
-	indexGlobalPermissions = append(indexGlobalPermissions,
-		IndicesMonitorStatsPermission,
-		IndicesRolloverPermission,
-		IndicesDeletePermission)
-	return bp.createRole(clusterPermissions, []string{}, indexGlobalPermissions, IsmRoleType)
+	return bp.CreateRoleType(bp.getRoleType(IsmRoleType))
 }
 
 func (bp BaseProvider) CreateRoleWithAdminPermissions() error {
-	indexPermissions := []string{
-		IndicesAllActionPermission,
-		strings.ToUpper(IndicesAllActionPermission),
-	}
-	clusterPermissions := []string{
-		ClusterReadWritePermissions,
-		strings.ToUpper(ClusterReadWritePermissions),
-		ClusterMonitorMainPermission,
-		ClusterMonitorHealthPermission,
-		ClusterMonitorTaskPermissions,
-		ClusterMonitorStatePermission,
-		ClusterScrollClearPermission,
-		ClusterManageIndexTemplatesPermissions,
-		ClusterManageTemplatePermissions,
-		ClusterManageIndexTemplatePermissions,
-	}
-	// IndexGlobalPermissions `indices:admin/resize` permission required for clone index and should be removed after fix https://github.com/opensearch-project/security/issues/429
-	indexGlobalPermissions := []string{
-		ClusterManageIndexTemplatePermissions,
-		ClusterManageAliasesPermissions,
-		"indices:admin/resize",
-		IndicesAdminRefreshPermission,
-	}
-	return bp.createRole(clusterPermissions, indexPermissions, indexGlobalPermissions, AdminRoleType)
+	return bp.CreateRoleType(bp.getRoleType(AdminRoleType))
 }
 
 func (bp BaseProvider) CreateRoleWithDMLPermissions() error {
-	indexPermissions := []string{
-		IndicesDMLActionPermission,
-		strings.ToUpper(IndicesDMLActionPermission),
-		IndicesMappingPutPermission,
-		strings.ToUpper(IndicesMappingPutPermission),
-		IndicesExistPermission,
-		strings.ToUpper(IndicesExistPermission),
-		IndicesGetPermission,
-		strings.ToUpper(IndicesGetPermission),
-	}
-	clusterPermissions := []string{
-		ClusterReadWritePermissions,
-		strings.ToUpper(ClusterReadWritePermissions),
-		ClusterScrollClearPermission,
-		ClusterMonitorTaskGetPermission,
-		ClusterMonitorStatePermission,
-		ClusterMonitorMainPermission,
-	}
-	return bp.createRole(clusterPermissions, indexPermissions, []string{}, DmlRoleType)
+	return bp.CreateRoleType(bp.getRoleType(DmlRoleType))
 }
 
 func (bp BaseProvider) CreateRoleWithReadOnlyPermissions() error {
-	indexPermissions := []string{
-		IndicesROActionPermission,
-		IndicesExistPermission,
-		IndicesGetPermission,
-		strings.ToUpper(IndicesROActionPermission),
-		strings.ToUpper(IndicesExistPermission),
-		strings.ToUpper(IndicesGetPermission),
-	}
-	clusterPermissions := []string{
-		ClusterReadOnlyPermissions,
-		strings.ToUpper(ClusterReadOnlyPermissions),
-		ClusterScrollClearPermission,
-		ClusterMonitorStatePermission,
-		ClusterMonitorMainPermission,
+	return bp.CreateRoleType(bp.getRoleType(ReadOnlyRoleType))
+}
+
+// CreateRoleType creates or updates the role with permissions of the role type
+func (bp BaseProvider) CreateRoleType(roleType RoleType) error {
+	indexPermissions := roleType.IndexPermissions
+	if indexPermissions == nil {
+		indexPermissions = []string{}
 	}
-	return bp.createRole(clusterPermissions, indexPermissions, []string{}, ReadOnlyRoleType)
+	return bp.createRole(roleType.ClusterPermissions, indexPermissions, roleType.GlobalIndexPermissions,
+		roleType.TenantPermissions, roleType.Name)
 }
 
 func (bp BaseProvider) createRole(clusterPermissions []string, indexPermissions []string,
-	globalIndexPermissions []string, roleType string) error {
+	globalIndexPermissions []string, tenantPermissions []string, roleType string) error {
 	name := fmt.Sprintf(common.RoleNamePattern, roleType)
 	logger.Debug(fmt.Sprintf("Creating role with name [%s]", name))
 	role := Role{
@@ -186,6 +146,14 @@ func (bp BaseProvider) createRole(clusterPermissions []string, indexPermissions
 			AllowedActions: globalIndexPermissions,
 		})
 	}
+	if len(tenantPermissions) > 0 {
+		role.TenantPermissions = []TenantPermission{
+			{
+				TenantPatterns: []string{AttributeTenant},
+				AllowedActions: tenantPermissions,
+			},
+		}
+	}
 	body, err := json.Marshal(role)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to marshal body for '%s' role", name))
@@ -0,0 +1,205 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var roleTypeNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// RoleType describes permissions of users with the role type.
+// Index permissions are granted for indices with the resource prefix of the user,
+// global index permissions are granted for all indices,
+// tenant permissions are granted for the Dashboards tenant named after the resource prefix of the user.
+type RoleType struct {
+	Name                   string   `json:"name"`
+	ClusterPermissions     []string `json:"clusterPermissions,omitempty"`
+	IndexPermissions       []string `json:"indexPermissions,omitempty"`
+	GlobalIndexPermissions []string `json:"globalIndexPermissions,omitempty"`
+	TenantPermissions      []string `json:"tenantPermissions,omitempty"`
+}
+
+// defaultRoleTypes returns role types which are supported without configuration
+func defaultRoleTypes() []RoleType {
+	return []RoleType{
+		{
+			Name: ReadOnlyRoleType,
+			ClusterPermissions: []string{
+				ClusterReadOnlyPermissions,
+				strings.ToUpper(ClusterReadOnlyPermissions),
+				ClusterScrollClearPermission,
+				ClusterMonitorStatePermission,
+				ClusterMonitorMainPermission,
+			},
+			IndexPermissions: []string{
+				IndicesROActionPermission,
+				IndicesExistPermission,
+				IndicesGetPermission,
+				strings.ToUpper(IndicesROActionPermission),
+				strings.ToUpper(IndicesExistPermission),
+				strings.ToUpper(IndicesGetPermission),
+			},
+			TenantPermissions: []string{TenantReadPermission},
+		},
+		{
+			Name: DmlRoleType,
+			ClusterPermissions: []string{
+				ClusterReadWritePermissions,
+				strings.ToUpper(ClusterReadWritePermissions),
+				ClusterScrollClearPermission,
+				ClusterMonitorTaskGetPermission,
+				ClusterMonitorStatePermission,
+				ClusterMonitorMainPermission,
+			},
+			IndexPermissions: []string{
+				IndicesDMLActionPermission,
+				strings.ToUpper(IndicesDMLActionPermission),
+				IndicesMappingPutPermission,
+				strings.ToUpper(IndicesMappingPutPermission),
+				IndicesExistPermission,
+				strings.ToUpper(IndicesExistPermission),
+				IndicesGetPermission,
+				strings.ToUpper(IndicesGetPermission),
+			},
+			TenantPermissions: []string{TenantWritePermission},
+		},
+		{
+			Name: AdminRoleType,
+			ClusterPermissions: []string{
+				ClusterReadWritePermissions,
+				strings.ToUpper(ClusterReadWritePermissions),
+				ClusterMonitorMainPermission,
+				ClusterMonitorHealthPermission,
+				ClusterMonitorTaskPermissions,
+				ClusterMonitorStatePermission,
+				ClusterScrollClearPermission,
+				ClusterManageIndexTemplatesPermissions,
+				ClusterManageTemplatePermissions,
+				ClusterManageIndexTemplatePermissions,
+			},
+			IndexPermissions: []string{
+				IndicesAllActionPermission,
+				strings.ToUpper(IndicesAllActionPermission),
+			},
+			// `indices:admin/resize` permission required for clone index and should be removed after fix https://github.com/opensearch-project/security/issues/429
+			GlobalIndexPermissions: []string{
+				ClusterManageIndexTemplatePermissions,
+				ClusterManageAliasesPermissions,
+				"indices:admin/resize",
+				IndicesAdminRefreshPermission,
+			},
+			TenantPermissions: []string{TenantWritePermission},
+		},
+		{
+			Name:               IsmRoleType,
+			ClusterPermissions: []string{ClusterAdminIsmPermissions},
+			GlobalIndexPermissions: []string{
+				IndicesIsmManagedIndexPermission,
+				IndicesMonitorStatsPermission,
+				IndicesRolloverPermission,
+				IndicesDeletePermission,
+			},
+		},
+	}
+}
+
+// LoadRoleTypes reads role types from the configuration file. Role types from the file override default ones
+// with the same name, other role types are added after default ones. Default role types are used if the file does not exist.
+func (bp *BaseProvider) LoadRoleTypes(path string) error {
+	roleTypes := defaultRoleTypes()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Info(fmt.Sprintf("Role types configuration '%s' does not exist, default role types are used", path))
+		bp.roleTypes = roleTypes
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read role types configuration '%s': %w", path, err)
+	}
+	var configuredRoleTypes []RoleType
+	if strings.TrimSpace(string(data)) != "" {
+		if err = json.Unmarshal(data, &configuredRoleTypes); err != nil {
+			return fmt.Errorf("failed to parse role types configuration '%s': %w", path, err)
+		}
+	}
+	for _, roleType := range configuredRoleTypes {
+		if err = validateRoleType(roleType); err != nil {
+			return err
+		}
+		overridden := false
+		for i := range roleTypes {
+			if roleTypes[i].Name == roleType.Name {
+				roleTypes[i] = roleType
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			roleTypes = append(roleTypes, roleType)
+		}
+	}
+	bp.roleTypes = roleTypes
+	logger.Info(fmt.Sprintf("Supported role types are %v", bp.GetSupportedRoleTypes()))
+	return nil
+}
+
+func validateRoleType(roleType RoleType) error {
+	if !roleTypeNameRegexp.MatchString(roleType.Name) {
+		return fmt.Errorf("role type name '%s' must match '%s'", roleType.Name, roleTypeNameRegexp.String())
+	}
+	if len(roleType.ClusterPermissions) == 0 && len(roleType.IndexPermissions) == 0 && len(roleType.GlobalIndexPermissions) == 0 &&
+		len(roleType.TenantPermissions) == 0 {
+		return fmt.Errorf("role type '%s' does not have any permissions", roleType.Name)
+	}
+	return nil
+}
+
+// CreateRoles creates or updates roles for all supported role types
+func (bp BaseProvider) CreateRoles() error {
+	for _, roleType := range bp.getRoleTypes() {
+		if err := bp.CreateRoleType(roleType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bp BaseProvider) getRoleTypes() []RoleType {
+	if bp.roleTypes == nil {
+		return defaultRoleTypes()
+	}
+	return bp.roleTypes
+}
+
+// getRoleType returns the supported role type by its name or the default role type if it is not configured
+func (bp BaseProvider) getRoleType(name string) RoleType {
+	for _, roleType := range bp.getRoleTypes() {
+		if roleType.Name == name {
+			return roleType
+		}
+	}
+	for _, roleType := range defaultRoleTypes() {
+		if roleType.Name == name {
+			return roleType
+		}
+	}
+	return RoleType{Name: name}
+}
@@ -0,0 +1,97 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const roleTypesConfiguration = `[
+  {"name": "writer", "clusterPermissions": ["cluster_composite_ops"], "indexPermissions": ["indices:data/write/index"]},
+  {"name": "readonly", "indexPermissions": ["indices:data/read/search"]}
+]`
+
+func writeRoleTypes(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "dbaas.role_types.json")
+	err := os.WriteFile(path, []byte(content), 0600)
+	assert.Nil(t, err)
+	return path
+}
+
+func TestLoadRoleTypesWithoutConfiguration(t *testing.T) {
+	provider := bp
+	err := provider.LoadRoleTypes(filepath.Join(t.TempDir(), "absent.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{ReadOnlyRoleType, DmlRoleType, AdminRoleType, IsmRoleType}, provider.GetSupportedRoleTypes())
+}
+
+func TestLoadRoleTypes(t *testing.T) {
+	provider := bp
+	err := provider.LoadRoleTypes(writeRoleTypes(t, roleTypesConfiguration))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{ReadOnlyRoleType, DmlRoleType, AdminRoleType, IsmRoleType, "writer"}, provider.GetSupportedRoleTypes())
+	assert.Equal(t, []string{"indices:data/read/search"}, provider.getRoleType(ReadOnlyRoleType).IndexPermissions)
+	assert.Equal(t, []string{"indices:data/write/index"}, provider.getRoleType("writer").IndexPermissions)
+	assert.Nil(t, provider.CreateRoles())
+}
+
+func TestLoadInvalidRoleTypes(t *testing.T) {
+	provider := bp
+	err := provider.LoadRoleTypes(writeRoleTypes(t, `[{"name": "Writer*", "indexPermissions": ["indices:data/write/index"]}]`))
+	assert.NotNil(t, err)
+	err = provider.LoadRoleTypes(writeRoleTypes(t, `[{"name": "writer"}]`))
+	assert.NotNil(t, err)
+	err = provider.LoadRoleTypes(writeRoleTypes(t, `{"name": "writer"}`))
+	assert.NotNil(t, err)
+}
+
+func TestDefineRoleType(t *testing.T) {
+	provider := bp
+	err := provider.LoadRoleTypes(writeRoleTypes(t, `[
+  {"name": "dml_nodelete", "indexPermissions": ["indices:data/write/index"]}
+]`))
+	assert.Nil(t, err)
+	assert.Equal(t, DmlRoleType, provider.DefineRoleType("dbaas_dml"))
+	assert.Equal(t, "dml_nodelete", provider.DefineRoleType("dbaas_dml_nodelete"))
+	assert.Equal(t, "dml_nodelete", provider.DefineRoleType("dbaas_dml_nodelete_role"))
+	assert.Equal(t, ReadOnlyRoleType, provider.DefineRoleType("prefix_readonly"))
+	assert.Equal(t, "dml_nodelete", provider.DefineRoleType("prefix_dml_nodelete"))
+	assert.Equal(t, AdminRoleType, provider.DefineRoleType("prefix_role"))
+	assert.Equal(t, DmlRoleType, provider.DefineRoleType("prefix_dml_role"))
+	assert.Equal(t, ReadOnlyRoleType, provider.DefineRoleType("prefix_readonly_role"))
+	assert.Equal(t, "dml_nodelete", provider.DefineRoleType("prefix_dml_nodelete_role"))
+	assert.Equal(t, AdminRoleType, provider.DefineRoleType("prefix_admin_role"))
+}
+
+func TestCreateDatabaseWithConfiguredRoleTypes(t *testing.T) {
+	provider := bp
+	err := provider.LoadRoleTypes(writeRoleTypes(t, roleTypesConfiguration))
+	assert.Nil(t, err)
+	requestOnCreateDb := DbCreateRequest{
+		Settings: Settings{
+			ResourcePrefix: true,
+			CreateOnly:     []string{common.UserKind},
+		},
+	}
+	response, err := provider.createDatabase(requestOnCreateDb, ctx)
+	assert.Nil(t, err)
+	connectionProperties := response.(DbCreateResponseMultiUser).ConnectionProperties
+	assert.Len(t, connectionProperties, 5)
+	assert.Equal(t, "writer", connectionProperties[4].Role)
+}
@@ -0,0 +1,422 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/gorilla/mux"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+const settingsChangesMetadataKey = "settingsChanges"
+
+var errInvalidSettingsUpdate = errors.New("invalid settings update")
+
+// SettingsUpdateRequest describes changes of settings of the existing database
+type SettingsUpdateRequest struct {
+	// IndexSettings are dynamic index settings applied to all indices and templates of the database
+	IndexSettings map[string]interface{} `json:"indexSettings,omitempty"`
+	// AddRoleTypes are role types for which users are created
+	AddRoleTypes []string `json:"addRoleTypes,omitempty"`
+	// RemoveRoleTypes are role types which users are deleted
+	RemoveRoleTypes []string `json:"removeRoleTypes,omitempty"`
+}
+
+// settingsUpdateEnvelope is the request of DBaaS aggregator which contains current and new settings of the database.
+// Only new settings are applied, fields of the settings update are also accepted at the top level.
+type settingsUpdateEnvelope struct {
+	SettingsUpdateRequest
+	NewSettings *SettingsUpdateRequest `json:"newSettings,omitempty"`
+}
+
+type SettingsUpdateResponse struct {
+	Indices              []string                      `json:"indices"`
+	Templates            []string                      `json:"templates"`
+	ConnectionProperties []common.ConnectionProperties `json:"connectionProperties"`
+	Resources            []dao.DbResource              `json:"resources"`
+	DeletedUsers         []string                      `json:"deletedUsers"`
+}
+
+// SettingsChange is the record about applied settings update stored in the metadata document
+type SettingsChange struct {
+	Time             string                 `json:"time"`
+	IndexSettings    map[string]interface{} `json:"indexSettings,omitempty"`
+	Indices          []string               `json:"indices,omitempty"`
+	Templates        []string               `json:"templates,omitempty"`
+	AddedRoleTypes   []string               `json:"addedRoleTypes,omitempty"`
+	RemovedRoleTypes []string               `json:"removedRoleTypes,omitempty"`
+}
+
+// UpdateSettingsHandler applies new settings to the existing database with the resource prefix
+func (bp BaseProvider) UpdateSettingsHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		prefix := mux.Vars(r)["dbName"]
+		logger.InfoContext(ctx, fmt.Sprintf("Request to update settings of '%s' database is received", prefix))
+		var envelope settingsUpdateEnvelope
+		decoder := json.NewDecoder(r.Body)
+		err := decoder.Decode(&envelope)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to decode request in update settings handler", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusBadRequest)
+			return
+		}
+		defer func() { _ = r.Body.Close() }()
+		request := envelope.SettingsUpdateRequest
+		if envelope.NewSettings != nil {
+			request = *envelope.NewSettings
+		}
+		response, err := bp.updateSettings(prefix, request, ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to update settings of '%s' database", prefix), slog.Any("error", err))
+			status := http.StatusInternalServerError
+			if errors.Is(err, errInvalidSettingsUpdate) {
+				status = http.StatusBadRequest
+			}
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), status)
+			return
+		}
+		responseBody, err := json.Marshal(response)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to serialize response in update settings handler", slog.Any("error", err))
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
+		common.ProcessResponseBody(ctx, w, responseBody, http.StatusOK)
+	}
+}
+
+// updateSettings applies the settings update step by step. Completed steps are recorded in the metadata document
+// even if the next step fails, so the same update can be repeated.
+func (bp BaseProvider) updateSettings(prefix string, request SettingsUpdateRequest, ctx context.Context) (*SettingsUpdateResponse, error) {
+	if err := bp.validateSettingsUpdate(prefix, request); err != nil {
+		return nil, err
+	}
+	response := &SettingsUpdateResponse{
+		Indices:              make([]string, 0),
+		Templates:            make([]string, 0),
+		ConnectionProperties: make([]common.ConnectionProperties, 0),
+		Resources:            make([]dao.DbResource, 0),
+		DeletedUsers:         make([]string, 0),
+	}
+	change := SettingsChange{Time: time.Now().UTC().Format(time.RFC3339)}
+	err := bp.applySettingsUpdate(prefix, request, response, &change, ctx)
+	if change.IndexSettings != nil || len(change.AddedRoleTypes) > 0 || len(change.RemovedRoleTypes) > 0 {
+		if recordErr := bp.recordSettingsChange(prefix, change, ctx); recordErr != nil {
+			err = errors.Join(err, recordErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (bp BaseProvider) validateSettingsUpdate(prefix string, request SettingsUpdateRequest) error {
+	if err := checkForbiddenSymbolPrefix(prefix); err != nil || prefix == "" {
+		return fmt.Errorf("%w: database prefix '%s' is not valid", errInvalidSettingsUpdate, prefix)
+	}
+	if len(request.IndexSettings) == 0 && len(request.AddRoleTypes) == 0 && len(request.RemoveRoleTypes) == 0 {
+		return fmt.Errorf("%w: request must contain 'indexSettings', 'addRoleTypes' or 'removeRoleTypes'", errInvalidSettingsUpdate)
+	}
+	supportedRoleTypes := bp.GetSupportedRoleTypes()
+	for _, roleType := range append(slices.Clone(request.AddRoleTypes), request.RemoveRoleTypes...) {
+		if !slices.Contains(supportedRoleTypes, roleType) {
+			return fmt.Errorf("%w: role type '%s' is not supported, supported role types are %v",
+				errInvalidSettingsUpdate, roleType, supportedRoleTypes)
+		}
+	}
+	for _, roleType := range request.AddRoleTypes {
+		if slices.Contains(request.RemoveRoleTypes, roleType) {
+			return fmt.Errorf("%w: role type '%s' can not be added and removed at the same time", errInvalidSettingsUpdate, roleType)
+		}
+	}
+	return nil
+}
+
+func (bp BaseProvider) applySettingsUpdate(prefix string, request SettingsUpdateRequest, response *SettingsUpdateResponse,
+	change *SettingsChange, ctx context.Context) error {
+	namePattern := fmt.Sprintf("%s*", prefix)
+	if len(request.IndexSettings) > 0 {
+		settings := normalizeIndexSettings(request.IndexSettings)
+		indices, err := bp.putIndexSettings(namePattern, settings, ctx)
+		if err != nil {
+			return err
+		}
+		response.Indices = indices
+		templates, err := bp.putTemplatesSettings(namePattern, settings, ctx)
+		response.Templates = templates
+		change.IndexSettings = settings
+		change.Indices = indices
+		change.Templates = templates
+		if err != nil {
+			return err
+		}
+	}
+	if len(request.AddRoleTypes) == 0 && len(request.RemoveRoleTypes) == 0 {
+		return nil
+	}
+	users, err := bp.getUsers()
+	if err != nil {
+		return err
+	}
+	metadata, err := bp.GetMetadata(prefix, ctx)
+	if err != nil {
+		return err
+	}
+	temporaryUsers, err := getTemporaryUsers(metadata)
+	if err != nil {
+		return err
+	}
+	existingUsers := bp.describeUsers(prefix, users, temporaryUsers)
+	for _, roleType := range request.RemoveRoleTypes {
+		for _, user := range existingUsers {
+			if user.RoleType != roleType {
+				continue
+			}
+			if err = bp.deleteUser(user.Username, ctx); err != nil {
+				return fmt.Errorf("failed to delete '%s' user with '%s' role type: %w", user.Username, roleType, err)
+			}
+			response.DeletedUsers = append(response.DeletedUsers, user.Username)
+		}
+		change.RemovedRoleTypes = append(change.RemovedRoleTypes, roleType)
+	}
+	for _, roleType := range request.AddRoleTypes {
+		exists := slices.ContainsFunc(existingUsers, func(user UserDescription) bool {
+			return user.RoleType == roleType
+		})
+		if exists {
+			logger.InfoContext(ctx, fmt.Sprintf("User with '%s' role type already exists for '%s' database", roleType, prefix))
+			continue
+		}
+		username, password, resources, err := bp.CreateUserByPrefix(prefix, "", namePattern, roleType, ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create user with '%s' role type: %w", roleType, err)
+		}
+		response.ConnectionProperties = append(response.ConnectionProperties,
+			bp.GetExtendedConnectionProperties("", username, password, prefix, roleType))
+		response.Resources = append(response.Resources, resources...)
+		change.AddedRoleTypes = append(change.AddedRoleTypes, roleType)
+	}
+	return nil
+}
+
+// putIndexSettings applies settings to all open indices matching the pattern and returns their names
+func (bp BaseProvider) putIndexSettings(pattern string, settings map[string]interface{}, ctx context.Context) ([]string, error) {
+	indices, err := bp.describeIndices(pattern, ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(indices))
+	for _, index := range indices {
+		names = append(names, index.Name)
+	}
+	if len(names) == 0 {
+		return names, nil
+	}
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	putSettingsRequest := opensearchapi.IndicesPutSettingsRequest{
+		Index: []string{pattern},
+		Body:  strings.NewReader(string(body)),
+	}
+	response, err := putSettingsRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update settings of '%s' indices: %w", pattern, err)
+	}
+	if err = checkAcknowledged(response, fmt.Sprintf("update settings of '%s' indices", pattern)); err != nil {
+		return nil, err
+	}
+	logger.InfoContext(ctx, fmt.Sprintf("Settings of %v indices are updated", names))
+	return names, nil
+}
+
+// putTemplatesSettings merges settings to index templates and legacy templates matching the pattern,
+// so new indices of the database are created with the same settings
+func (bp BaseProvider) putTemplatesSettings(pattern string, settings map[string]interface{}, ctx context.Context) ([]string, error) {
+	updated := make([]string, 0)
+	getIndexTemplateRequest := opensearchapi.IndicesGetIndexTemplateRequest{
+		Name: []string{pattern},
+	}
+	var indexTemplates map[string][]IndexTemplate
+	found, err := bp.getTemplates(getIndexTemplateRequest, &indexTemplates, ctx)
+	if err != nil {
+		return updated, err
+	}
+	if found {
+		for _, template := range indexTemplates["index_templates"] {
+			body, ok := template.IndexTemplate.(map[string]interface{})
+			if !ok {
+				return updated, fmt.Errorf("index template '%s' has unexpected format", template.Name)
+			}
+			templateSection, _ := body["template"].(map[string]interface{})
+			if templateSection == nil {
+				templateSection = make(map[string]interface{})
+				body["template"] = templateSection
+			}
+			templateSection["settings"] = mergeSettings(templateSection["settings"], settings)
+			templateBody, err := json.Marshal(body)
+			if err != nil {
+				return updated, err
+			}
+			request := opensearchapi.IndicesPutIndexTemplateRequest{Name: template.Name, Body: strings.NewReader(string(templateBody))}
+			if err = bp.putTemplate(request, template.Name, ctx); err != nil {
+				return updated, err
+			}
+			updated = append(updated, template.Name)
+		}
+	}
+	getTemplateRequest := opensearchapi.IndicesGetTemplateRequest{
+		Name: []string{pattern},
+	}
+	var templates map[string]map[string]interface{}
+	found, err = bp.getTemplates(getTemplateRequest, &templates, ctx)
+	if err != nil {
+		return updated, err
+	}
+	if found {
+		for name, body := range templates {
+			body["settings"] = mergeSettings(body["settings"], settings)
+			templateBody, err := json.Marshal(body)
+			if err != nil {
+				return updated, err
+			}
+			request := opensearchapi.IndicesPutTemplateRequest{Name: name, Body: strings.NewReader(string(templateBody))}
+			if err = bp.putTemplate(request, name, ctx); err != nil {
+				return updated, err
+			}
+			updated = append(updated, name)
+		}
+	}
+	slices.Sort(updated)
+	return updated, nil
+}
+
+// getTemplates reads templates to the result and returns false if there are no templates
+func (bp BaseProvider) getTemplates(request opensearchapi.Request, result interface{}, ctx context.Context) (bool, error) {
+	response, err := request.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("during receiving templates error occurred: [%d]", response.StatusCode)
+	}
+	return true, common.ProcessBody(response.Body, result)
+}
+
+func (bp BaseProvider) putTemplate(request opensearchapi.Request, name string, ctx context.Context) error {
+	response, err := request.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return fmt.Errorf("failed to update '%s' template: %w", name, err)
+	}
+	if err = checkAcknowledged(response, fmt.Sprintf("update '%s' template", name)); err != nil {
+		return err
+	}
+	logger.InfoContext(ctx, fmt.Sprintf("Settings of '%s' template are updated", name))
+	return nil
+}
+
+// recordSettingsChange appends the change to the list of settings changes in the metadata document of the database
+func (bp BaseProvider) recordSettingsChange(prefix string, change SettingsChange, ctx context.Context) error {
+	metadata, err := bp.GetMetadata(prefix, ctx)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	changes, _ := metadata[settingsChangesMetadataKey].([]interface{})
+	changeMap, err := common.ConvertStructToMap(change)
+	if err != nil {
+		return err
+	}
+	metadata[settingsChangesMetadataKey] = append(changes, changeMap)
+	_, err = bp.CreateMetadata(prefix, metadata, ctx)
+	return err
+}
+
+func checkAcknowledged(response *opensearchapi.Response, action string) error {
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("failed to %s: [%d] %s", action, response.StatusCode, string(body))
+}
+
+// normalizeIndexSettings converts settings to the flat form with `index.` prefix,
+// for example, `{"index": {"refresh_interval": "5s"}}` is converted to `{"index.refresh_interval": "5s"}`
+func normalizeIndexSettings(settings map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	flattenSettings("", settings, flat)
+	result := make(map[string]interface{}, len(flat))
+	for key, value := range flat {
+		if !strings.HasPrefix(key, "index.") {
+			key = fmt.Sprintf("index.%s", key)
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func flattenSettings(prefix string, settings map[string]interface{}, result map[string]interface{}) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = fmt.Sprintf("%s.%s", prefix, key)
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenSettings(key, nested, result)
+			continue
+		}
+		result[key] = value
+	}
+}
+
+// mergeSettings merges normalized settings to the settings of the template, null values remove settings
+func mergeSettings(templateSettings interface{}, settings map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if current, ok := templateSettings.(map[string]interface{}); ok {
+		result = normalizeIndexSettings(current)
+	}
+	for key, value := range settings {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
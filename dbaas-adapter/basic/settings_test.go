@@ -0,0 +1,178 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// settingsClient serves resources of `orders` database and records bodies of modifying requests by their paths
+type settingsClient struct {
+	lock     sync.Mutex
+	requests map[string]string
+}
+
+func newSettingsClient() *settingsClient {
+	return &settingsClient{requests: make(map[string]string)}
+}
+
+func (c *settingsClient) Perform(req *http.Request) (*http.Response, error) {
+	path := req.URL.Path
+	if req.Method != http.MethodGet {
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		c.lock.Lock()
+		c.requests[req.Method+" "+path] = string(body)
+		c.lock.Unlock()
+	}
+	statusCode := http.StatusOK
+	body := `{"acknowledged":true}`
+	switch {
+	case req.Method != http.MethodGet && strings.HasPrefix(path, "/dbaas_opensearch_metadata/_doc/"):
+		statusCode = http.StatusCreated
+		body = `{"result":"updated"}`
+	case req.Method != http.MethodGet:
+	case path == "/_cat/indices/orders*":
+		body = `[{"index":"orders_items","docs.count":"10","store.size":"2048"}]`
+	case path == "/_index_template/orders*":
+		body = `{"index_templates":[{"name":"orders_template","index_template":{"index_patterns":["orders_*"],
+"template":{"settings":{"index":{"number_of_shards":"1","refresh_interval":"1s"}}}}}]}`
+	case path == "/_template/orders*":
+		body = `{"orders_legacy":{"order":0,"index_patterns":["orders_legacy*"],"settings":{}}}`
+	case path == "/_plugins/_security/api/internalusers":
+		body = `{"orders_a1":{"hash":"","backend_roles":["dbaas_readonly"],"attributes":{"resource_prefix":"orders"}},
+"orders_b2":{"hash":"","backend_roles":["dbaas_dml"],"attributes":{"resource_prefix":"orders"}}}`
+	case strings.HasPrefix(path, "/_plugins/_security/api/internalusers/"):
+		statusCode = http.StatusNotFound
+		body = `{}`
+	case path == "/dbaas_opensearch_metadata/_doc/orders":
+		body = `{"found":true,"_source":{"microserviceName":"orders-service","settingsChanges":[{"time":"2025-01-01T00:00:00Z"}]}}`
+	default:
+		statusCode = http.StatusNotFound
+		body = `{}`
+	}
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func (c *settingsClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *settingsClient) DiscoverNodes() error {
+	return nil
+}
+
+func TestNormalizeIndexSettings(t *testing.T) {
+	settings := normalizeIndexSettings(map[string]interface{}{
+		"index":              map[string]interface{}{"refresh_interval": "5s"},
+		"number_of_replicas": 2,
+		"index.blocks.write": nil,
+	})
+	expected := map[string]interface{}{
+		"index.refresh_interval":   "5s",
+		"index.number_of_replicas": 2,
+		"index.blocks.write":       nil,
+	}
+	assert.Equal(t, expected, settings)
+}
+
+func TestUpdateSettings(t *testing.T) {
+	client := newSettingsClient()
+	provider := newRecoveryProvider(client)
+	request := SettingsUpdateRequest{
+		IndexSettings:   map[string]interface{}{"index": map[string]interface{}{"refresh_interval": "5s"}},
+		AddRoleTypes:    []string{AdminRoleType, DmlRoleType},
+		RemoveRoleTypes: []string{ReadOnlyRoleType},
+	}
+	response, err := provider.updateSettings("orders", request, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"orders_items"}, response.Indices)
+	assert.Equal(t, []string{"orders_legacy", "orders_template"}, response.Templates)
+	assert.Equal(t, []string{"orders_a1"}, response.DeletedUsers)
+	assert.Len(t, response.ConnectionProperties, 1)
+	assert.Equal(t, AdminRoleType, response.ConnectionProperties[0].Role)
+	assert.Equal(t, "orders", response.ConnectionProperties[0].ResourcePrefix)
+	assert.NotEmpty(t, response.ConnectionProperties[0].Password)
+
+	assert.JSONEq(t, `{"index.refresh_interval":"5s"}`, client.requests["PUT /orders*/_settings"])
+	var indexTemplate map[string]interface{}
+	err = json.Unmarshal([]byte(client.requests["PUT /_index_template/orders_template"]), &indexTemplate)
+	assert.Nil(t, err)
+	expectedSettings := map[string]interface{}{"index.number_of_shards": "1", "index.refresh_interval": "5s"}
+	assert.Equal(t, expectedSettings, indexTemplate["template"].(map[string]interface{})["settings"])
+	assert.JSONEq(t, `{"order":0,"index_patterns":["orders_legacy*"],"settings":{"index.refresh_interval":"5s"}}`,
+		client.requests["PUT /_template/orders_legacy"])
+	_, deleted := client.requests["DELETE /_plugins/_security/api/internalusers/orders_a1"]
+	assert.True(t, deleted)
+
+	var metadata map[string]interface{}
+	err = json.Unmarshal([]byte(client.requests["PUT /dbaas_opensearch_metadata/_doc/orders"]), &metadata)
+	assert.Nil(t, err)
+	assert.Equal(t, "orders-service", metadata["microserviceName"])
+	changes := metadata[settingsChangesMetadataKey].([]interface{})
+	assert.Len(t, changes, 2)
+	change := changes[1].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"index.refresh_interval": "5s"}, change["indexSettings"])
+	assert.Equal(t, []interface{}{AdminRoleType}, change["addedRoleTypes"])
+	assert.Equal(t, []interface{}{ReadOnlyRoleType}, change["removedRoleTypes"])
+}
+
+func TestUpdateSettingsHandlerWithUnsupportedRoleType(t *testing.T) {
+	client := newSettingsClient()
+	provider := newRecoveryProvider(client)
+	request := httptest.NewRequest(http.MethodPut, "/databases/orders/settings", strings.NewReader(`{"addRoleTypes":["unknown"]}`))
+	request = mux.SetURLVars(request, map[string]string{"dbName": "orders"})
+	recorder := httptest.NewRecorder()
+	provider.UpdateSettingsHandler()(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "role type 'unknown' is not supported")
+	assert.Empty(t, client.requests)
+}
+
+func TestUpdateSettingsHandlerWithEnvelope(t *testing.T) {
+	client := newSettingsClient()
+	provider := newRecoveryProvider(client)
+	request := httptest.NewRequest(http.MethodPut, "/databases/orders/settings",
+		strings.NewReader(`{"currentSettings":{},"newSettings":{"indexSettings":{"index":{"refresh_interval":"5s"}}}}`))
+	request = mux.SetURLVars(request, map[string]string{"dbName": "orders"})
+	recorder := httptest.NewRecorder()
+	provider.UpdateSettingsHandler()(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"index.refresh_interval":"5s"}`, client.requests["PUT /orders*/_settings"])
+}
+
+func TestUpdateSettingsHandlerWithoutChanges(t *testing.T) {
+	client := newSettingsClient()
+	provider := newRecoveryProvider(client)
+	for _, body := range []string{`{}`, `{"currentSettings":{"indexSettings":{"index":{"refresh_interval":"5s"}}},"newSettings":{}}`, `{"settings":{}}`} {
+		request := httptest.NewRequest(http.MethodPut, "/databases/orders/settings", strings.NewReader(body))
+		request = mux.SetURLVars(request, map[string]string{"dbName": "orders"})
+		recorder := httptest.NewRecorder()
+		provider.UpdateSettingsHandler()(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, body)
+	}
+	assert.Empty(t, client.requests)
+}
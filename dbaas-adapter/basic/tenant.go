@@ -0,0 +1,135 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Netcracker/dbaas-opensearch-adapter/api"
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+)
+
+const (
+	// AttributeTenant matches the Dashboards tenant named after the resource prefix of the user
+	AttributeTenant       = "${attr.internal.resource_prefix}"
+	TenantReadPermission  = "kibana_all_read"
+	TenantWritePermission = "kibana_all_write"
+)
+
+// Tenant is OpenSearch Dashboards tenant which separates saved objects of logical databases
+type Tenant struct {
+	Description string `json:"description,omitempty"`
+	Reserved    bool   `json:"reserved,omitempty"`
+}
+
+type TenantPermission struct {
+	TenantPatterns []string `json:"tenant_patterns"`
+	AllowedActions []string `json:"allowed_actions"`
+}
+
+// createTenant creates or updates Dashboards tenant with the name of the resource prefix
+func (bp BaseProvider) createTenant(name string, ctx context.Context) error {
+	logger.InfoContext(ctx, fmt.Sprintf("Creating '%s' tenant", name))
+	body, err := json.Marshal(Tenant{Description: fmt.Sprintf("Tenant of '%s' database", name)})
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Add("Content-type", "application/json")
+	createTenantRequest := api.CreateTenantRequest{
+		Tenant: name,
+		Body:   strings.NewReader(string(body)),
+		Header: header,
+	}
+	response, err := createTenantRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return fmt.Errorf("error occurred during '%s' tenant creation: %+v", name, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusOK || response.StatusCode == http.StatusCreated {
+		logger.InfoContext(ctx, fmt.Sprintf("'%s' tenant is successfully created or updated", name))
+		return nil
+	}
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("tenant with name '%s' is not created: [%d] %s", name, response.StatusCode, string(responseBody))
+}
+
+func (bp BaseProvider) getTenant(name string) (*Tenant, error) {
+	logger.Debug(fmt.Sprintf("Getting tenant with name '%s'", name))
+	getTenantRequest := api.GetTenantRequest{
+		Tenant: name,
+	}
+	response, err := getTenantRequest.Do(context.Background(), bp.opensearch.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive tenant with '%s' name: %+v", name, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusNotFound {
+		logger.Debug(fmt.Sprintf("Tenant with name '%s' is not found", name))
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("during receiving tenant error occurred: [%d] %+v", response.StatusCode, response.Body)
+	}
+	var tenants map[string]*Tenant
+	err = common.ProcessBody(response.Body, &tenants)
+	if err != nil {
+		return nil, err
+	}
+	return tenants[name], nil
+}
+
+// getTenants returns all Dashboards tenants by their names
+func (bp BaseProvider) getTenants(ctx context.Context) (map[string]Tenant, error) {
+	getTenantRequest := api.GetTenantRequest{}
+	response, err := getTenantRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive tenants: %+v", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("during receiving tenants error occurred: [%d] %+v", response.StatusCode, response.Body)
+	}
+	var tenants map[string]Tenant
+	err = common.ProcessBody(response.Body, &tenants)
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+func (bp BaseProvider) deleteTenant(name string, ctx context.Context) error {
+	deleteTenantRequest := api.DeleteTenantRequest{
+		Tenant: name,
+	}
+	response, err := deleteTenantRequest.Do(ctx, bp.opensearch.Client)
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant with '%s' name: %+v", name, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("during deleting '%s' tenant error occurred: [%d] %+v", name, response.StatusCode, response.Body)
+	}
+	logger.DebugContext(ctx, fmt.Sprintf("Tenant with name '%s' is removed", name))
+	return nil
+}
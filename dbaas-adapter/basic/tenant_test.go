@@ -0,0 +1,68 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"encoding/json"
+	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestCreateDatabaseWithTenant(t *testing.T) {
+	requestOnCreateDb := DbCreateRequest{
+		NamePrefix: "orders",
+		Settings: Settings{
+			ResourcePrefix: true,
+			CreateOnly:     []string{common.UserKind, common.TenantKind},
+		},
+	}
+	response, err := bp.createDatabase(requestOnCreateDb, ctx)
+	assert.Nil(t, err)
+	resources := response.(DbCreateResponseMultiUser).Resources
+	assert.Contains(t, resources, dao.DbResource{Kind: common.TenantKind, Name: "orders"})
+}
+
+func TestCreateDatabaseWithTenantWithoutResourcePrefix(t *testing.T) {
+	requestOnCreateDb := DbCreateRequest{
+		DbName: "orders",
+		Settings: Settings{
+			CreateOnly: []string{common.TenantKind},
+		},
+	}
+	_, err := baseProvider.createDatabase(requestOnCreateDb, ctx)
+	assert.NotNil(t, err)
+}
+
+func TestCreateRoleWithTenantPermissions(t *testing.T) {
+	client := newSettingsClient()
+	provider := newRecoveryProvider(client)
+	err := provider.CreateRoleType(provider.getRoleType(ReadOnlyRoleType))
+	assert.Nil(t, err)
+	var role Role
+	err = json.Unmarshal([]byte(client.requests[http.MethodPut+" /_plugins/_security/api/roles/dbaas_readonly_role"]), &role)
+	assert.Nil(t, err)
+	expected := []TenantPermission{{TenantPatterns: []string{AttributeTenant}, AllowedActions: []string{TenantReadPermission}}}
+	assert.Equal(t, expected, role.TenantPermissions)
+
+	err = provider.CreateRoleType(provider.getRoleType(IsmRoleType))
+	assert.Nil(t, err)
+	var ismRole Role
+	err = json.Unmarshal([]byte(client.requests[http.MethodPut+" /_plugins/_security/api/roles/dbaas_ism_role"]), &ismRole)
+	assert.Nil(t, err)
+	assert.Empty(t, ismRole.TenantPermissions)
+}
@@ -336,6 +336,24 @@ func (bp BaseProvider) GetUser(username string) (*User, error) {
 }
 
 func (bp BaseProvider) getUsersByPrefix(prefix string) ([]string, error) {
+	users, err := bp.getUsers()
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		return nil, nil
+	}
+	usersByPrefix := make([]string, 0)
+	for element := range users {
+		if strings.HasPrefix(element, prefix) {
+			usersByPrefix = append(usersByPrefix, element)
+		}
+	}
+	return usersByPrefix, nil
+}
+
+// getUsers returns all internal users, it returns nil if there are no users
+func (bp BaseProvider) getUsers() (map[string]User, error) {
 	getUsersRequest := api.GetUsersRequest{}
 	response, err := getUsersRequest.Do(context.Background(), bp.opensearch.Client)
 	if err != nil {
@@ -344,21 +362,15 @@ func (bp BaseProvider) getUsersByPrefix(prefix string) ([]string, error) {
 	defer func() { _ = response.Body.Close() }()
 	if response.StatusCode == http.StatusOK {
 		var users map[string]User
-		usersByPrefix := make([]string, 0)
 		err = common.ProcessBody(response.Body, &users)
 		if err != nil {
 			return nil, err
 		}
-		for element := range users {
-			if strings.HasPrefix(element, prefix) {
-				usersByPrefix = append(usersByPrefix, element)
-			}
-		}
-		return usersByPrefix, nil
+		return users, nil
 	} else if response.StatusCode == http.StatusNotFound {
 		return nil, nil
 	}
-	return nil, fmt.Errorf("during receiving users by prefix %s error occurred: %+v", prefix, response.Body)
+	return nil, fmt.Errorf("during receiving users error occurred: %+v", response.Body)
 }
 
 func (bp BaseProvider) deleteUser(username string, ctx context.Context) error {
@@ -17,12 +17,16 @@ package basic
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 const (
@@ -31,13 +35,162 @@ const (
 	RecoveryFailedState  = "failed"
 	RecoveryDoneState    = "done"
 	batchSize            = 100
+	batchAttempts        = 3
 )
 
+var (
+	errRecoveryRunning = errors.New("users recovery is already running")
+	errNoFailedUsers   = errors.New("there are no failed users to recover")
+
+	recoveryStates     = []string{RecoveryIdleState, RecoveryRunningState, RecoveryFailedState, RecoveryDoneState}
+	recoveryStateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dbaas_opensearch_users_recovery_state",
+		Help: "State of the last users recovery, 1 for the current state and 0 for others",
+	}, []string{"state"})
+	recoveryUsersGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dbaas_opensearch_users_recovery_users",
+		Help: "Number of total, processed and failed users of the last users recovery",
+	}, []string{"users"})
+)
+
+// recoveryRetryDelay is the delay before the second attempt to patch the batch, it is doubled for each next attempt
+var recoveryRetryDelay = 5 * time.Second
+
 type UsersToRecover struct {
 	Settings             map[string]interface{}        `json:"settings,omitempty"`
 	ConnectionProperties []common.ConnectionProperties `json:"connectionProperties"`
 }
 
+// RecoveryProgress describes the progress of the last users recovery
+type RecoveryProgress struct {
+	State string `json:"state"`
+	// Total is the number of users to recover
+	Total int `json:"total"`
+	// Processed is the number of users which are recovered or failed
+	Processed int `json:"processed"`
+	// CurrentBatch is the number of the batch being processed starting from 1
+	CurrentBatch int          `json:"currentBatch,omitempty"`
+	Batches      int          `json:"batches"`
+	FailedUsers  []FailedUser `json:"failedUsers,omitempty"`
+}
+
+// FailedUser is the user which is not recovered with the reason of the failure
+type FailedUser struct {
+	Username string `json:"username"`
+	Reason   string `json:"reason"`
+}
+
+// usersRecovery keeps the progress of users recovery and connection properties of failed users
+// to recover only them on retry
+type usersRecovery struct {
+	lock        *sync.Mutex
+	progress    RecoveryProgress
+	failedUsers []common.ConnectionProperties
+}
+
+func newUsersRecovery() *usersRecovery {
+	recovery := &usersRecovery{
+		lock:     &sync.Mutex{},
+		progress: RecoveryProgress{State: RecoveryIdleState},
+	}
+	recovery.updateMetrics()
+	return recovery
+}
+
+// start resets the progress for the given users and returns false if recovery is already running
+func (ur *usersRecovery) start(users []common.ConnectionProperties) bool {
+	ur.lock.Lock()
+	defer ur.lock.Unlock()
+	if ur.progress.State == RecoveryRunningState {
+		return false
+	}
+	ur.progress = RecoveryProgress{
+		State:   RecoveryRunningState,
+		Total:   len(users),
+		Batches: (len(users) + batchSize - 1) / batchSize,
+	}
+	ur.failedUsers = nil
+	ur.updateMetrics()
+	return true
+}
+
+// startRetry starts recovery of users failed during the previous recovery and returns them
+func (ur *usersRecovery) startRetry() ([]common.ConnectionProperties, error) {
+	ur.lock.Lock()
+	failedUsers := ur.failedUsers
+	state := ur.progress.State
+	ur.lock.Unlock()
+	if state == RecoveryRunningState {
+		return nil, errRecoveryRunning
+	}
+	if len(failedUsers) == 0 {
+		return nil, errNoFailedUsers
+	}
+	if !ur.start(failedUsers) {
+		return nil, errRecoveryRunning
+	}
+	return failedUsers, nil
+}
+
+func (ur *usersRecovery) startBatch(number int) {
+	ur.lock.Lock()
+	defer ur.lock.Unlock()
+	ur.progress.CurrentBatch = number
+}
+
+// finishBatch records processed users of the batch and failed ones with their reasons
+func (ur *usersRecovery) finishBatch(batch []common.ConnectionProperties, failures map[string]string) {
+	ur.lock.Lock()
+	defer ur.lock.Unlock()
+	ur.progress.Processed += len(batch)
+	for _, user := range batch {
+		if reason, failed := failures[user.Username]; failed {
+			ur.progress.FailedUsers = append(ur.progress.FailedUsers, FailedUser{Username: user.Username, Reason: reason})
+			ur.failedUsers = append(ur.failedUsers, user)
+		}
+	}
+	ur.updateMetrics()
+}
+
+// finish sets the final state of recovery, it is failed if at least one user is not recovered
+func (ur *usersRecovery) finish() RecoveryProgress {
+	ur.lock.Lock()
+	defer ur.lock.Unlock()
+	ur.progress.CurrentBatch = 0
+	ur.progress.State = RecoveryDoneState
+	if len(ur.progress.FailedUsers) > 0 {
+		ur.progress.State = RecoveryFailedState
+	}
+	ur.updateMetrics()
+	return ur.getProgress()
+}
+
+func (ur *usersRecovery) Progress() RecoveryProgress {
+	ur.lock.Lock()
+	defer ur.lock.Unlock()
+	return ur.getProgress()
+}
+
+// updateMetrics exposes the progress as gauges, it must be called under the lock
+func (ur *usersRecovery) updateMetrics() {
+	for _, state := range recoveryStates {
+		value := 0.0
+		if state == ur.progress.State {
+			value = 1
+		}
+		recoveryStateGauge.WithLabelValues(state).Set(value)
+	}
+	recoveryUsersGauge.WithLabelValues("total").Set(float64(ur.progress.Total))
+	recoveryUsersGauge.WithLabelValues("processed").Set(float64(ur.progress.Processed))
+	recoveryUsersGauge.WithLabelValues("failed").Set(float64(len(ur.progress.FailedUsers)))
+}
+
+func (ur *usersRecovery) getProgress() RecoveryProgress {
+	progress := ur.progress
+	progress.FailedUsers = append([]FailedUser(nil), ur.progress.FailedUsers...)
+	return progress
+}
+
 func (bp *BaseProvider) RecoverUsersHandler() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := common.PrepareContext(r)
@@ -49,58 +202,97 @@ func (bp *BaseProvider) RecoverUsersHandler() func(w http.ResponseWriter, r *htt
 			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
 			return
 		}
-		if bp.recoveryState != RecoveryRunningState {
-			bp.recoveryState = RecoveryRunningState
-			go bp.recovery(usersToRecover.ConnectionProperties, ctx)
+		if bp.recovery.start(usersToRecover.ConnectionProperties) {
+			go bp.recoverUsers(usersToRecover.ConnectionProperties, ctx)
 		}
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
+// RetryRecoveryHandler starts recovery of only the users failed during the previous recovery
+func (bp *BaseProvider) RetryRecoveryHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.PrepareContext(r)
+		failedUsers, err := bp.recovery.startRetry()
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to retry users recovery", slog.Any("error", err))
+			status := http.StatusBadRequest
+			if errors.Is(err, errRecoveryRunning) {
+				status = http.StatusConflict
+			}
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), status)
+			return
+		}
+		logger.InfoContext(ctx, fmt.Sprintf("Retry recovery of %d failed users", len(failedUsers)))
+		go bp.recoverUsers(failedUsers, ctx)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// GetRecoveryStateHandler returns the progress of the last users recovery
 func (bp *BaseProvider) GetRecoveryStateHandler() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		responseBody := []byte(bp.recoveryState)
+		responseBody, err := json.Marshal(bp.recovery.Progress())
+		if err != nil {
+			common.ProcessResponseBody(ctx, w, []byte(err.Error()), http.StatusInternalServerError)
+			return
+		}
 		common.ProcessResponseBody(ctx, w, responseBody, http.StatusOK)
 	}
 }
 
-func (bp *BaseProvider) recovery(connectionProperties []common.ConnectionProperties, ctx context.Context) {
-	var changes []Change
-	for _, properties := range connectionProperties {
+func (bp *BaseProvider) recoverUsers(users []common.ConnectionProperties, ctx context.Context) {
+	for position := 0; position < len(users); position += batchSize {
+		batch := users[position:min(position+batchSize, len(users))]
+		bp.recovery.startBatch(position/batchSize + 1)
+		logger.DebugContext(ctx, fmt.Sprintf("Current batch size is %d", len(batch)))
+		bp.recovery.finishBatch(batch, bp.recoverBatch(batch, ctx))
+	}
+	progress := bp.recovery.finish()
+	if progress.State == RecoveryFailedState {
+		logger.ErrorContext(ctx, fmt.Sprintf("Users recovery is finished, %d of %d users are not recovered",
+			len(progress.FailedUsers), progress.Total))
+		return
+	}
+	logger.InfoContext(ctx, "Users recovery is successfully finished")
+}
+
+// recoverBatch patches users of the batch with exponential backoff between attempts.
+// If the batch still fails, users are patched one by one to find the failed ones, which are returned with reasons.
+func (bp *BaseProvider) recoverBatch(batch []common.ConnectionProperties, ctx context.Context) map[string]string {
+	delay := recoveryRetryDelay
+	var err error
+	for attempt := 1; attempt <= batchAttempts; attempt++ {
+		if err = bp.patchUsers(bp.getUsersChanges(batch), ctx); err == nil {
+			return nil
+		}
+		logger.WarnContext(ctx, fmt.Sprintf("Attempt %d to restore batch of users failed: %+v", attempt, err))
+		if attempt < batchAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	logger.ErrorContext(ctx, "Unable to restore batch of users, users are restored one by one", slog.Any("error", err))
+	failures := make(map[string]string)
+	for _, user := range batch {
+		if err = bp.patchUsers(bp.getUsersChanges([]common.ConnectionProperties{user}), ctx); err != nil {
+			failures[user.Username] = err.Error()
+		}
+	}
+	return failures
+}
+
+func (bp *BaseProvider) getUsersChanges(users []common.ConnectionProperties) []Change {
+	changes := make([]Change, 0, len(users))
+	for _, properties := range users {
 		changes = append(changes, Change{
 			Operation: "add",
 			Path:      fmt.Sprintf("/%s", properties.Username),
 			Value:     bp.getUserContent(properties),
 		})
 	}
-	position := 0
-	var batch []Change
-	for position < len(changes) {
-		if position+batchSize < len(changes) {
-			batch = changes[position : position+batchSize]
-		} else {
-			batch = changes[position:]
-		}
-		logger.DebugContext(ctx, fmt.Sprintf("Current batch size is %d", len(batch)))
-		var err error
-		// 3 attempts to create corresponding patch of users
-		for i := 0; i < 3; i++ {
-			err = bp.patchUsers(batch, ctx)
-			if err == nil {
-				break
-			}
-			time.Sleep(10 * time.Second)
-		}
-		if err != nil {
-			bp.recoveryState = RecoveryFailedState
-			logger.ErrorContext(ctx, fmt.Sprintf("Unable to restore users because of error: %+v", err))
-			return
-		}
-		position += batchSize
-	}
-	bp.recoveryState = RecoveryDoneState
-	logger.InfoContext(ctx, "Users recovery is successfully finished")
+	return changes
 }
 
 func (bp *BaseProvider) getUserContent(properties common.ConnectionProperties) Content {
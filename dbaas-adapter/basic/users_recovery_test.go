@@ -15,12 +15,70 @@
 package basic
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/Netcracker/dbaas-opensearch-adapter/cluster"
 	"github.com/Netcracker/dbaas-opensearch-adapter/common"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 )
 
+// failingUsersClient rejects patch requests which contain the failed username
+type failingUsersClient struct {
+	failedUsername string
+	lock           sync.Mutex
+	patches        int
+}
+
+func (c *failingUsersClient) Perform(req *http.Request) (*http.Response, error) {
+	c.lock.Lock()
+	c.patches++
+	c.lock.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	if strings.Contains(string(body), fmt.Sprintf("\"/%s\"", c.failedUsername)) {
+		return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader("invalid user"))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+}
+
+func (c *failingUsersClient) Metrics() (opensearchtransport.Metrics, error) {
+	return opensearchtransport.Metrics{}, nil
+}
+
+func (c *failingUsersClient) DiscoverNodes() error {
+	return nil
+}
+
+func newRecoveryProvider(client common.Client) *BaseProvider {
+	return &BaseProvider{
+		opensearch:        &cluster.Opensearch{Host: "localhost", Port: 9200, Protocol: common.Http, Client: client},
+		mutex:             &sync.Mutex{},
+		passwordGenerator: NewPasswordGenerator(),
+		ApiVersion:        common.ApiV2,
+		recovery:          newUsersRecovery(),
+	}
+}
+
+func usersToRecover(count int) []common.ConnectionProperties {
+	users := make([]common.ConnectionProperties, 0, count)
+	for i := 0; i < count; i++ {
+		users = append(users, common.ConnectionProperties{
+			Username: fmt.Sprintf("user%d", i),
+			Password: common.GenerateUUID(),
+			DbName:   "test",
+		})
+	}
+	return users
+}
+
 func TestUserContentWithResourcePrefix(t *testing.T) {
 	username := "admin"
 	password := common.GenerateUUID()
@@ -149,3 +207,75 @@ func TestUserContentWithoutRole(t *testing.T) {
 	assert.EqualValues(t, expectedAttributes, content.Attributes)
 	assert.EqualValues(t, expectedBackendRoles, content.BackendRoles)
 }
+
+func TestRecoverUsersWithFailedUser(t *testing.T) {
+	recoveryRetryDelay = 0
+	client := &failingUsersClient{failedUsername: "user150"}
+	provider := newRecoveryProvider(client)
+	users := usersToRecover(250)
+	assert.True(t, provider.recovery.start(users))
+	provider.recoverUsers(users, context.Background())
+
+	progress := provider.recovery.Progress()
+	assert.Equal(t, RecoveryFailedState, progress.State)
+	assert.Equal(t, 250, progress.Total)
+	assert.Equal(t, 250, progress.Processed)
+	assert.Equal(t, 3, progress.Batches)
+	assert.Equal(t, 0, progress.CurrentBatch)
+	assert.Len(t, progress.FailedUsers, 1)
+	assert.Equal(t, "user150", progress.FailedUsers[0].Username)
+	assert.Contains(t, progress.FailedUsers[0].Reason, "invalid user")
+	// two successful batches, three attempts for the failed one and patches of its users one by one
+	assert.Equal(t, 2+batchAttempts+batchSize, client.patches)
+}
+
+func TestRetryRecoveryProcessesOnlyFailedUsers(t *testing.T) {
+	recoveryRetryDelay = 0
+	client := &failingUsersClient{failedUsername: "user1"}
+	provider := newRecoveryProvider(client)
+	users := usersToRecover(3)
+	assert.True(t, provider.recovery.start(users))
+	provider.recoverUsers(users, context.Background())
+	assert.Equal(t, RecoveryFailedState, provider.recovery.Progress().State)
+	assert.Equal(t, float64(1), testutil.ToFloat64(recoveryStateGauge.WithLabelValues(RecoveryFailedState)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(recoveryStateGauge.WithLabelValues(RecoveryRunningState)))
+	assert.Equal(t, float64(3), testutil.ToFloat64(recoveryUsersGauge.WithLabelValues("processed")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(recoveryUsersGauge.WithLabelValues("failed")))
+
+	client.failedUsername = ""
+	failedUsers, err := provider.recovery.startRetry()
+	assert.Nil(t, err)
+	assert.Len(t, failedUsers, 1)
+	provider.recoverUsers(failedUsers, context.Background())
+
+	progress := provider.recovery.Progress()
+	assert.Equal(t, RecoveryDoneState, progress.State)
+	assert.Equal(t, 1, progress.Total)
+	assert.Equal(t, 1, progress.Processed)
+	assert.Empty(t, progress.FailedUsers)
+	assert.Equal(t, float64(1), testutil.ToFloat64(recoveryStateGauge.WithLabelValues(RecoveryDoneState)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(recoveryUsersGauge.WithLabelValues("failed")))
+
+	_, err = provider.recovery.startRetry()
+	assert.ErrorIs(t, err, errNoFailedUsers)
+}
+
+func TestGetRecoveryStateHandler(t *testing.T) {
+	provider := newRecoveryProvider(common.NewClient())
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/users/restore-password/state", nil)
+	provider.GetRecoveryStateHandler()(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var progress RecoveryProgress
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &progress))
+	assert.Equal(t, RecoveryIdleState, progress.State)
+}
+
+func TestRetryRecoveryHandlerWithoutFailedUsers(t *testing.T) {
+	provider := newRecoveryProvider(common.NewClient())
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/users/restore-password/retry", nil)
+	provider.RetryRecoveryHandler()(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -101,7 +101,7 @@ func NewOpensearch(host string, port int, protocol string, username string, pass
 		Port:     port,
 		Protocol: protocol,
 		Health:   common.ComponentHealth{Status: common.Up},
-		Client:   oc,
+		Client:   common.NewInstrumentedClient(oc),
 	}
 
 	service.Health.Status = service.GetHealth(context.Background())
@@ -42,6 +42,7 @@ const (
 	MetadataKind       = "metadataDocument"
 	ResourcePrefixKind = "resourcePrefix"
 	TemplateKind       = "template"
+	TenantKind         = "tenant"
 	IndexTemplateKind  = "indexTemplate"
 	UserKind           = "user"
 	Down               = "DOWN"
@@ -60,6 +61,7 @@ const (
 
 var (
 	CheckPrefixesUniqueEnabled = GetBoolEnv("CHECK_PREFIXES_UNIQUE_ENABLED", true)
+	DashboardsTenantsEnabled   = GetBoolEnv("DASHBOARDS_TENANTS_ENABLED", false)
 )
 
 type CorrelationID string
@@ -0,0 +1,63 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var clientRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "dbaas_opensearch_client_request_duration_seconds",
+	Help:    "Duration of requests from the adapter to OpenSearch by method, operation and status code",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "operation", "status"})
+
+// InstrumentedClient observes the duration of every request performed through the wrapped client
+type InstrumentedClient struct {
+	Client
+}
+
+func NewInstrumentedClient(client Client) *InstrumentedClient {
+	return &InstrumentedClient{Client: client}
+}
+
+func (c *InstrumentedClient) Perform(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := c.Client.Perform(req)
+	status := "error"
+	if err == nil && response != nil {
+		status = strconv.Itoa(response.StatusCode)
+	}
+	clientRequestDuration.WithLabelValues(req.Method, GetOperation(req.URL.Path), status).
+		Observe(time.Since(start).Seconds())
+	return response, err
+}
+
+// GetOperation returns the OpenSearch API of the request path, e.g. `_search` or `_security`,
+// to keep the number of label values small. Requests to index itself are reported as `index` operation.
+func GetOperation(path string) string {
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "_") && segment != "_plugins" {
+			return segment
+		}
+	}
+	return IndexKind
+}
@@ -0,0 +1,43 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetOperation(t *testing.T) {
+	assert.Equal(t, "_doc", GetOperation("/dbaas_opensearch_metadata/_doc/test"))
+	assert.Equal(t, "_security", GetOperation("/_plugins/_security/api/internalusers/test"))
+	assert.Equal(t, "_cat", GetOperation("/_cat/indices/test*"))
+	assert.Equal(t, IndexKind, GetOperation("/test_index"))
+}
+
+func TestInstrumentedClient(t *testing.T) {
+	client := NewInstrumentedClient(NewClient())
+	request := httptest.NewRequest(http.MethodGet, "/dbaas_opensearch_metadata/_doc/test", nil)
+	response, err := client.Perform(request)
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+	var metric dto.Metric
+	err = clientRequestDuration.WithLabelValues(http.MethodGet, "_doc", "200").(prometheus.Histogram).Write(&metric)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+}
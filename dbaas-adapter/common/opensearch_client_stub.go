@@ -53,6 +53,9 @@ func (cs *ClientStub) Perform(req *http.Request) (*http.Response, error) {
 	case strings.HasPrefix(path, "/_plugins/_security/api/rolesmapping"):
 		role := strings.ReplaceAll(path, "/_plugins/_security/api/rolesmapping", "")
 		body = cs.roleMappingManipulations(role, method)
+	case strings.HasPrefix(path, "/_plugins/_security/api/tenants/"):
+		tenant := strings.ReplaceAll(path, "/_plugins/_security/api/tenants/", "")
+		body = cs.tenantManipulations(tenant, method)
 	case strings.HasPrefix(path, "/_plugins/_security/api/internalusers"):
 		username := strings.ReplaceAll(path, "/_plugins/_security/api/internalusers/", "")
 		body = cs.userManipulations(username, method)
@@ -173,6 +176,20 @@ func (cs *ClientStub) userManipulations(name string, method string) string {
 	}
 }
 
+func (cs *ClientStub) tenantManipulations(name string, method string) string {
+	switch method {
+	case http.MethodGet:
+		return fmt.Sprintf(`{"%s":{"reserved":false,"hidden":false,"description":"Tenant of '%s' database","static":false}}`, name, name)
+	case http.MethodDelete:
+		return fmt.Sprintf(`{"status":"OK","message":"'%s' deleted."}`, name)
+	case http.MethodPut:
+		return fmt.Sprintf(`{"status":"CREATED","message":"'%s' created."}`, name)
+	default:
+		logger.Error(fmt.Sprintf("Tenant operations do not include '%s' method", method))
+		return ""
+	}
+}
+
 func (cs *ClientStub) templateManipulations(name string, method string) string {
 	switch method {
 	case http.MethodGet:
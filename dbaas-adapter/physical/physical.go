@@ -29,10 +29,23 @@ import (
 	cl "github.com/Netcracker/dbaas-opensearch-adapter/client"
 	"github.com/Netcracker/dbaas-opensearch-adapter/common"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
-var logger = common.GetLogger()
+var (
+	logger = common.GetLogger()
+
+	registrationAttemptsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dbaas_opensearch_registration_attempts_total",
+		Help: "Number of attempts to register physical database in DBaaS aggregator by result",
+	}, []string{"result"})
+	registrationStatusGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dbaas_opensearch_registration_status",
+		Help: "Result of the last registration attempt, 1 if physical database is registered and 0 otherwise",
+	})
+)
 
 type Database struct {
 	Id     string            `json:"id"`
@@ -208,9 +221,13 @@ func (rs *RegistrationProvider) doRegistrationRequest() {
 			//}
 			logger.InfoContext(ctx, fmt.Sprintf("Recovered from physical database registration panic, set health PROBLEM: %s", message))
 			rs.Health = common.ComponentHealth{Status: "PROBLEM"}
+			registrationAttemptsCounter.WithLabelValues("failure").Inc()
+			registrationStatusGauge.Set(0)
 		} else {
 			logger.InfoContext(ctx, "Successfully registered physical database, set health OK")
 			rs.Health = common.ComponentHealth{Status: "OK"}
+			registrationAttemptsCounter.WithLabelValues("success").Inc()
+			registrationStatusGauge.Set(1)
 		}
 	}()
 	method, url, body := rs.prepareRequestParameters(ctx)
@@ -0,0 +1,63 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	r := mux.NewRouter()
+	r.Use(MetricsMiddleware)
+	r.HandleFunc("/databases/{dbName}/metadata", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["dbName"] == "absent" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}).Methods(http.MethodPut)
+
+	for _, dbName := range []string{"first", "second", "absent"} {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/databases/"+dbName+"/metadata", nil))
+	}
+
+	route := "/databases/{dbName}/metadata"
+	assert.Equal(t, float64(2), testutil.ToFloat64(httpRequestsCounter.WithLabelValues(route, http.MethodPut, "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(httpRequestsCounter.WithLabelValues(route, http.MethodPut, "404")))
+}
+
+func TestRegisterCollectorTwice(t *testing.T) {
+	first := prometheus.NewGauge(prometheus.GaugeOpts{Name: "dbaas_opensearch_test_collector"})
+	second := prometheus.NewGauge(prometheus.GaugeOpts{Name: "dbaas_opensearch_test_collector"})
+	defer prometheus.Unregister(second)
+	first.Set(1)
+	second.Set(2)
+	assert.Nil(t, registerCollector(first))
+	assert.Nil(t, registerCollector(second))
+	// the collector registered last is used
+	assert.Nil(t, testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(`
+# HELP dbaas_opensearch_test_collector 
+# TYPE dbaas_opensearch_test_collector gauge
+dbaas_opensearch_test_collector 2
+`), "dbaas_opensearch_test_collector"))
+}
@@ -16,10 +16,10 @@ package server
 
 import (
 	"context"
-	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/Netcracker/dbaas-opensearch-adapter/auth"
 	"github.com/Netcracker/dbaas-opensearch-adapter/backup"
 	"github.com/Netcracker/dbaas-opensearch-adapter/basic"
 	cl "github.com/Netcracker/dbaas-opensearch-adapter/client"
@@ -30,6 +30,8 @@ import (
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"log/slog"
 	"net/http"
 	"os"
@@ -57,6 +59,16 @@ var (
 
 	labelsFilename    = common.GetEnv("LABELS_FILE_LOCATION_NAME", "dbaas.physical_databases.registration.labels.json")
 	labelsLocationDir = common.GetEnv("LABELS_FILE_LOCATION_DIR", "/app/config/")
+	roleTypesFile     = common.GetEnv("ROLE_TYPES_FILE_LOCATION", "/app/roles/dbaas.role_types.json")
+
+	quotaCheckInterval = common.GetIntEnv("QUOTA_CHECK_INTERVAL_SECONDS", 300)
+
+	orphansCheckInterval = common.GetIntEnv("ORPHANS_CHECK_INTERVAL_SECONDS", 3600)
+	orphansGCEnabled     = common.GetBoolEnv("ORPHANS_GC_ENABLED", false)
+	orphansGCGracePeriod = common.GetIntEnv("ORPHANS_GC_GRACE_PERIOD_SECONDS", 86400)
+
+	authConfigFile            = common.GetEnv("AUTH_CONFIG_FILE_LOCATION", "/app/auth/dbaas.auth.json")
+	credentialsReloadInterval = common.GetIntEnv("CREDENTIALS_RELOAD_INTERVAL_SECONDS", 30)
 	//nolint:errcheck
 	registrationEnabled, _ = strconv.ParseBool(common.GetEnv("REGISTRATION_ENABLED", "false"))
 )
@@ -72,7 +84,14 @@ func Server(ctx context.Context, adapterAddress string, adapterUsername string,
 		},
 	}
 
-	hnd := Handlers(ctx, adapter)
+	logger := common.GetLogger()
+	authConfig, err := auth.LoadConfig(authConfigFile)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to load authentication configuration", slog.Any("error", err))
+		return
+	}
+
+	hnd := Handlers(ctx, adapter, authConfig)
 	if hnd == nil {
 		return
 	}
@@ -83,7 +102,13 @@ func Server(ctx context.Context, adapterAddress string, adapterUsername string,
 	}
 
 	isTlsEnabled := strings.Contains(adapterAddress, common.Https)
-	logger := common.GetLogger()
+	if isTlsEnabled && authConfig.ClientCertificate.Enabled {
+		server.TLSConfig, err = auth.ServerTLSConfig(fmt.Sprintf("%s/ca.crt", certificatesFolder))
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to configure client certificate authentication", slog.Any("error", err))
+			return
+		}
+	}
 
 	go func() {
 		var err error
@@ -103,14 +128,14 @@ func Server(ctx context.Context, adapterAddress string, adapterUsername string,
 	deadlineCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err := server.Shutdown(deadlineCtx)
+	err = server.Shutdown(deadlineCtx)
 	if err != nil {
 		logger.Error("failed to shutdown server")
 	}
 	logger.Info("server is down gracefully")
 }
 
-func Handlers(ctx context.Context, adapter common.Component) http.Handler {
+func Handlers(ctx context.Context, adapter common.Component, authConfig auth.Config) http.Handler {
 	opensearch := cluster.NewOpensearch(opensearchHost, opensearchPort,
 		opensearchProtocol, opensearchUsername, opensearchPassword)
 	baseProvider := basic.NewBaseProvider(opensearch)
@@ -118,9 +143,22 @@ func Handlers(ctx context.Context, adapter common.Component) http.Handler {
 	if err != nil {
 		return nil
 	}
+	// Role types must be loaded before registration, because they are sent as supported roles
+	createBasicRoles(baseProvider)
 	registrationProvider := startRegistration(adapter.Address, adapter.Credentials.Username,
 		adapter.Credentials.Password, baseProvider)
-	createBasicRoles(baseProvider)
+	quotaChecker := basic.NewQuotaChecker(baseProvider, time.Duration(quotaCheckInterval)*time.Second)
+	quotaChecker.Start(ctx)
+	bulkDropTracker := basic.NewBulkDropTracker(baseProvider)
+	if err = bulkDropTracker.Start(ctx); err != nil {
+		common.GetLogger().ErrorContext(ctx, "Failed to resume bulk drop operations", slog.Any("error", err))
+	}
+	orphansCollector := basic.NewOrphansCollector(baseProvider, time.Duration(orphansCheckInterval)*time.Second,
+		time.Duration(orphansGCGracePeriod)*time.Second, orphansGCEnabled)
+	orphansCollector.Start(ctx)
+	if err = registerCollector(basic.NewDatabasesCollector(baseProvider)); err != nil {
+		common.GetLogger().ErrorContext(ctx, "Failed to register databases collector", slog.Any("error", err))
+	}
 	curatorBaseClient := cl.ConfigureCuratorClient()
 	backupProvider := backup.NewBackupProvider(opensearch.Client, curatorBaseClient, opensearchRepoRoot)
 	basePath := fmt.Sprintf("/api/%s/dbaas/adapter/opensearch", registrationProvider.ApiVersion)
@@ -132,12 +170,21 @@ func Handlers(ctx context.Context, adapter common.Component) http.Handler {
 		Opensearch:            opensearch,
 	}
 
+	authenticator, err := newAuthenticator(ctx, adapter, authConfig)
+	if err != nil {
+		common.GetLogger().ErrorContext(ctx, "Failed to configure authentication", slog.Any("error", err))
+		return nil
+	}
+
 	r := mux.NewRouter()
-	authorizer := BasicAuthorizer(adapter.Credentials.Username, adapter.Credentials.Password,
-		"This API is for using by DBaaS aggregator only")
+	r.Use(MetricsMiddleware)
+	authorizer := authenticator.Authorizer(auth.FullAccess)
+	readAuthorizer := authenticator.Authorizer(auth.ReadOnlyAccess)
 
 	r.HandleFunc("/health", healthService.HealthHandler()).Methods(http.MethodGet)
 
+	r.Handle("/metrics", promhttp.Handler()).Methods(http.MethodGet)
+
 	r.HandleFunc(fmt.Sprintf("%s/supports", basePath), baseProvider.SupportsHandler()).Methods(http.MethodGet)
 
 	r.Handle(fmt.Sprintf("%s/databases", basePath),
@@ -145,11 +192,31 @@ func Handlers(ctx context.Context, adapter common.Component) http.Handler {
 	).Methods(http.MethodPost)
 
 	r.Handle(fmt.Sprintf("%s/databases", basePath),
-		handlers.LoggingHandler(os.Stdout, authorizer(baseProvider.ListDatabasesHandler())),
+		handlers.LoggingHandler(os.Stdout, readAuthorizer(baseProvider.ListDatabasesHandler())),
 	).Methods(http.MethodGet)
 
 	r.Handle(fmt.Sprintf("%s/resources/bulk-drop", basePath),
-		handlers.LoggingHandler(os.Stdout, authorizer(baseProvider.BulkDropResourceHandler())),
+		handlers.LoggingHandler(os.Stdout, authorizer(bulkDropTracker.BulkDropResourceHandler(basePath))),
+	).Methods(http.MethodPost)
+
+	r.Handle(fmt.Sprintf("%s/resources/bulk-drop/{operationId}", basePath),
+		handlers.LoggingHandler(os.Stdout, readAuthorizer(bulkDropTracker.TrackBulkDropHandler())),
+	).Methods(http.MethodGet)
+
+	r.Handle(fmt.Sprintf("%s/describe/databases", basePath),
+		handlers.LoggingHandler(os.Stdout, readAuthorizer(baseProvider.DescribeDatabasesHandler())),
+	).Methods(http.MethodPost)
+
+	r.Handle(fmt.Sprintf("%s/quotas/violations", basePath),
+		handlers.LoggingHandler(os.Stdout, readAuthorizer(quotaChecker.ViolationsHandler())),
+	).Methods(http.MethodGet)
+
+	r.Handle(fmt.Sprintf("%s/orphans", basePath),
+		handlers.LoggingHandler(os.Stdout, readAuthorizer(orphansCollector.AuditHandler())),
+	).Methods(http.MethodGet)
+
+	r.Handle(fmt.Sprintf("%s/orphans/gc", basePath),
+		handlers.LoggingHandler(os.Stdout, authorizer(orphansCollector.CollectHandler())),
 	).Methods(http.MethodPost)
 
 	r.Handle(fmt.Sprintf("%s/databases/{dbName}/metadata", basePath),
@@ -169,15 +236,15 @@ func Handlers(ctx context.Context, adapter common.Component) http.Handler {
 	).Methods(http.MethodPost)
 
 	r.Handle(fmt.Sprintf("%s/backups/track/backup/{backupID}", basePath),
-		handlers.LoggingHandler(os.Stdout, authorizer(backupProvider.TrackBackupHandler())),
+		handlers.LoggingHandler(os.Stdout, readAuthorizer(backupProvider.TrackBackupHandler())),
 	).Methods(http.MethodGet)
 
 	r.Handle(fmt.Sprintf("%s/backups/track/restore/{backupID}", basePath),
-		handlers.LoggingHandler(os.Stdout, authorizer(backupProvider.TrackRestoreFromTrackIdHandler(opensearchRepo))),
+		handlers.LoggingHandler(os.Stdout, readAuthorizer(backupProvider.TrackRestoreFromTrackIdHandler(opensearchRepo))),
 	).Methods(http.MethodGet)
 
 	r.Handle(fmt.Sprintf("%s/backups/track/restoring/backups/{backupID}/indices/{indices}", basePath),
-		handlers.LoggingHandler(os.Stdout, authorizer(backupProvider.TrackRestoreFromIndicesHandler(opensearchRepo))),
+		handlers.LoggingHandler(os.Stdout, readAuthorizer(backupProvider.TrackRestoreFromIndicesHandler(opensearchRepo))),
 	).Methods(http.MethodGet)
 
 	r.Handle(fmt.Sprintf("%s/backups/{backupID}", basePath),
@@ -185,7 +252,7 @@ func Handlers(ctx context.Context, adapter common.Component) http.Handler {
 	).Methods(http.MethodDelete)
 
 	r.Handle(fmt.Sprintf("%s/physical_database", basePath),
-		handlers.LoggingHandler(os.Stdout, authorizer(registrationProvider.GetPhysicalDatabaseHandler())),
+		handlers.LoggingHandler(os.Stdout, readAuthorizer(registrationProvider.GetPhysicalDatabaseHandler())),
 	).Methods(http.MethodGet)
 
 	r.Handle(fmt.Sprintf("/api/%s/dbaas/adapter/physical_database/force_registration", registrationProvider.ApiVersion),
@@ -201,12 +268,36 @@ func Handlers(ctx context.Context, adapter common.Component) http.Handler {
 	).Methods(http.MethodPut)
 
 	if registrationProvider.ApiVersion == common.ApiV2 {
+		r.Handle(fmt.Sprintf("%s/databases/{dbName}/settings", basePath),
+			handlers.LoggingHandler(os.Stdout, authorizer(baseProvider.UpdateSettingsHandler())),
+		).Methods(http.MethodPut)
+
+		r.Handle(fmt.Sprintf("%s/databases/{dbName}/password-rotation", basePath),
+			handlers.LoggingHandler(os.Stdout, authorizer(baseProvider.RotatePasswordsHandler())),
+		).Methods(http.MethodPost)
+
+		r.Handle(fmt.Sprintf("%s/databases/{dbName}/password-rotation/confirm", basePath),
+			handlers.LoggingHandler(os.Stdout, authorizer(baseProvider.ConfirmPasswordRotationHandler())),
+		).Methods(http.MethodPost)
+
 		r.Handle(fmt.Sprintf("%s/users/restore-password", basePath),
 			handlers.LoggingHandler(os.Stdout, authorizer(baseProvider.RecoverUsersHandler())),
 		).Methods(http.MethodPost)
 
+		r.Handle(fmt.Sprintf("%s/users/restore-password/retry", basePath),
+			handlers.LoggingHandler(os.Stdout, authorizer(baseProvider.RetryRecoveryHandler())),
+		).Methods(http.MethodPost)
+
 		r.Handle(fmt.Sprintf("%s/users/restore-password/state", basePath),
-			handlers.LoggingHandler(os.Stdout, authorizer(baseProvider.GetRecoveryStateHandler())),
+			handlers.LoggingHandler(os.Stdout, readAuthorizer(baseProvider.GetRecoveryStateHandler())),
+		).Methods(http.MethodGet)
+
+		r.Handle(fmt.Sprintf("%s/databases/{dbName}/clone", basePath),
+			handlers.LoggingHandler(os.Stdout, authorizer(backupProvider.CloneDatabaseHandler(baseProvider, opensearchRepo, basePath))),
+		).Methods(http.MethodPost)
+
+		r.Handle(fmt.Sprintf("%s/backups/track/clone/{trackID}", basePath),
+			handlers.LoggingHandler(os.Stdout, readAuthorizer(backupProvider.CloneTrackHandler(opensearchRepo, basePath))),
 		).Methods(http.MethodGet)
 
 		r.Handle(fmt.Sprintf("%s/backups/backup", basePath),
@@ -214,7 +305,7 @@ func Handlers(ctx context.Context, adapter common.Component) http.Handler {
 		).Methods(http.MethodPost)
 
 		r.Handle(fmt.Sprintf("%s/backups/backup/{backupId}", basePath),
-			RecoverMiddleware(handlers.LoggingHandler(os.Stdout, authorizer(backupProvider.TrackBackupV2Handler()))),
+			RecoverMiddleware(handlers.LoggingHandler(os.Stdout, readAuthorizer(backupProvider.TrackBackupV2Handler()))),
 		).Methods(http.MethodGet)
 
 		r.Handle(fmt.Sprintf("%s/backups/backup/{backupId}/restore", basePath),
@@ -222,7 +313,7 @@ func Handlers(ctx context.Context, adapter common.Component) http.Handler {
 		).Methods(http.MethodPost)
 
 		r.Handle(fmt.Sprintf("%s/backups/restore/{restoreId}", basePath),
-			RecoverMiddleware(handlers.LoggingHandler(os.Stdout, authorizer(backupProvider.TrackRestoreV2Handler()))),
+			RecoverMiddleware(handlers.LoggingHandler(os.Stdout, readAuthorizer(backupProvider.TrackRestoreV2Handler()))),
 		).Methods(http.MethodGet)
 
 		r.Handle(fmt.Sprintf("%s/backups/backup/{backupId}", basePath),
@@ -237,6 +328,18 @@ func Handlers(ctx context.Context, adapter common.Component) http.Handler {
 	return JsonContentType(handlers.CompressHandler(r))
 }
 
+// registerCollector registers the collector replacing the one registered by previous handlers,
+// so metrics are collected with the current provider
+func registerCollector(collector prometheus.Collector) error {
+	err := prometheus.Register(collector)
+	var registeredErr prometheus.AlreadyRegisteredError
+	if errors.As(err, &registeredErr) {
+		prometheus.Unregister(registeredErr.ExistingCollector)
+		err = prometheus.Register(collector)
+	}
+	return err
+}
+
 func JsonContentType(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -261,6 +364,33 @@ func RecoverMiddleware(h http.Handler) http.Handler {
 	})
 }
 
+// newAuthenticator combines enabled authentication strategies. Client certificates are checked first,
+// because they are verified during TLS handshake, then credentials from Authorization header.
+func newAuthenticator(ctx context.Context, adapter common.Component, authConfig auth.Config) (*auth.Chain, error) {
+	var authenticators []auth.Authenticator
+	if authConfig.ClientCertificate.Enabled {
+		if strings.Contains(adapter.Address, common.Https) {
+			authenticators = append(authenticators, auth.NewCertificateAuthenticator(authConfig.ClientCertificate))
+		} else {
+			common.GetLogger().WarnContext(ctx, "Client certificate authentication is enabled, but TLS is disabled, so it is not used")
+		}
+	}
+	basicAuthenticator, err := auth.NewBasicAuthenticator(adapter.Credentials.Username, adapter.Credentials.Password)
+	if err != nil {
+		return nil, err
+	}
+	basicAuthenticator.Start(ctx, time.Duration(credentialsReloadInterval)*time.Second)
+	authenticators = append(authenticators, basicAuthenticator)
+	if authConfig.TokenReview.Enabled {
+		tokenReviewAuthenticator, err := auth.NewTokenReviewAuthenticator(authConfig.TokenReview)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, tokenReviewAuthenticator)
+	}
+	return auth.NewChain("This API is for using by DBaaS aggregator only", authenticators...), nil
+}
+
 func startRegistration(adapterAddress string, adapterUsername string, adapterPassword string,
 	baseProvider *basic.BaseProvider) *physical.RegistrationProvider {
 	dbaasAggregatorCredentials := dao.BasicAuth{
@@ -296,16 +426,10 @@ func createBasicRoles(baseProvider *basic.BaseProvider) {
 	if err != nil {
 		panic(err)
 	}
-	if err = baseProvider.CreateRoleWithISMPermissions(); err != nil {
-		panic(err)
-	}
-	if err = baseProvider.CreateRoleWithAdminPermissions(); err != nil {
-		panic(err)
-	}
-	if err = baseProvider.CreateRoleWithDMLPermissions(); err != nil {
+	if err = baseProvider.LoadRoleTypes(roleTypesFile); err != nil {
 		panic(err)
 	}
-	if err = baseProvider.CreateRoleWithReadOnlyPermissions(); err != nil {
+	if err = baseProvider.CreateRoles(); err != nil {
 		panic(err)
 	}
 	// migration is necessary if specific roles mapping does not exist
@@ -327,7 +451,7 @@ func performMigration(baseProvider *basic.BaseProvider) error {
 		return err
 	}
 	for role, mapping := range rolesMapping {
-		if relevantForMigration(role, mapping) {
+		if relevantForMigration(role, mapping, baseProvider.GetSupportedRoleTypes()) {
 			if err = updateUserConfiguration(mapping.Users[0], role, baseProvider); err != nil {
 				return err
 			}
@@ -336,10 +460,19 @@ func performMigration(baseProvider *basic.BaseProvider) error {
 	return nil
 }
 
-func relevantForMigration(roleName string, roleMapping basic.RoleMapping) bool {
-	return (strings.HasSuffix(roleName, "_role") || strings.HasSuffix(roleName, "_dml") ||
-		strings.HasSuffix(roleName, "_readonly") || strings.HasSuffix(roleName, "_admin") ||
-		strings.HasSuffix(roleName, "_ism")) && !roleMapping.Reserved && len(roleMapping.Users) == 1
+func relevantForMigration(roleName string, roleMapping basic.RoleMapping, roleTypes []string) bool {
+	if roleMapping.Reserved || len(roleMapping.Users) != 1 {
+		return false
+	}
+	if strings.HasSuffix(roleName, "_role") {
+		return true
+	}
+	for _, roleType := range roleTypes {
+		if strings.HasSuffix(roleName, fmt.Sprintf("_%s", roleType)) {
+			return true
+		}
+	}
+	return false
 }
 
 func updateUserConfiguration(username string, roleName string, baseProvider *basic.BaseProvider) error {
@@ -363,20 +496,3 @@ func updateUserConfiguration(username string, roleName string, baseProvider *bas
 	roleType := baseProvider.DefineRoleType(roleName)
 	return baseProvider.PatchUser(username, "", pattern, roleType, context.Background())
 }
-
-func BasicAuthorizer(username string, password string, realm string) func(func(w http.ResponseWriter, r *http.Request)) http.Handler {
-	return func(f func(w http.ResponseWriter, r *http.Request)) http.Handler {
-		h := http.HandlerFunc(f)
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx := r.Context()
-			user, pass, ok := r.BasicAuth()
-
-			if !ok || subtle.ConstantTimeCompare([]byte(user), []byte(username)) != 1 || subtle.ConstantTimeCompare([]byte(pass), []byte(password)) != 1 {
-				w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
-				common.ProcessResponseBody(ctx, w, []byte("Not authorized to use this API, only DBaaS aggregator can use it.\n"), http.StatusUnauthorized)
-				return
-			}
-			h.ServeHTTP(w, r)
-		})
-	}
-}
@@ -110,7 +110,93 @@ type DisasterRecovery struct {
 	ConfigMapName              string `json:"configMapName"`
 	ReplicationWatcherEnabled  bool   `json:"replicationWatcherEnabled,omitempty"`
 	ReplicationWatcherInterval int    `json:"replicationWatcherInterval,omitempty"`
-	DeleteFollowerIndex        bool   `json:"deleteFollowerIndex,omitempty"`
+	// ReplicationWatcherMaxRestarts - Maximum number of replication restarts by the watcher within the restart window.
+	// The watcher is stopped when it is reached.
+	ReplicationWatcherMaxRestarts int `json:"replicationWatcherMaxRestarts,omitempty"`
+	// ReplicationWatcherRestartWindow - Window in seconds the replication restarts are counted within.
+	ReplicationWatcherRestartWindow int                `json:"replicationWatcherRestartWindow,omitempty"`
+	DeleteFollowerIndex             bool               `json:"deleteFollowerIndex,omitempty"`
+	ConfigurationSync               *ConfigurationSync `json:"configurationSync,omitempty"`
+	ConsistencyCheck                *ConsistencyCheck  `json:"consistencyCheck,omitempty"`
+	// Failover - Whether the switchover to `active` mode is performed without the other side,
+	// for example, when the other side is unavailable.
+	Failover         bool              `json:"failover,omitempty"`
+	Fencing          *Fencing          `json:"fencing,omitempty"`
+	SnapshotShipping *SnapshotShipping `json:"snapshotShipping,omitempty"`
+	ReplicationPause *ReplicationPause `json:"replicationPause,omitempty"`
+}
+
+// ReplicationPause defines temporary pause of follower indices replication on the standby side,
+// for example, during network maintenance between sides
+type ReplicationPause struct {
+	// Enabled - Whether replication is paused. Replication of paused indices is resumed when it is disabled.
+	Enabled bool `json:"enabled,omitempty"`
+	// Pattern - Wildcard pattern of follower indices to pause. All replicated indices are paused if it is empty.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// Fencing defines split-brain protection with the epoch exchanged between sides
+type Fencing struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// SiteName - Unique name of the current side. It is stored in the witness.
+	SiteName string `json:"siteName,omitempty"`
+	// RemoteUrl - URL of the operator Disaster Recovery server on the other side,
+	// for example "http://opensearch-disaster-recovery.opensearch-service.svc.cluster-2.local:8069".
+	RemoteUrl string `json:"remoteUrl,omitempty"`
+	// SecretName - Name of the secret with "token" used by both sides to authenticate fencing requests.
+	SecretName string          `json:"secretName"`
+	Witness    *FencingWitness `json:"witness,omitempty"`
+}
+
+// FencingWitness defines the third-party object storing the epoch of the last side switched to `active` mode
+type FencingWitness struct {
+	// Kind - Can be "ConfigMap" or "Lease".
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// KubeconfigSecretName - Name of the secret with "kubeconfig" of the shared cluster. The current cluster is used if it is empty.
+	KubeconfigSecretName string `json:"kubeconfigSecretName,omitempty"`
+}
+
+// SnapshotShipping defines Disaster Recovery based on snapshots shipped through the repository shared by both sides
+// instead of the cross cluster replication
+type SnapshotShipping struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// RepositoryName - Name of the snapshots repository registered on both sides with the same storage.
+	// The repository from `opensearch.snapshots` is used if it is empty.
+	RepositoryName string `json:"repositoryName,omitempty"`
+	// Interval - Interval in seconds between snapshots on the active side and restores on the standby side.
+	Interval int `json:"interval,omitempty"`
+	// Retention - Number of the last Disaster Recovery snapshots kept in the repository.
+	Retention int `json:"retention,omitempty"`
+	// RenamePattern and RenameReplacement - Regular expression and replacement for names of restored indices.
+	RenamePattern     string `json:"renamePattern,omitempty"`
+	RenameReplacement string `json:"renameReplacement,omitempty"`
+}
+
+// ConfigurationSync defines copying of security configuration, templates and ISM policies
+// from the active side to the standby one
+type ConfigurationSync struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// RemoteUrl - REST URL of OpenSearch on the other side, for example "https://opensearch.opensearch-service.svc.cluster-2.local:9200".
+	RemoteUrl string `json:"remoteUrl"`
+	// SecretName - Name of the secret with "username" and "password" for the other side. Local credentials are used if it is empty.
+	SecretName string `json:"secretName,omitempty"`
+	// Interval - Interval in seconds between synchronizations.
+	Interval int `json:"interval,omitempty"`
+	// Objects - Kinds of objects to synchronize: "roles", "rolesMapping", "internalUsers", "componentTemplates",
+	// "indexTemplates" and "ismPolicies". All kinds are synchronized if it is empty.
+	Objects []string `json:"objects,omitempty"`
+}
+
+// ConsistencyCheck defines verification of replicated data between the current side and the leader one
+type ConsistencyCheck struct {
+	// Enabled - Whether the verification is run periodically on the standby side.
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval - Interval in seconds between verifications.
+	Interval int `json:"interval,omitempty"`
+	// SampleSize - Number of documents per index to compare by ID and sequence number. Only documents count is compared if it is 0.
+	SampleSize int `json:"sampleSize,omitempty"`
 }
 
 // OpenSearchServiceSpec defines the desired state of OpenSearchService
@@ -127,11 +213,163 @@ type OpenSearchServiceSpec struct {
 }
 
 type DisasterRecoveryStatus struct {
-	Mode               string `json:"mode"`
+	Mode               string                   `json:"mode"`
+	Status             string                   `json:"status"`
+	Comment            string                   `json:"comment,omitempty"` // deprecated
+	Message            string                   `json:"message,omitempty"`
+	UsersRecoveryState string                   `json:"usersRecoveryState,omitempty"`
+	UsersRecovery      *UsersRecoveryStatus     `json:"usersRecovery,omitempty"`
+	ReplicationActions []ReplicationAction      `json:"replicationActions,omitempty"`
+	ConfigurationSync  *ConfigurationSyncStatus `json:"configurationSync,omitempty"`
+	SwitchoverReport   *SwitchoverReport        `json:"switchoverReport,omitempty"`
+	ConsistencyCheck   *ConsistencyCheckStatus  `json:"consistencyCheck,omitempty"`
+	Failover           *FailoverStatus          `json:"failover,omitempty"`
+	Fencing            *FencingStatus           `json:"fencing,omitempty"`
+	SnapshotShipping   *SnapshotShippingStatus  `json:"snapshotShipping,omitempty"`
+	ReplicationPause   *ReplicationPauseStatus  `json:"replicationPause,omitempty"`
+	// History - Last switchovers from the oldest to the newest one.
+	History []SwitchoverHistoryEntry `json:"history,omitempty"`
+}
+
+// UsersRecoveryStatus shows the progress of the last users recovery reported by DBaaS adapter
+type UsersRecoveryStatus struct {
+	Total     int `json:"total"`
+	Processed int `json:"processed"`
+	// CurrentBatch - Number of the batch being processed starting from 1. It is absent when recovery is not running.
+	CurrentBatch int                    `json:"currentBatch,omitempty"`
+	Batches      int                    `json:"batches,omitempty"`
+	FailedUsers  []UsersRecoveryFailure `json:"failedUsers,omitempty"`
+}
+
+// UsersRecoveryFailure describes the user which is not recovered
+type UsersRecoveryFailure struct {
+	Username string `json:"username"`
+	Reason   string `json:"reason"`
+}
+
+// ReplicationPauseStatus shows follower indices which replication is paused by the operator
+type ReplicationPauseStatus struct {
+	Indices []string `json:"indices,omitempty"`
+	Time    string   `json:"time"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// FencingStatus shows the epoch of the current side and the conflict with the other side if any
+type FencingStatus struct {
+	// Epoch - Epoch the current side was switched to `active` mode with or the epoch of the active side it follows.
+	Epoch    int64  `json:"epoch"`
+	Conflict string `json:"conflict,omitempty"`
+}
+
+// SnapshotShippingStatus shows the last snapshot taken on the active side or restored on the standby side
+type SnapshotShippingStatus struct {
+	Mode     string `json:"mode"`
+	Snapshot string `json:"snapshot,omitempty"`
+	// SnapshotTime - Start time of the snapshot.
+	SnapshotTime string `json:"snapshotTime,omitempty"`
+	// LastSyncTime - Time of the last successful snapshot on the active side or check for a new snapshot on the standby side.
+	LastSyncTime string `json:"lastSyncTime,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// ConfigurationSyncStatus shows the result of the last configuration synchronization from the active side
+type ConfigurationSyncStatus struct {
+	LastSyncTime string `json:"lastSyncTime,omitempty"`
+	// Synced - Number of objects created or updated during the last synchronization.
+	Synced    int                     `json:"synced,omitempty"`
+	Conflicts []ConfigurationConflict `json:"conflicts,omitempty"`
+}
+
+// ConfigurationConflict describes an object which cannot be synchronized from the active side
+type ConfigurationConflict struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// SwitchoverReport shows the result of switchover checks performed without any changes on the side
+type SwitchoverReport struct {
+	// Request - Value of the annotation the dry-run is triggered with.
+	Request    string `json:"request"`
+	TargetMode string `json:"targetMode"`
+	Time       string `json:"time"`
+	// Ready - Whether the switchover to the target mode would succeed.
+	Ready bool `json:"ready"`
+	// EstimatedCatchUpSeconds - Estimated time of the final replication catch-up. It is absent if it cannot be estimated.
+	EstimatedCatchUpSeconds *int64            `json:"estimatedCatchUpSeconds,omitempty"`
+	Checks                  []SwitchoverCheck `json:"checks,omitempty"`
+}
+
+// SwitchoverCheck describes the result of one switchover check
+type SwitchoverCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// ConsistencyCheckStatus shows the result of the last verification of replicated data
+type ConsistencyCheckStatus struct {
+	// Request - Value of the annotation the verification is triggered with. It is empty for periodic verifications.
+	Request string `json:"request,omitempty"`
+	// Mode - Disaster Recovery mode of the side during the verification.
+	Mode           string          `json:"mode"`
+	Time           string          `json:"time"`
+	Consistent     bool            `json:"consistent"`
+	CheckedIndices int             `json:"checkedIndices"`
+	Mismatches     []IndexMismatch `json:"mismatches,omitempty"`
+}
+
+// IndexMismatch describes the difference between the local index and the leader one
+type IndexMismatch struct {
+	Index       string `json:"index"`
+	LocalCount  int64  `json:"localCount"`
+	LeaderCount int64  `json:"leaderCount"`
+	Reason      string `json:"reason"`
+}
+
+// FailoverStatus shows the result of the last failover to `active` mode
+type FailoverStatus struct {
+	Time string `json:"time"`
+	// EstimatedLostOperations - Sum of the differences between last known leader and follower checkpoints of all indices.
+	EstimatedLostOperations int64             `json:"estimatedLostOperations"`
+	Indices                 []IndexCheckpoint `json:"indices,omitempty"`
+	// ResyncRequired - Whether the other side has not been resynchronized with the current one after failover yet.
+	ResyncRequired bool `json:"resyncRequired"`
+}
+
+// IndexCheckpoint describes the last known replication state of the follower index
+type IndexCheckpoint struct {
+	Index              string `json:"index"`
 	Status             string `json:"status"`
-	Comment            string `json:"comment,omitempty"` // deprecated
-	Message            string `json:"message,omitempty"`
+	LeaderCheckpoint   int64  `json:"leaderCheckpoint"`
+	FollowerCheckpoint int64  `json:"followerCheckpoint"`
+}
+
+// ReplicationAction describes an action performed by the replication watcher to repair replication
+type ReplicationAction struct {
+	// Index - Name of the follower index or empty for actions with the whole replication.
+	Index string `json:"index,omitempty"`
+	// Action - Can be "resume", "refollow" or "restart".
+	Action string `json:"action"`
+	// Result - Can be "succeeded" or "failed".
+	Result  string `json:"result"`
+	Message string `json:"message,omitempty"`
+	Time    string `json:"time"`
+}
+
+// SwitchoverHistoryEntry shows the result of the switchover to the requested mode
+type SwitchoverHistoryEntry struct {
+	Mode      string `json:"mode"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	// Result - Can be "done" or "failed".
+	Result             string `json:"result"`
 	UsersRecoveryState string `json:"usersRecoveryState,omitempty"`
+	// ReplicationCheck - Can be "passed", "failed" or "skipped".
+	ReplicationCheck string `json:"replicationCheck,omitempty"`
+	Error            string `json:"error,omitempty"`
+	// Attempts - Number of attempts of the switchover to the same mode, retries update the last entry.
+	Attempts int `json:"attempts,omitempty"`
 }
 
 // OpenSearchServiceStatus defines the observed state of OpenSearchService
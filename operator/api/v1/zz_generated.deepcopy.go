@@ -9,6 +9,96 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationConflict) DeepCopyInto(out *ConfigurationConflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationConflict.
+func (in *ConfigurationConflict) DeepCopy() *ConfigurationConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationSync) DeepCopyInto(out *ConfigurationSync) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSync.
+func (in *ConfigurationSync) DeepCopy() *ConfigurationSync {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationSyncStatus) DeepCopyInto(out *ConfigurationSyncStatus) {
+	*out = *in
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]ConfigurationConflict, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSyncStatus.
+func (in *ConfigurationSyncStatus) DeepCopy() *ConfigurationSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsistencyCheck) DeepCopyInto(out *ConsistencyCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsistencyCheck.
+func (in *ConsistencyCheck) DeepCopy() *ConsistencyCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsistencyCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsistencyCheckStatus) DeepCopyInto(out *ConsistencyCheckStatus) {
+	*out = *in
+	if in.Mismatches != nil {
+		in, out := &in.Mismatches, &out.Mismatches
+		*out = make([]IndexMismatch, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsistencyCheckStatus.
+func (in *ConsistencyCheckStatus) DeepCopy() *ConsistencyCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsistencyCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Curator) DeepCopyInto(out *Curator) {
 	*out = *in
@@ -57,6 +147,31 @@ func (in *DbaasAdapter) DeepCopy() *DbaasAdapter {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DisasterRecovery) DeepCopyInto(out *DisasterRecovery) {
 	*out = *in
+	if in.ConfigurationSync != nil {
+		in, out := &in.ConfigurationSync, &out.ConfigurationSync
+		*out = new(ConfigurationSync)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsistencyCheck != nil {
+		in, out := &in.ConsistencyCheck, &out.ConsistencyCheck
+		*out = new(ConsistencyCheck)
+		**out = **in
+	}
+	if in.Fencing != nil {
+		in, out := &in.Fencing, &out.Fencing
+		*out = new(Fencing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotShipping != nil {
+		in, out := &in.SnapshotShipping, &out.SnapshotShipping
+		*out = new(SnapshotShipping)
+		**out = **in
+	}
+	if in.ReplicationPause != nil {
+		in, out := &in.ReplicationPause, &out.ReplicationPause
+		*out = new(ReplicationPause)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecovery.
@@ -72,6 +187,56 @@ func (in *DisasterRecovery) DeepCopy() *DisasterRecovery {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DisasterRecoveryStatus) DeepCopyInto(out *DisasterRecoveryStatus) {
 	*out = *in
+	if in.UsersRecovery != nil {
+		in, out := &in.UsersRecovery, &out.UsersRecovery
+		*out = new(UsersRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicationActions != nil {
+		in, out := &in.ReplicationActions, &out.ReplicationActions
+		*out = make([]ReplicationAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigurationSync != nil {
+		in, out := &in.ConfigurationSync, &out.ConfigurationSync
+		*out = new(ConfigurationSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SwitchoverReport != nil {
+		in, out := &in.SwitchoverReport, &out.SwitchoverReport
+		*out = new(SwitchoverReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsistencyCheck != nil {
+		in, out := &in.ConsistencyCheck, &out.ConsistencyCheck
+		*out = new(ConsistencyCheckStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(FailoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fencing != nil {
+		in, out := &in.Fencing, &out.Fencing
+		*out = new(FencingStatus)
+		**out = **in
+	}
+	if in.SnapshotShipping != nil {
+		in, out := &in.SnapshotShipping, &out.SnapshotShipping
+		*out = new(SnapshotShippingStatus)
+		**out = **in
+	}
+	if in.ReplicationPause != nil {
+		in, out := &in.ReplicationPause, &out.ReplicationPause
+		*out = new(ReplicationPauseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]SwitchoverHistoryEntry, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryStatus.
@@ -121,6 +286,106 @@ func (in *ExternalOpenSearch) DeepCopy() *ExternalOpenSearch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverStatus) DeepCopyInto(out *FailoverStatus) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]IndexCheckpoint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverStatus.
+func (in *FailoverStatus) DeepCopy() *FailoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Fencing) DeepCopyInto(out *Fencing) {
+	*out = *in
+	if in.Witness != nil {
+		in, out := &in.Witness, &out.Witness
+		*out = new(FencingWitness)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Fencing.
+func (in *Fencing) DeepCopy() *Fencing {
+	if in == nil {
+		return nil
+	}
+	out := new(Fencing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FencingStatus) DeepCopyInto(out *FencingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FencingStatus.
+func (in *FencingStatus) DeepCopy() *FencingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FencingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FencingWitness) DeepCopyInto(out *FencingWitness) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FencingWitness.
+func (in *FencingWitness) DeepCopy() *FencingWitness {
+	if in == nil {
+		return nil
+	}
+	out := new(FencingWitness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexCheckpoint) DeepCopyInto(out *IndexCheckpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexCheckpoint.
+func (in *IndexCheckpoint) DeepCopy() *IndexCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexMismatch) DeepCopyInto(out *IndexMismatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexMismatch.
+func (in *IndexMismatch) DeepCopy() *IndexMismatch {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexMismatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Monitoring) DeepCopyInto(out *Monitoring) {
 	*out = *in
@@ -266,7 +531,7 @@ func (in *OpenSearchServiceSpec) DeepCopyInto(out *OpenSearchServiceSpec) {
 	if in.DisasterRecovery != nil {
 		in, out := &in.DisasterRecovery, &out.DisasterRecovery
 		*out = new(DisasterRecovery)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -283,7 +548,7 @@ func (in *OpenSearchServiceSpec) DeepCopy() *OpenSearchServiceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenSearchServiceStatus) DeepCopyInto(out *OpenSearchServiceStatus) {
 	*out = *in
-	out.DisasterRecoveryStatus = in.DisasterRecoveryStatus
+	in.DisasterRecoveryStatus.DeepCopyInto(&out.DisasterRecoveryStatus)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]StatusCondition, len(*in))
@@ -302,6 +567,56 @@ func (in *OpenSearchServiceStatus) DeepCopy() *OpenSearchServiceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationAction) DeepCopyInto(out *ReplicationAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationAction.
+func (in *ReplicationAction) DeepCopy() *ReplicationAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationPause) DeepCopyInto(out *ReplicationPause) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationPause.
+func (in *ReplicationPause) DeepCopy() *ReplicationPause {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationPause)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationPauseStatus) DeepCopyInto(out *ReplicationPauseStatus) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationPauseStatus.
+func (in *ReplicationPauseStatus) DeepCopy() *ReplicationPauseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationPauseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdateStatus) DeepCopyInto(out *RollingUpdateStatus) {
 	*out = *in
@@ -354,6 +669,36 @@ func (in *SlowQueries) DeepCopy() *SlowQueries {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotShipping) DeepCopyInto(out *SnapshotShipping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotShipping.
+func (in *SnapshotShipping) DeepCopy() *SnapshotShipping {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotShipping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotShippingStatus) DeepCopyInto(out *SnapshotShippingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotShippingStatus.
+func (in *SnapshotShippingStatus) DeepCopy() *SnapshotShippingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotShippingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Snapshots) DeepCopyInto(out *Snapshots) {
 	*out = *in
@@ -408,3 +753,93 @@ func (in *StatusCondition) DeepCopy() *StatusCondition {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverCheck) DeepCopyInto(out *SwitchoverCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverCheck.
+func (in *SwitchoverCheck) DeepCopy() *SwitchoverCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverHistoryEntry) DeepCopyInto(out *SwitchoverHistoryEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverHistoryEntry.
+func (in *SwitchoverHistoryEntry) DeepCopy() *SwitchoverHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverReport) DeepCopyInto(out *SwitchoverReport) {
+	*out = *in
+	if in.EstimatedCatchUpSeconds != nil {
+		in, out := &in.EstimatedCatchUpSeconds, &out.EstimatedCatchUpSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]SwitchoverCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverReport.
+func (in *SwitchoverReport) DeepCopy() *SwitchoverReport {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsersRecoveryFailure) DeepCopyInto(out *UsersRecoveryFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsersRecoveryFailure.
+func (in *UsersRecoveryFailure) DeepCopy() *UsersRecoveryFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(UsersRecoveryFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsersRecoveryStatus) DeepCopyInto(out *UsersRecoveryStatus) {
+	*out = *in
+	if in.FailedUsers != nil {
+		in, out := &in.FailedUsers, &out.FailedUsers
+		*out = make([]UsersRecoveryFailure, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsersRecoveryStatus.
+func (in *UsersRecoveryStatus) DeepCopy() *UsersRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UsersRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -566,6 +566,39 @@ DRD Port
   {{- end -}}
 {{- end -}}
 
+{{/*
+Whether TLS or authentication is enabled for the operator Disaster Recovery server
+*/}}
+{{- define "disasterRecovery.serverSecurityEnabled" -}}
+  {{- if and (eq (include "opensearch.enableDisasterRecovery" .) "true") (or .Values.global.disasterRecovery.server.tls.enabled .Values.global.disasterRecovery.server.auth.type) -}}
+    {{- "true" -}}
+  {{- else -}}
+    {{- "false" -}}
+  {{- end -}}
+{{- end -}}
+
+{{/*
+TLS secret name for the operator Disaster Recovery server, the certificates of Disaster Recovery daemon are used by default
+*/}}
+{{- define "disasterRecovery.serverCertSecretName" -}}
+  {{- if .Values.global.disasterRecovery.server.tls.secretName -}}
+    {{- .Values.global.disasterRecovery.server.tls.secretName -}}
+  {{- else -}}
+    {{- required "The TLS secret name should be specified in the 'disasterRecovery.server.tls.secretName' parameter when TLS is enabled for the operator Disaster Recovery server without TLS for Disaster Recovery daemon." (include "disasterRecovery.certSecretName" .) -}}
+  {{- end -}}
+{{- end -}}
+
+{{/*
+Secret name with the token to authenticate fencing requests between Disaster Recovery sides
+*/}}
+{{- define "disasterRecovery.fencingSecretName" -}}
+  {{- if .Values.global.disasterRecovery.fencing.secretName -}}
+    {{- .Values.global.disasterRecovery.fencing.secretName -}}
+  {{- else -}}
+    {{- template "opensearch.fullname" . -}}-fencing-secret
+  {{- end -}}
+{{- end -}}
+
 {{- define "pod-scheduler-enabled" -}}
 {{- if and .Values.podScheduler.enabled (or (eq (include "master-nodes-volumes-enabled" .) "true") (eq (include "data-nodes-volumes-enabled" .) "true")) }}
   {{- "true" -}}
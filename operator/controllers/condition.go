@@ -58,3 +58,12 @@ func addCondition(currentConditions []opensearchservice.StatusCondition, conditi
 	}
 	return append(currentConditions, condition)
 }
+
+func hasCondition(conditions []opensearchservice.StatusCondition, reason string) bool {
+	for _, condition := range conditions {
+		if condition.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,350 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+)
+
+const (
+	defaultConfigurationSyncInterval = 300 * time.Second
+	allConfigurationObjectsName      = "*"
+)
+
+// configurationObject is a normalized object of OpenSearch configuration
+type configurationObject struct {
+	content map[string]interface{}
+	// reserved objects are managed by OpenSearch itself and are never synchronized
+	reserved bool
+	// version contains optimistic concurrency parameters for update requests
+	version string
+}
+
+// configurationKind describes how objects of one kind are read from and written to OpenSearch
+type configurationKind struct {
+	name       string
+	listPath   string
+	objectPath string
+	// creatable is false for objects which cannot be fully restored from the read API
+	creatable bool
+	parse     func([]byte) (map[string]configurationObject, error)
+	wrap      func(map[string]interface{}) interface{}
+}
+
+var configurationKinds = []configurationKind{
+	newSecurityKind("roles", "roles", true),
+	newSecurityKind("rolesMapping", "rolesmapping", true),
+	// Password hashes are not returned by REST API, so only existing users can be updated
+	newSecurityKind("internalUsers", "internalusers", false),
+	newTemplateKind("componentTemplates", "_component_template", "component_templates", "component_template"),
+	newTemplateKind("indexTemplates", "_index_template", "index_templates", "index_template"),
+	{
+		name:       "ismPolicies",
+		listPath:   "_plugins/_ism/policies?size=1000",
+		objectPath: "_plugins/_ism/policies/%s",
+		creatable:  true,
+		parse:      parseIsmPolicies,
+		wrap: func(content map[string]interface{}) interface{} {
+			return map[string]interface{}{"policy": content}
+		},
+	},
+}
+
+type ConfigurationSyncHelper struct {
+	logger       logr.Logger
+	localClient  *util.RestClient
+	remoteClient *util.RestClient
+	// newRemoteClient rebuilds the client of the active side before each synchronization
+	// to apply the current credentials from the secret
+	newRemoteClient func() *util.RestClient
+	statusUpdater   util.StatusUpdater
+	objects         []string
+}
+
+type ConfigurationSyncWatcher struct {
+	lock   *sync.Mutex
+	cancel *context.CancelFunc
+}
+
+func NewConfigurationSyncWatcher(mutex *sync.Mutex) ConfigurationSyncWatcher {
+	var cancel context.CancelFunc
+	return ConfigurationSyncWatcher{
+		lock:   mutex,
+		cancel: &cancel,
+	}
+}
+
+func (csw ConfigurationSyncWatcher) isRunning() bool {
+	return *csw.cancel != nil
+}
+
+func (csw ConfigurationSyncWatcher) start(helper ConfigurationSyncHelper, interval time.Duration) {
+	csw.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	*csw.cancel = cancel
+	go csw.watch(ctx, helper, interval)
+}
+
+func (csw ConfigurationSyncWatcher) stop() {
+	if *csw.cancel != nil {
+		(*csw.cancel)()
+		*csw.cancel = nil
+	}
+}
+
+// stopAndWait stops the watcher and waits until the running synchronization is finished
+func (csw ConfigurationSyncWatcher) stopAndWait() {
+	csw.stop()
+	csw.lock.Lock()
+	defer csw.lock.Unlock()
+}
+
+func (csw ConfigurationSyncWatcher) watch(ctx context.Context, helper ConfigurationSyncHelper, interval time.Duration) {
+	csw.lock.Lock()
+	defer csw.lock.Unlock()
+	for ctx.Err() == nil {
+		if helper.newRemoteClient != nil {
+			helper.remoteClient = helper.newRemoteClient()
+		}
+		status := helper.synchronize()
+		if ctx.Err() == nil {
+			helper.updateStatus(status)
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+	}
+	helper.logger.Info("Configuration Sync Watcher is stopped, exit from watch loop")
+}
+
+// synchronize copies configuration objects from the active side and returns the result of synchronization
+func (helper ConfigurationSyncHelper) synchronize() opensearchservice.ConfigurationSyncStatus {
+	status := opensearchservice.ConfigurationSyncStatus{}
+	for _, kind := range configurationKinds {
+		if !helper.isSelected(kind.name) {
+			continue
+		}
+		synced, conflicts := helper.synchronizeKind(kind)
+		status.Synced += synced
+		status.Conflicts = append(status.Conflicts, conflicts...)
+	}
+	status.LastSyncTime = time.Now().UTC().Format(time.RFC3339)
+	helper.logger.Info(fmt.Sprintf("Configuration synchronization is finished: %d objects are synchronized, %d conflicts",
+		status.Synced, len(status.Conflicts)))
+	return status
+}
+
+func (helper ConfigurationSyncHelper) isSelected(kind string) bool {
+	if len(helper.objects) == 0 {
+		return true
+	}
+	for _, object := range helper.objects {
+		if object == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (helper ConfigurationSyncHelper) synchronizeKind(kind configurationKind) (int, []opensearchservice.ConfigurationConflict) {
+	remoteObjects, err := helper.listObjects(helper.remoteClient, kind)
+	if err != nil {
+		helper.logger.Error(err, "unable to get objects from active side", "kind", kind.name)
+		return 0, []opensearchservice.ConfigurationConflict{newConfigurationConflict(kind.name, allConfigurationObjectsName,
+			fmt.Sprintf("unable to get objects from active side: %v", err))}
+	}
+	localObjects, err := helper.listObjects(helper.localClient, kind)
+	if err != nil {
+		helper.logger.Error(err, "unable to get objects from standby side", "kind", kind.name)
+		return 0, []opensearchservice.ConfigurationConflict{newConfigurationConflict(kind.name, allConfigurationObjectsName,
+			fmt.Sprintf("unable to get objects from standby side: %v", err))}
+	}
+
+	synced := 0
+	var conflicts []opensearchservice.ConfigurationConflict
+	for _, name := range sortedObjectNames(remoteObjects) {
+		remoteObject := remoteObjects[name]
+		if remoteObject.reserved {
+			continue
+		}
+		localObject, found := localObjects[name]
+		if found && localObject.reserved {
+			conflicts = append(conflicts, newConfigurationConflict(kind.name, name, "object is reserved on standby side"))
+			continue
+		}
+		if found && reflect.DeepEqual(localObject.content, remoteObject.content) {
+			continue
+		}
+		if !found && !kind.creatable {
+			conflicts = append(conflicts, newConfigurationConflict(kind.name, name,
+				"object is absent on standby side and cannot be created without sensitive data"))
+			continue
+		}
+		path := fmt.Sprintf(kind.objectPath, url.PathEscape(name))
+		if found {
+			path += localObject.version
+		}
+		if err = helper.putObject(path, kind.wrap(remoteObject.content)); err != nil {
+			helper.logger.Error(err, "unable to synchronize object", "kind", kind.name, "name", name)
+			conflicts = append(conflicts, newConfigurationConflict(kind.name, name, err.Error()))
+			continue
+		}
+		helper.logger.V(1).Info(fmt.Sprintf("Object '%s' of kind '%s' is synchronized", name, kind.name))
+		synced++
+	}
+	for _, name := range sortedObjectNames(localObjects) {
+		if _, found := remoteObjects[name]; !found && !localObjects[name].reserved {
+			conflicts = append(conflicts, newConfigurationConflict(kind.name, name, "object is absent on active side"))
+		}
+	}
+	return synced, conflicts
+}
+
+func (helper ConfigurationSyncHelper) listObjects(restClient *util.RestClient, kind configurationKind) (map[string]configurationObject, error) {
+	responseBody, err := restClient.SendRequestWithStatusCodeCheck(http.MethodGet, kind.listPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	return kind.parse(responseBody)
+}
+
+func (helper ConfigurationSyncHelper) putObject(path string, object interface{}) error {
+	body, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	_, err = helper.localClient.SendRequestWithStatusCodeCheck(http.MethodPut, path, bytes.NewReader(body))
+	return err
+}
+
+func (helper ConfigurationSyncHelper) updateStatus(status opensearchservice.ConfigurationSyncStatus) {
+	err := helper.statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		instance.Status.DisasterRecoveryStatus.ConfigurationSync = &status
+	})
+	if err != nil {
+		helper.logger.Error(err, "Unable to update configuration synchronization status")
+	}
+}
+
+func newConfigurationConflict(kind string, name string, reason string) opensearchservice.ConfigurationConflict {
+	return opensearchservice.ConfigurationConflict{Kind: kind, Name: name, Reason: reason}
+}
+
+func sortedObjectNames(objects map[string]configurationObject) []string {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newSecurityKind describes objects of OpenSearch Security REST API
+func newSecurityKind(name string, endpoint string, creatable bool) configurationKind {
+	return configurationKind{
+		name:       name,
+		listPath:   fmt.Sprintf("_plugins/_security/api/%s", endpoint),
+		objectPath: fmt.Sprintf("_plugins/_security/api/%s/%%s", endpoint),
+		creatable:  creatable,
+		parse:      parseSecurityObjects,
+		wrap: func(content map[string]interface{}) interface{} {
+			return content
+		},
+	}
+}
+
+func parseSecurityObjects(body []byte) (map[string]configurationObject, error) {
+	var objects map[string]map[string]interface{}
+	if err := json.Unmarshal(body, &objects); err != nil {
+		return nil, err
+	}
+	result := make(map[string]configurationObject, len(objects))
+	for name, content := range objects {
+		reserved := content["reserved"] == true || content["hidden"] == true || content["static"] == true
+		for _, field := range []string{"reserved", "hidden", "static", "hash"} {
+			delete(content, field)
+		}
+		result[name] = configurationObject{content: content, reserved: reserved}
+	}
+	return result, nil
+}
+
+// newTemplateKind describes composable index templates and component templates.
+// Templates with names started with dot belong to OpenSearch plugins and are not synchronized.
+func newTemplateKind(name string, endpoint string, listKey string, objectKey string) configurationKind {
+	return configurationKind{
+		name:       name,
+		listPath:   endpoint,
+		objectPath: endpoint + "/%s",
+		creatable:  true,
+		parse: func(body []byte) (map[string]configurationObject, error) {
+			var templates map[string][]map[string]interface{}
+			if err := json.Unmarshal(body, &templates); err != nil {
+				return nil, err
+			}
+			result := make(map[string]configurationObject, len(templates[listKey]))
+			for _, template := range templates[listKey] {
+				templateName, _ := template["name"].(string)
+				content, _ := template[objectKey].(map[string]interface{})
+				result[templateName] = configurationObject{content: content, reserved: strings.HasPrefix(templateName, ".")}
+			}
+			return result, nil
+		},
+		wrap: func(content map[string]interface{}) interface{} {
+			return content
+		},
+	}
+}
+
+func parseIsmPolicies(body []byte) (map[string]configurationObject, error) {
+	var response struct {
+		Policies []struct {
+			Id          string                 `json:"_id"`
+			SeqNo       int64                  `json:"_seq_no"`
+			PrimaryTerm int64                  `json:"_primary_term"`
+			Policy      map[string]interface{} `json:"policy"`
+		} `json:"policies"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	result := make(map[string]configurationObject, len(response.Policies))
+	for _, policy := range response.Policies {
+		// These fields are maintained by ISM plugin and differ between clusters
+		for _, field := range []string{"policy_id", "last_updated_time", "schema_version"} {
+			delete(policy.Policy, field)
+		}
+		result[policy.Id] = configurationObject{
+			content: policy.Policy,
+			version: fmt.Sprintf("?if_seq_no=%d&if_primary_term=%d", policy.SeqNo, policy.PrimaryTerm),
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newConfigurationStub serves GET requests from responses and records PUT requests
+func newConfigurationStub(responses map[string]string, puts map[string]string, lock *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			lock.Lock()
+			puts[r.URL.RequestURI()] = string(body)
+			lock.Unlock()
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		response, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			response = `{}`
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+}
+
+func TestSynchronize_CopiesChangedObjectsAndReportsConflicts(t *testing.T) {
+	remote := newConfigurationStub(map[string]string{
+		"/_plugins/_security/api/roles": `{
+			"all_access":{"reserved":true,"cluster_permissions":["*"]},
+			"app_role":{"reserved":false,"hidden":false,"static":false,"cluster_permissions":["cluster_monitor"]},
+			"same_role":{"reserved":false,"cluster_permissions":["indices_monitor"]}}`,
+		"/_plugins/_security/api/internalusers": `{
+			"existing":{"hash":"","reserved":false,"backend_roles":["new"]},
+			"missing":{"hash":"","reserved":false,"backend_roles":[]}}`,
+		"/_index_template": `{"index_templates":[{"name":"app","index_template":{"index_patterns":["app-*"]}},
+			{"name":".plugin","index_template":{"index_patterns":[".plugin*"]}}]}`,
+		"/_plugins/_ism/policies?size=1000": `{"policies":[{"_id":"rollover","_seq_no":1,"_primary_term":1,
+			"policy":{"policy_id":"rollover","last_updated_time":1,"description":"new"}}]}`,
+	}, map[string]string{}, &sync.Mutex{})
+	defer remote.Close()
+
+	var lock sync.Mutex
+	puts := map[string]string{}
+	local := newConfigurationStub(map[string]string{
+		"/_plugins/_security/api/roles": `{
+			"same_role":{"reserved":false,"cluster_permissions":["indices_monitor"]},
+			"local_role":{"reserved":false,"cluster_permissions":[]}}`,
+		"/_plugins/_security/api/internalusers": `{"existing":{"hash":"","reserved":false,"backend_roles":["old"]}}`,
+		"/_plugins/_ism/policies?size=1000": `{"policies":[{"_id":"rollover","_seq_no":7,"_primary_term":2,
+			"policy":{"policy_id":"rollover","last_updated_time":2,"description":"old"}}]}`,
+	}, puts, &lock)
+	defer local.Close()
+
+	helper := ConfigurationSyncHelper{
+		logger:       logr.Discard(),
+		localClient:  util.NewRestClient(local.URL, http.Client{}, util.Credentials{}),
+		remoteClient: util.NewRestClient(remote.URL, http.Client{}, util.Credentials{}),
+	}
+	status := helper.synchronize()
+
+	expectedPuts := []string{
+		"/_plugins/_security/api/roles/app_role",
+		"/_plugins/_security/api/internalusers/existing",
+		"/_index_template/app",
+		"/_plugins/_ism/policies/rollover?if_seq_no=7&if_primary_term=2",
+	}
+	if len(puts) != len(expectedPuts) {
+		t.Errorf("expected %d updated objects, got %v", len(expectedPuts), puts)
+	}
+	for _, path := range expectedPuts {
+		if _, ok := puts[path]; !ok {
+			t.Errorf("expected PUT request to %s, got %v", path, puts)
+		}
+	}
+	if status.Synced != len(expectedPuts) {
+		t.Errorf("expected %d synchronized objects, got %d", len(expectedPuts), status.Synced)
+	}
+
+	var role map[string]interface{}
+	_ = json.Unmarshal([]byte(puts["/_plugins/_security/api/roles/app_role"]), &role)
+	if _, ok := role["reserved"]; ok {
+		t.Errorf("expected read-only fields to be removed, got %v", role)
+	}
+	var policy map[string]map[string]interface{}
+	_ = json.Unmarshal([]byte(puts["/_plugins/_ism/policies/rollover?if_seq_no=7&if_primary_term=2"]), &policy)
+	if policy["policy"]["description"] != "new" {
+		t.Errorf("unexpected ISM policy body: %v", policy)
+	}
+
+	conflicts := map[string]string{}
+	for _, conflict := range status.Conflicts {
+		conflicts[conflict.Kind+"/"+conflict.Name] = conflict.Reason
+	}
+	if len(conflicts) != 2 || conflicts["roles/local_role"] == "" || conflicts["internalUsers/missing"] == "" {
+		t.Errorf("unexpected conflicts: %v", status.Conflicts)
+	}
+	if status.LastSyncTime == "" {
+		t.Error("expected last synchronization time to be set")
+	}
+}
+
+func TestSynchronize_OnlySelectedObjects(t *testing.T) {
+	var lock sync.Mutex
+	puts := map[string]string{}
+	remote := newConfigurationStub(map[string]string{
+		"/_plugins/_security/api/roles": `{"app_role":{"cluster_permissions":["cluster_monitor"]}}`,
+		"/_index_template":              `{"index_templates":[{"name":"app","index_template":{"index_patterns":["app-*"]}}]}`,
+	}, map[string]string{}, &sync.Mutex{})
+	defer remote.Close()
+	local := newConfigurationStub(map[string]string{}, puts, &lock)
+	defer local.Close()
+
+	helper := ConfigurationSyncHelper{
+		logger:       logr.Discard(),
+		localClient:  util.NewRestClient(local.URL, http.Client{}, util.Credentials{}),
+		remoteClient: util.NewRestClient(remote.URL, http.Client{}, util.Credentials{}),
+		objects:      []string{"indexTemplates"},
+	}
+	status := helper.synchronize()
+
+	if len(puts) != 1 || puts["/_index_template/app"] == "" {
+		t.Errorf("expected only index template to be synchronized, got %v", puts)
+	}
+	if status.Synced != 1 || len(status.Conflicts) != 0 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestConfigurationSyncWatcher_RebuildsRemoteClientForEachSynchronization(t *testing.T) {
+	remote := newConfigurationStub(map[string]string{}, map[string]string{}, &sync.Mutex{})
+	defer remote.Close()
+	local := newConfigurationStub(map[string]string{}, map[string]string{}, &sync.Mutex{})
+	defer local.Close()
+
+	var lock sync.Mutex
+	built := 0
+	helper := ConfigurationSyncHelper{
+		logger:      logr.Discard(),
+		localClient: util.NewRestClient(local.URL, http.Client{}, util.Credentials{}),
+		newRemoteClient: func() *util.RestClient {
+			lock.Lock()
+			defer lock.Unlock()
+			built++
+			return util.NewRestClient(remote.URL, http.Client{}, util.Credentials{})
+		},
+		statusUpdater: util.NewStatusUpdater(fake.NewClientBuilder().Build(), &opensearchservice.OpenSearchService{}),
+		objects:       []string{"indexTemplates"},
+	}
+	watcher := NewConfigurationSyncWatcher(&sync.Mutex{})
+	watcher.start(helper, 10*time.Millisecond)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lock.Lock()
+		count := built
+		lock.Unlock()
+		if count >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	watcher.stopAndWait()
+
+	lock.Lock()
+	count := built
+	lock.Unlock()
+	if count < 2 {
+		t.Errorf("expected remote client to be rebuilt for each synchronization, got %d", count)
+	}
+	if watcher.isRunning() {
+		t.Error("expected watcher to be stopped")
+	}
+	time.Sleep(50 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	if built != count {
+		t.Errorf("expected no synchronization after stop, got %d more", built-count)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+)
+
+const (
+	defaultConsistencyCheckInterval = 3600 * time.Second
+	maxReportedMismatches           = 50
+	// allReplicatedIndicesName is reported as the mismatched index if replicated indices cannot be listed
+	allReplicatedIndicesName = "*"
+)
+
+type ConsistencyHelper struct {
+	logger             logr.Logger
+	replicationManager ReplicationManager
+	statusUpdater      util.StatusUpdater
+	mode               string
+	sampleSize         int
+}
+
+// ConsistencyWatcher verifies that replicated indices contain the same documents as the leader ones.
+// The verification is run periodically or once on demand. The lock guards the state of the watcher
+// and the results of verifications, so results of concurrent verifications are recorded one by one.
+type ConsistencyWatcher struct {
+	lock    *sync.Mutex
+	cancel  *context.CancelFunc
+	request *string
+}
+
+type sampledDocuments struct {
+	Hits struct {
+		Hits []struct {
+			Id    string `json:"_id"`
+			SeqNo int64  `json:"_seq_no"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func NewConsistencyWatcher(mutex *sync.Mutex) ConsistencyWatcher {
+	var cancel context.CancelFunc
+	var request string
+	return ConsistencyWatcher{
+		lock:    mutex,
+		cancel:  &cancel,
+		request: &request,
+	}
+}
+
+func (cw ConsistencyWatcher) isRunning() bool {
+	cw.lock.Lock()
+	defer cw.lock.Unlock()
+	return *cw.cancel != nil
+}
+
+func (cw ConsistencyWatcher) start(helper ConsistencyHelper, interval time.Duration) {
+	cw.lock.Lock()
+	defer cw.lock.Unlock()
+	cw.cancelWatch()
+	ctx, cancel := context.WithCancel(context.Background())
+	*cw.cancel = cancel
+	go cw.watch(ctx, helper, interval)
+}
+
+func (cw ConsistencyWatcher) stop() {
+	cw.lock.Lock()
+	defer cw.lock.Unlock()
+	cw.cancelWatch()
+}
+
+// cancelWatch stops the periodic verification, the lock must be held by the caller
+func (cw ConsistencyWatcher) cancelWatch() {
+	if *cw.cancel != nil {
+		(*cw.cancel)()
+		*cw.cancel = nil
+	}
+}
+
+// isRequested checks whether the verification for the request is already started
+func (cw ConsistencyWatcher) isRequested(request string) bool {
+	cw.lock.Lock()
+	defer cw.lock.Unlock()
+	return *cw.request == request
+}
+
+// runOnce starts the verification for the request in background
+func (cw ConsistencyWatcher) runOnce(helper ConsistencyHelper, request string) {
+	cw.lock.Lock()
+	*cw.request = request
+	cw.lock.Unlock()
+	go func() {
+		status := helper.verify()
+		cw.lock.Lock()
+		defer cw.lock.Unlock()
+		helper.updateStatus(status, request)
+	}()
+}
+
+func (cw ConsistencyWatcher) watch(ctx context.Context, helper ConsistencyHelper, interval time.Duration) {
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+			status := helper.verify()
+			cw.lock.Lock()
+			if ctx.Err() == nil {
+				helper.updateStatus(status, "")
+			}
+			cw.lock.Unlock()
+		}
+	}
+	helper.logger.Info("Consistency Watcher is stopped, exit from watch loop")
+}
+
+// verify compares documents count and, if sample size is specified, sampled documents
+// of each replicated index with the leader index
+func (helper ConsistencyHelper) verify() opensearchservice.ConsistencyCheckStatus {
+	status := opensearchservice.ConsistencyCheckStatus{Mode: helper.mode}
+	indexNames, err := helper.replicationManager.GetRulesIndices()
+	if err != nil {
+		helper.logger.Error(err, "Unable to get replicated indices for consistency check")
+		status.Mismatches = append(status.Mismatches, opensearchservice.IndexMismatch{
+			Index:  allReplicatedIndicesName,
+			Reason: fmt.Sprintf("unable to get replicated indices: %v", err),
+		})
+	}
+	sort.Strings(indexNames)
+	for _, index := range indexNames {
+		if mismatch := helper.verifyIndex(index); mismatch != nil {
+			status.Mismatches = append(status.Mismatches, *mismatch)
+		}
+	}
+	status.CheckedIndices = len(indexNames)
+	status.Consistent = len(status.Mismatches) == 0
+	status.Time = time.Now().UTC().Format(time.RFC3339)
+	helper.logger.Info(fmt.Sprintf("Consistency check is finished: %d indices are checked, %d mismatches",
+		status.CheckedIndices, len(status.Mismatches)))
+	return status
+}
+
+func (helper ConsistencyHelper) verifyIndex(index string) *opensearchservice.IndexMismatch {
+	mismatch := &opensearchservice.IndexMismatch{Index: index}
+	localCount, err := helper.replicationManager.getDocumentsCount(index)
+	if err != nil {
+		mismatch.Reason = fmt.Sprintf("unable to count local documents: %v", err)
+		return mismatch
+	}
+	mismatch.LocalCount = localCount
+	leaderCount, err := helper.replicationManager.getDocumentsCount(leaderIndex(index))
+	if err != nil {
+		mismatch.Reason = fmt.Sprintf("unable to count leader documents: %v", err)
+		return mismatch
+	}
+	mismatch.LeaderCount = leaderCount
+	if localCount != leaderCount {
+		mismatch.Reason = "documents count differs"
+		return mismatch
+	}
+	if helper.sampleSize <= 0 || localCount == 0 {
+		return nil
+	}
+	if err = helper.compareSampledDocuments(index); err != nil {
+		mismatch.Reason = err.Error()
+		return mismatch
+	}
+	return nil
+}
+
+// compareSampledDocuments selects random documents of the local index and compares
+// the checksum of their IDs and sequence numbers with the same documents of the leader index
+func (helper ConsistencyHelper) compareSampledDocuments(index string) error {
+	sampleQuery := fmt.Sprintf(`{"size":%d,"_source":false,"seq_no_primary_term":true,
+"query":{"function_score":{"random_score":{"seed":%d,"field":"_seq_no"}}}}`, helper.sampleSize, time.Now().UnixNano())
+	localDocuments, err := helper.searchDocuments(index, sampleQuery)
+	if err != nil {
+		return fmt.Errorf("unable to sample local documents: %w", err)
+	}
+	ids := make([]string, 0, len(localDocuments))
+	for id := range localDocuments {
+		ids = append(ids, id)
+	}
+	idsBody, _ := json.Marshal(ids)
+	leaderQuery := fmt.Sprintf(`{"size":%d,"_source":false,"seq_no_primary_term":true,"query":{"ids":{"values":%s}}}`,
+		len(ids), idsBody)
+	leaderDocuments, err := helper.searchDocuments(leaderIndex(index), leaderQuery)
+	if err != nil {
+		return fmt.Errorf("unable to get sampled leader documents: %w", err)
+	}
+	if documentsChecksum(localDocuments) != documentsChecksum(leaderDocuments) {
+		return fmt.Errorf("sampled documents differ")
+	}
+	return nil
+}
+
+func (helper ConsistencyHelper) searchDocuments(index string, query string) (map[string]int64, error) {
+	body, err := helper.replicationManager.restClient.SendRequestWithStatusCodeCheck(http.MethodPost,
+		fmt.Sprintf("%s/_search", index), strings.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	var response sampledDocuments
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	documents := make(map[string]int64, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		documents[hit.Id] = hit.SeqNo
+	}
+	return documents, nil
+}
+
+func (helper ConsistencyHelper) updateStatus(status opensearchservice.ConsistencyCheckStatus, request string) {
+	recordConsistencyMetrics(status, time.Now())
+	status.Request = request
+	if len(status.Mismatches) > maxReportedMismatches {
+		status.Mismatches = status.Mismatches[:maxReportedMismatches]
+	}
+	err := helper.statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		instance.Status.DisasterRecoveryStatus.ConsistencyCheck = &status
+	})
+	if err != nil {
+		helper.logger.Error(err, "Unable to update consistency check status")
+	}
+}
+
+// documentsChecksum returns the checksum of sorted document IDs with their sequence numbers
+func documentsChecksum(documents map[string]int64) string {
+	entries := make([]string, 0, len(documents))
+	for id, seqNo := range documents {
+		entries = append(entries, fmt.Sprintf("%s:%d", id, seqNo))
+	}
+	sort.Strings(entries)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(entries, "\n"))))
+}
+
+func leaderIndex(index string) string {
+	return fmt.Sprintf("%s:%s", leaderAlias, index)
+}
@@ -0,0 +1,147 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newConsistencyHelper(server *httptest.Server, sampleSize int) ConsistencyHelper {
+	replicationManager := NewReplicationManager(*util.NewRestClient(server.URL, http.Client{}, util.Credentials{}), "",
+		[]ReplicationRule{{Patterns: []string{"*"}}}, logr.Discard())
+	return ConsistencyHelper{
+		logger:             logr.Discard(),
+		replicationManager: *replicationManager,
+		mode:               "standby",
+		sampleSize:         sampleSize,
+	}
+}
+
+func TestVerify_ReportsMismatchedIndices(t *testing.T) {
+	responses := map[string]string{
+		"/_cat/indices/*":                 `[{"index":"same"},{"index":"behind"},{"index":"changed"}]`,
+		"/same/_count":                    `{"count":2}`,
+		"/leader-cluster:same/_count":     `{"count":2}`,
+		"/behind/_count":                  `{"count":1}`,
+		"/leader-cluster:behind/_count":   `{"count":2}`,
+		"/changed/_count":                 `{"count":2}`,
+		"/leader-cluster:changed/_count":  `{"count":2}`,
+		"/same/_search":                   `{"hits":{"hits":[{"_id":"1","_seq_no":0},{"_id":"2","_seq_no":1}]}}`,
+		"/leader-cluster:same/_search":    `{"hits":{"hits":[{"_id":"2","_seq_no":1},{"_id":"1","_seq_no":0}]}}`,
+		"/changed/_search":                `{"hits":{"hits":[{"_id":"1","_seq_no":0},{"_id":"2","_seq_no":1}]}}`,
+		"/leader-cluster:changed/_search": `{"hits":{"hits":[{"_id":"1","_seq_no":0},{"_id":"2","_seq_no":5}]}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	status := newConsistencyHelper(server, 2).verify()
+
+	if status.Consistent || status.CheckedIndices != 3 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if len(status.Mismatches) != 2 {
+		t.Fatalf("expected two mismatches, got %+v", status.Mismatches)
+	}
+	behind := status.Mismatches[0]
+	if behind.Index != "behind" || behind.LocalCount != 1 || behind.LeaderCount != 2 {
+		t.Errorf("unexpected mismatch: %+v", behind)
+	}
+	if changed := status.Mismatches[1]; changed.Index != "changed" || changed.Reason != "sampled documents differ" {
+		t.Errorf("unexpected mismatch: %+v", changed)
+	}
+}
+
+func TestVerify_OnlyDocumentsCountWithoutSampleSize(t *testing.T) {
+	var lock sync.Mutex
+	searched := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_cat/indices/*":
+			_, _ = w.Write([]byte(`[{"index":"test"}]`))
+		case "/test/_count", "/leader-cluster:test/_count":
+			_, _ = w.Write([]byte(`{"count":5}`))
+		default:
+			lock.Lock()
+			searched = true
+			lock.Unlock()
+		}
+	}))
+	defer server.Close()
+
+	status := newConsistencyHelper(server, 0).verify()
+
+	if !status.Consistent || status.CheckedIndices != 1 || status.Mode != "standby" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if searched {
+		t.Error("expected no document sampling without sample size")
+	}
+}
+
+func TestNewConsistencyWatcher_InitiallyIdle(t *testing.T) {
+	var mu sync.Mutex
+	watcher := NewConsistencyWatcher(&mu)
+	if watcher.isRunning() || watcher.isRequested("1") {
+		t.Error("expected a freshly created watcher to be idle")
+	}
+}
+
+func TestConsistencyWatcher_StateIsAvailableDuringVerification(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_cat/indices/*" {
+			<-release
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	helper := newConsistencyHelper(server, 0)
+	helper.statusUpdater = util.NewStatusUpdater(fake.NewClientBuilder().Build(), &opensearchservice.OpenSearchService{})
+
+	var mu sync.Mutex
+	watcher := NewConsistencyWatcher(&mu)
+	watcher.runOnce(helper, "1")
+	done := make(chan struct{})
+	go func() {
+		watcher.start(helper, time.Hour)
+		if !watcher.isRequested("1") || !watcher.isRunning() {
+			t.Error("expected requested verification and running watcher")
+		}
+		watcher.stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("expected the state of the watcher to be available during verification")
+	}
+	close(release)
+	if watcher.isRunning() {
+		t.Error("expected watcher to be stopped")
+	}
+}
@@ -16,6 +16,7 @@ package controllers
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -30,6 +31,8 @@ import (
 
 const (
 	drConfigHashName            = "config.disasterRecovery"
+	drConfigurationSyncHashName = "spec.disasterRecovery.configurationSync"
+	drConsistencyCheckHashName  = "spec.disasterRecovery.consistencyCheck"
 	replicationRemoteServiceKey = "remoteCluster"
 	replicationPatternKey       = "indicesPattern"
 	interval                    = 10 * time.Second
@@ -39,6 +42,10 @@ const (
 	usersRecoveryIdleState      = "idle"
 	usersRecoveryRunningState   = "running"
 	opensearchGKEServiceEnvVar  = "OPENSEARCH_GKE_SERVICE"
+	replicationActionSucceeded  = "succeeded"
+	replicationActionFailed     = "failed"
+	maxReplicationActions       = 20
+	otherSideConnectionTimeout  = 5 * time.Second
 )
 
 type DisasterRecoveryReconciler struct {
@@ -47,6 +54,7 @@ type DisasterRecoveryReconciler struct {
 	reconciler               *OpenSearchServiceReconciler
 	replicationWatcher       ReplicationWatcher
 	opensearchGKEServiceName string
+	serverSecurity           disasterrecovery.ServerSecurity
 }
 
 type LeaderStats struct {
@@ -69,6 +77,7 @@ func NewDisasterRecoveryReconciler(r *OpenSearchServiceReconciler, cr *opensearc
 		reconciler:               r,
 		replicationWatcher:       r.ReplicationWatcher,
 		opensearchGKEServiceName: os.Getenv(opensearchGKEServiceEnvVar),
+		serverSecurity:           disasterrecovery.NewServerSecurity(),
 	}
 }
 
@@ -81,11 +90,23 @@ func (r DisasterRecoveryReconciler) Status() error {
 }
 
 func (r DisasterRecoveryReconciler) Configure() error {
+	if request, requested := r.isSwitchoverDryRunRequested(); requested {
+		if err := r.updateSwitchoverReport(r.runSwitchoverDryRun(request)); err != nil {
+			r.logger.Error(err, "Unable to update switchover dry-run report")
+		}
+	}
+
 	crCondition := r.cr.Spec.DisasterRecovery.Mode != r.cr.Status.DisasterRecoveryStatus.Mode ||
 		r.cr.Status.DisasterRecoveryStatus.Status == "running" ||
 		r.cr.Status.DisasterRecoveryStatus.Status == "failed" ||
 		r.cr.Status.DisasterRecoveryStatus.Status == "queue"
 
+	if r.isFencingEnabled() {
+		if err := r.reconcileFencing(crCondition); err != nil && r.cr.Spec.DisasterRecovery.Mode == "active" {
+			return r.fence(err)
+		}
+	}
+
 	drConfigHash, err :=
 		r.reconciler.calculateConfigDataHash(r.cr.Spec.DisasterRecovery.ConfigMapName, drConfigHashName, r.cr, r.logger)
 	if err != nil {
@@ -95,6 +116,7 @@ func (r DisasterRecoveryReconciler) Configure() error {
 
 	message := ""
 	usersRecoveryState := usersRecoveryDoneState
+	var historyEntry *opensearchservice.SwitchoverHistoryEntry
 
 	defer func() {
 		status := "done"
@@ -103,14 +125,36 @@ func (r DisasterRecoveryReconciler) Configure() error {
 			message = fmt.Sprintf("Error occurred during OpenSearch switching: %v", err)
 		}
 		_ = r.updateDisasterRecoveryStatus(status, message, usersRecoveryState)
+		if status == "done" && historyEntry != nil && r.isFailover() {
+			if resetErr := r.resetFailoverFlag(); resetErr != nil {
+				r.logger.Error(resetErr, "Unable to reset failover flag")
+			}
+		}
+		if historyEntry != nil {
+			entryUsersRecoveryState := ""
+			if r.cr.Spec.DbaasAdapter != nil {
+				entryUsersRecoveryState = usersRecoveryState
+			}
+			finishSwitchoverHistoryEntry(historyEntry, status, entryUsersRecoveryState, err)
+			r.recordSwitchover(*historyEntry)
+		}
 		if r.cr.Spec.DisasterRecovery.Mode == "active" {
 			_ = r.enableClientServices()
 		}
 		r.logger.Info("Disaster recovery status was updated.")
 	}()
 
+	// Configuration synchronization is reconciled once at the end, also when the switchover fails,
+	// to restart it after the switchover if the side remains in `standby` mode
+	defer r.reconcileConfigurationSync()
+
 	needReturnError := true
 	if crCondition || drConfigHashChanged {
+		historyEntry = newSwitchoverHistoryEntry(r.cr.Spec.DisasterRecovery.Mode)
+		// Configuration objects must not be written while the side changes its mode
+		r.reconciler.ConfigurationSyncWatcher.stopAndWait()
+		// Replication is recreated during the switchover, so the requested pause is applied again
+		delete(r.reconciler.ResourceHashes, drReplicationPauseHashName)
 		r.replicationWatcher.pause(r.logger)
 		r.replicationWatcher.Lock.Lock()
 		defer r.replicationWatcher.Lock.Unlock()
@@ -130,8 +174,14 @@ func (r DisasterRecoveryReconciler) Configure() error {
 		}
 		time.Sleep(time.Second * 2)
 
-		replicationManager := r.getReplicationManager()
-		if r.cr.Spec.DisasterRecovery.Mode == "standby" {
+		var replicationManager ReplicationManager
+		if replicationManager, err = r.getReplicationManager(); err != nil {
+			return err
+		}
+		snapshotShipping := r.isSnapshotShippingEnabled()
+		if r.cr.Spec.DisasterRecovery.Mode == "standby" && snapshotShipping {
+			message, err = r.switchoverSnapshotShipping(replicationManager, historyEntry)
+		} else if r.cr.Spec.DisasterRecovery.Mode == "standby" {
 			message = "The replication has started successfully"
 			if r.cr.Status.DisasterRecoveryStatus.Mode != "active" {
 				r.logger.Info("Removing previous replication rule")
@@ -147,28 +197,40 @@ func (r DisasterRecoveryReconciler) Configure() error {
 				err = r.runReplicationProcess(replicationManager)
 			}
 			if err == nil {
-				err = r.checkReplication(replicationManager.restClient)
+				err = r.checkReplication(replicationManager)
+				historyEntry.ReplicationCheck = replicationCheckResult(err)
 			}
 		}
 
 		if r.cr.Spec.DisasterRecovery.Mode == "active" || r.cr.Spec.DisasterRecovery.Mode == "disable" {
 			message = "The replication has stopped successfully"
-			err = r.replicationWatcher.checkReplication(r, true, r.logger)
-			if err == nil && checkNeeded {
-				var indexNames []string
-				indexNames, err = replicationManager.getReplicatedIndices()
+			if snapshotShipping {
+				message, err = r.switchoverSnapshotShipping(replicationManager, historyEntry)
+			} else if r.isFailover() {
+				message = "The failover has been performed, the other side requires resync"
+				err = r.failover(replicationManager)
+			} else {
+				err = r.replicationWatcher.checkReplication(r, true, r.logger)
 				if err != nil {
-					r.logger.Error(err, "Can not get replication indices. Replication check is failed.")
+					historyEntry.ReplicationCheck = replicationCheckFailed
 				}
-				r.logger.Info("Start replication check")
-				if err = replicationManager.executeReplicationCheck(indexNames); err != nil {
-					r.logger.Error(err, "Replication check is failed.")
+				if err == nil && checkNeeded {
+					var indexNames []string
+					indexNames, err = replicationManager.getReplicatedIndices()
+					if err != nil {
+						r.logger.Error(err, "Can not get replication indices. Replication check is failed.")
+					}
+					r.logger.Info("Start replication check")
+					if err = replicationManager.executeReplicationCheck(indexNames); err != nil {
+						r.logger.Error(err, "Replication check is failed.")
+					}
+					historyEntry.ReplicationCheck = replicationCheckResult(err)
+				} else {
+					message = "Switchover mode has been changed without replication check"
+				}
+				if err == nil {
+					err = r.stopReplication(replicationManager)
 				}
-			} else {
-				message = "Switchover mode has been changed without replication check"
-			}
-			if err == nil {
-				err = r.stopReplication(replicationManager)
 			}
 			if r.cr.Spec.DisasterRecovery.Mode == "active" {
 				if err == nil {
@@ -187,22 +249,140 @@ func (r DisasterRecoveryReconciler) Configure() error {
 
 	r.reconciler.ResourceHashes[drConfigHashName] = drConfigHash
 
-	if r.cr.Spec.DisasterRecovery.Mode == "standby" {
+	// Cross cluster replication is not used with snapshot shipping, so the replication plugin can be absent
+	if r.cr.Spec.DisasterRecovery.Mode == "standby" && !r.isSnapshotShippingEnabled() {
 		err = r.updateClusterSettings()
 	}
 
-	if r.cr.Spec.DisasterRecovery.ReplicationWatcherEnabled {
+	if r.cr.Spec.DisasterRecovery.ReplicationWatcherEnabled && !r.isSnapshotShippingEnabled() {
 		r.replicationWatcher.start(r, r.logger)
 	} else {
 		r.replicationWatcher.pause(r.logger)
 	}
 
+	if r.cr.Spec.DisasterRecovery.Mode == "active" {
+		r.checkResyncAfterFailover()
+	}
+
+	r.reconcileConsistencyCheck()
+	r.reconcileSnapshotShipping()
+	r.reconcileReplicationPause()
+
 	if needReturnError {
 		return err
 	}
 	return nil
 }
 
+// reconcileConfigurationSync runs synchronization of configuration objects from the active side
+// only when the current side is in `standby` mode
+func (r DisasterRecoveryReconciler) reconcileConfigurationSync() {
+	watcher := r.reconciler.ConfigurationSyncWatcher
+	configurationSync := r.cr.Spec.DisasterRecovery.ConfigurationSync
+	if r.cr.Spec.DisasterRecovery.Mode != "standby" || configurationSync == nil || !configurationSync.Enabled {
+		watcher.stop()
+		delete(r.reconciler.ResourceHashes, drConfigurationSyncHashName)
+		return
+	}
+	configurationSyncHash, err := util.Hash(configurationSync)
+	if err != nil {
+		r.logger.Error(err, "Unable to calculate hash of configuration synchronization parameters")
+		return
+	}
+	if r.reconciler.ResourceHashes[drConfigurationSyncHashName] == configurationSyncHash && watcher.isRunning() {
+		return
+	}
+	r.reconciler.ResourceHashes[drConfigurationSyncHashName] = configurationSyncHash
+	syncInterval := defaultConfigurationSyncInterval
+	if configurationSync.Interval > 0 {
+		syncInterval = time.Duration(configurationSync.Interval) * time.Second
+	}
+	r.logger.Info("Start configuration synchronization from the active side")
+	watcher.start(r.prepareConfigurationSyncHelper(configurationSync), syncInterval)
+}
+
+func (r DisasterRecoveryReconciler) prepareConfigurationSyncHelper(configurationSync *opensearchservice.ConfigurationSync) ConfigurationSyncHelper {
+	return ConfigurationSyncHelper{
+		logger:      r.logger,
+		localClient: r.getRestClient(),
+		newRemoteClient: func() *util.RestClient {
+			return r.buildRemoteRestClient(configurationSync)
+		},
+		statusUpdater: util.NewStatusUpdater(r.reconciler.Client, r.cr),
+		objects:       configurationSync.Objects,
+	}
+}
+
+// buildRemoteRestClient returns the client of OpenSearch on the other side with the local trust configuration
+// and credentials from the configuration synchronization secret or local credentials if it is not specified
+func (r DisasterRecoveryReconciler) buildRemoteRestClient(configurationSync *opensearchservice.ConfigurationSync) *util.RestClient {
+	client, _ := r.reconciler.configureClient()
+	credentials := r.reconciler.parseOpenSearchCredentials(r.cr, r.logger)
+	if configurationSync.SecretName != "" {
+		credentials = r.reconciler.parseSecretCredentials(configurationSync.SecretName, r.cr.Namespace, r.logger)
+	}
+	return util.NewRestClient(strings.TrimSuffix(configurationSync.RemoteUrl, "/"), client, credentials)
+}
+
+// reconcileConsistencyCheck runs verification of replicated data on demand in any mode
+// and periodically in `standby` mode
+func (r DisasterRecoveryReconciler) reconcileConsistencyCheck() {
+	watcher := r.reconciler.ConsistencyWatcher
+	consistencyCheck := r.cr.Spec.DisasterRecovery.ConsistencyCheck
+	if consistencyCheck == nil {
+		consistencyCheck = &opensearchservice.ConsistencyCheck{}
+	}
+	request := r.cr.Annotations[util.ConsistencyCheckAnnotationKey]
+	lastStatus := r.cr.Status.DisasterRecoveryStatus.ConsistencyCheck
+	if request != "" && !watcher.isRequested(request) && (lastStatus == nil || lastStatus.Request != request) {
+		if helper, err := r.prepareConsistencyHelper(consistencyCheck); err != nil {
+			r.logger.Error(err, "Unable to start requested consistency check")
+		} else {
+			r.logger.Info("Start requested consistency check")
+			watcher.runOnce(helper, request)
+		}
+	}
+
+	if r.cr.Spec.DisasterRecovery.Mode != "standby" || !consistencyCheck.Enabled {
+		watcher.stop()
+		delete(r.reconciler.ResourceHashes, drConsistencyCheckHashName)
+		return
+	}
+	consistencyCheckHash, err := util.Hash(consistencyCheck)
+	if err != nil {
+		r.logger.Error(err, "Unable to calculate hash of consistency check parameters")
+		return
+	}
+	if r.reconciler.ResourceHashes[drConsistencyCheckHashName] == consistencyCheckHash && watcher.isRunning() {
+		return
+	}
+	helper, err := r.prepareConsistencyHelper(consistencyCheck)
+	if err != nil {
+		r.logger.Error(err, "Unable to start periodic consistency check")
+		return
+	}
+	r.reconciler.ResourceHashes[drConsistencyCheckHashName] = consistencyCheckHash
+	checkInterval := defaultConsistencyCheckInterval
+	if consistencyCheck.Interval > 0 {
+		checkInterval = time.Duration(consistencyCheck.Interval) * time.Second
+	}
+	watcher.start(helper, checkInterval)
+}
+
+func (r DisasterRecoveryReconciler) prepareConsistencyHelper(consistencyCheck *opensearchservice.ConsistencyCheck) (ConsistencyHelper, error) {
+	replicationManager, err := r.getReplicationManager()
+	if err != nil {
+		return ConsistencyHelper{}, err
+	}
+	return ConsistencyHelper{
+		logger:             r.logger,
+		replicationManager: replicationManager,
+		statusUpdater:      util.NewStatusUpdater(r.reconciler.Client, r.cr),
+		mode:               r.cr.Spec.DisasterRecovery.Mode,
+		sampleSize:         consistencyCheck.SampleSize,
+	}, nil
+}
+
 func (r DisasterRecoveryReconciler) enableClientServices() error {
 	r.logger.Info("Enable client service")
 	if err := r.reconciler.enableClientService(r.cr.Name, r.cr.Namespace, r.logger); err != nil {
@@ -246,15 +426,43 @@ func (r DisasterRecoveryReconciler) updateDisasterRecoveryStatus(status string,
 	})
 }
 
+// updateUsersRecoveryStatus updates the state of users recovery and resets the progress of the previous one
 func (r DisasterRecoveryReconciler) updateUsersRecoveryStatus(state string) error {
 	statusUpdater := util.NewStatusUpdater(r.reconciler.Client, r.cr)
 	return statusUpdater.UpdateStatusWithRetry(func(cr *opensearchservice.OpenSearchService) {
 		cr.Status.DisasterRecoveryStatus.UsersRecoveryState = state
+		cr.Status.DisasterRecoveryStatus.UsersRecovery = nil
 	})
 }
 
+// addReplicationAction records the replication repair action in Disaster Recovery status
+// keeping only the last maxReplicationActions entries
+func (r DisasterRecoveryReconciler) addReplicationAction(index string, action string, actionErr error) {
+	replicationAction := opensearchservice.ReplicationAction{
+		Index:  index,
+		Action: action,
+		Result: replicationActionSucceeded,
+		Time:   time.Now().UTC().Format(time.RFC3339),
+	}
+	if actionErr != nil {
+		replicationAction.Result = replicationActionFailed
+		replicationAction.Message = actionErr.Error()
+	}
+	statusUpdater := util.NewStatusUpdater(r.reconciler.Client, r.cr)
+	err := statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		actions := append(instance.Status.DisasterRecoveryStatus.ReplicationActions, replicationAction)
+		if len(actions) > maxReplicationActions {
+			actions = actions[len(actions)-maxReplicationActions:]
+		}
+		instance.Status.DisasterRecoveryStatus.ReplicationActions = actions
+	})
+	if err != nil {
+		r.logger.Error(err, "Unable to record replication action in status")
+	}
+}
+
 func (r DisasterRecoveryReconciler) removePreviousReplication(replicationManager ReplicationManager) error {
-	if err := replicationManager.RemoveReplicationRule(); err != nil {
+	if err := replicationManager.RemoveReplicationRules(); err != nil {
 		r.logger.Error(err, "can not delete autofollow replication rule")
 		return err
 	}
@@ -265,8 +473,7 @@ func (r DisasterRecoveryReconciler) removePreviousReplication(replicationManager
 		r.logger.Error(err, "can not stop all running replication tasks")
 		return err
 	}
-	r.logger.Info(fmt.Sprintf("Try to stop running replication for all indices match replication pattern [%s].", replicationManager.pattern))
-	if err := replicationManager.StopIndicesReplicationByPattern(replicationManager.pattern); err != nil {
+	if err := replicationManager.StopRulesIndicesReplication(); err != nil {
 		r.logger.Error(err, "can not stop OpenSearch indices by pattern during switchover process to `active` state.")
 		return err
 	}
@@ -301,8 +508,8 @@ func (r DisasterRecoveryReconciler) runReplicationProcess(replicationManager Rep
 	return nil
 }
 
-func (r DisasterRecoveryReconciler) checkReplication(restClient util.RestClient) error {
-	replicationChecker := disasterrecovery.NewReplicationCheckerWithClient(restClient)
+func (r DisasterRecoveryReconciler) checkReplication(replicationManager ReplicationManager) error {
+	replicationChecker := disasterrecovery.NewReplicationCheckerWithClient(replicationManager.restClient, replicationManager.matches)
 	err := wait.Poll(interval, timeout, func() (bool, error) {
 		status, err := replicationChecker.CheckReplication()
 		if err != nil {
@@ -347,6 +554,8 @@ func (r DisasterRecoveryReconciler) recoverUsers() (string, error) {
 	if state != usersRecoveryRunningState {
 		state = usersRecoveryIdleState
 	}
+	progressStatus := r.cr.Status.DisasterRecoveryStatus.UsersRecovery
+	retries := 0
 	for state != usersRecoveryDoneState && state != usersRecoveryFailedState {
 		if state == usersRecoveryIdleState {
 			err := wait.PollImmediate(interval, timeout, func() (bool, error) {
@@ -368,13 +577,22 @@ func (r DisasterRecoveryReconciler) recoverUsers() (string, error) {
 			}
 		}
 		time.Sleep(time.Second * 5)
-		statusCode, response, err := adapterRestClient.SendRequest(http.MethodGet,
-			"api/v2/dbaas/adapter/opensearch/users/restore-password/state", nil)
-		if err != nil || statusCode != http.StatusOK {
-			r.logger.Error(err, fmt.Sprintf("Unable to get state of procedure: %s", string(response)))
+		progress, err := getUsersRecoveryProgress(adapterRestClient)
+		if err != nil {
+			r.logger.Error(err, "Unable to get state of procedure")
 			continue
 		}
-		state = string(response)
+		state = progress.State
+		progressStatus = r.updateUsersRecoveryProgress(progressStatus, progress)
+		if state == usersRecoveryFailedState && len(progress.FailedUsers) > 0 && retries < maxUsersRecoveryRetries {
+			retries++
+			r.logger.Info(fmt.Sprintf("Retry recovery of %d failed users", len(progress.FailedUsers)))
+			if err = retryFailedUsers(adapterRestClient); err != nil {
+				r.logger.Error(err, "Unable to retry recovery of failed users")
+				continue
+			}
+			state = usersRecoveryRunningState
+		}
 	}
 	r.logger.Info(fmt.Sprintf("Users recovery is finished with [%s] state", state))
 	if state == usersRecoveryFailedState {
@@ -397,24 +615,46 @@ func (r DisasterRecoveryReconciler) buildAdapterRestClient() *util.RestClient {
 }
 
 // Check connection with other side to prevent a situation with stand-by mode on both sides.
-// If operator received empty response (EOF error), it means service's endpoints on the other side are up, so the other side is active.
+// If the Disaster Recovery server of the other side is known from fencing configuration, its mode is requested.
+// If the REST URL of OpenSearch on the other side is known from configuration synchronization, the cluster health
+// is requested with the same TLS configuration and credentials as for the synchronization.
+// Otherwise, the connection to the replication service of the other side is opened without sending any data,
+// if it is established, it means service's endpoints on the other side are up, so the other side is active.
 func (r DisasterRecoveryReconciler) checkConnectionWithOtherSide() error {
 	r.logger.Info("Checking connection with other side")
+	if r.isFencingEnabled() && r.cr.Spec.DisasterRecovery.Fencing.RemoteUrl != "" {
+		state, err := r.getRemoteFencingState(r.cr.Spec.DisasterRecovery.Fencing)
+		if err != nil {
+			r.logger.Error(err, "Operator can't get the state of the other side")
+			return err
+		}
+		if !state.IsActive() {
+			return fmt.Errorf("the other side is in [%s] mode, move it into active mode first", state.Mode)
+		}
+		r.logger.Info("Other side is active")
+		return nil
+	}
+
+	if configurationSync := r.cr.Spec.DisasterRecovery.ConfigurationSync; configurationSync != nil && configurationSync.RemoteUrl != "" {
+		if _, err := r.buildRemoteRestClient(configurationSync).SendRequestWithStatusCodeCheck(http.MethodGet, "_cluster/health", nil); err != nil {
+			r.logger.Error(err, "Operator can't connect to OpenSearch on the other side. "+
+				"To move current side into standby mode, need to move opposite side to active mode first.")
+			return fmt.Errorf("there is no connection with OpenSearch on the other side: %w", err)
+		}
+		r.logger.Info("Other side is active")
+		return nil
+	}
 
 	cmName := r.cr.Spec.DisasterRecovery.ConfigMapName
 	configMap, err := r.reconciler.findConfigMap(cmName, r.cr.Namespace, r.logger)
 	if err != nil {
 		return err
 	}
-	otherSideURL := configMap.Data[replicationRemoteServiceKey]
-
-	client := http.Client{
-		Timeout: 5 * time.Second,
-	}
-
-	_, err = client.Get(fmt.Sprintf("http://%s", otherSideURL))
-
-	if err != nil && strings.Contains(err.Error(), "EOF") {
+	otherSideAddress := configMap.Data[replicationRemoteServiceKey]
+	// The replication service is the transport endpoint, so credentials and requests are not sent to it
+	connection, err := net.DialTimeout("tcp", otherSideAddress, otherSideConnectionTimeout)
+	if err == nil {
+		_ = connection.Close()
 		r.logger.Info("Other side is active")
 		return nil
 	}
@@ -424,13 +664,24 @@ func (r DisasterRecoveryReconciler) checkConnectionWithOtherSide() error {
 	return fmt.Errorf("there is active replication on the other side")
 }
 
-func (r DisasterRecoveryReconciler) getReplicationManager() ReplicationManager {
+func (r DisasterRecoveryReconciler) getReplicationManager() (ReplicationManager, error) {
 	cmName := r.cr.Spec.DisasterRecovery.ConfigMapName
-	configMap, _ := r.reconciler.findConfigMap(cmName, r.cr.Namespace, r.logger)
+	configMap, err := r.reconciler.findConfigMap(cmName, r.cr.Namespace, r.logger)
+	if err != nil {
+		return ReplicationManager{}, err
+	}
 	remoteService := configMap.Data[replicationRemoteServiceKey]
-	pattern := configMap.Data[replicationPatternKey]
+	rules, err := parseReplicationRules(configMap.Data)
+	if err != nil {
+		return ReplicationManager{}, err
+	}
 	restClient := r.getRestClient()
-	return *NewReplicationManager(*restClient, remoteService, pattern, r.logger)
+	replicationManager := *NewReplicationManager(*restClient, remoteService, rules, r.logger)
+	// Replication state reported by the disaster recovery server does not take excluded indices into account
+	if r.replicationWatcher.Info != nil {
+		r.replicationWatcher.Info.SetIndexMatcher(replicationManager.matches)
+	}
+	return replicationManager, nil
 }
 
 func (r DisasterRecoveryReconciler) getRestClient() *util.RestClient {
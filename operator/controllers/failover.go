@@ -0,0 +1,155 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const leaderStatsPath = "_plugins/_replication/leader_stats"
+
+// isFailover checks whether the switchover to `active` mode is performed without the other side
+func (r DisasterRecoveryReconciler) isFailover() bool {
+	return r.cr.Spec.DisasterRecovery.Mode == "active" && r.cr.Spec.DisasterRecovery.Failover
+}
+
+// failover promotes the current side without any requests to the leader: the last known checkpoints
+// of follower indices are recorded as the estimate of lost data and the replication is stopped locally
+func (r DisasterRecoveryReconciler) failover(replicationManager ReplicationManager) error {
+	r.logger.Info("Start failover, the checks of the other side are skipped")
+	checkpoints, err := collectCheckpoints(replicationManager)
+	if err != nil {
+		r.logger.Error(err, "Unable to collect last known checkpoints of follower indices")
+	}
+	if err = replicationManager.RemoveReplicationRules(); err != nil {
+		r.logger.Error(err, "can not delete autofollow replication rules during failover")
+		return err
+	}
+	for _, rule := range replicationManager.rules {
+		if err = replicationManager.StopIndicesReplicationByPattern(rule.indexExpression()); err != nil {
+			r.logger.Error(err, fmt.Sprintf("can not stop replication of [%s] indices during failover", rule.indexExpression()))
+			return err
+		}
+	}
+	// Repeated failover finds no replicated indices, so the result of the unfinished one is kept
+	previousStatus := r.cr.Status.DisasterRecoveryStatus.Failover
+	if len(checkpoints) == 0 && previousStatus != nil && previousStatus.ResyncRequired {
+		return nil
+	}
+	failoverStatus := newFailoverStatus(checkpoints)
+	r.logger.Info(fmt.Sprintf("Failover is performed, estimated number of lost operations is %d",
+		failoverStatus.EstimatedLostOperations))
+	return r.updateFailoverStatus(&failoverStatus)
+}
+
+// resetFailoverFlag clears `failover` property of the custom resource after the successful failover,
+// so the next switchover to `active` mode checks the other side again
+func (r DisasterRecoveryReconciler) resetFailoverFlag() error {
+	instance := &opensearchservice.OpenSearchService{}
+	if err := r.reconciler.Client.Get(context.TODO(),
+		types.NamespacedName{Name: r.cr.Name, Namespace: r.cr.Namespace}, instance); err != nil {
+		return err
+	}
+	if instance.Spec.DisasterRecovery == nil || !instance.Spec.DisasterRecovery.Failover {
+		return nil
+	}
+	original := instance.DeepCopy()
+	instance.Spec.DisasterRecovery.Failover = false
+	return r.reconciler.Client.Patch(context.TODO(), instance, client.MergeFrom(original))
+}
+
+// checkResyncAfterFailover clears the resync flag as soon as the other side follows the indices of the current one
+func (r DisasterRecoveryReconciler) checkResyncAfterFailover() {
+	failoverStatus := r.cr.Status.DisasterRecoveryStatus.Failover
+	if failoverStatus == nil || !failoverStatus.ResyncRequired {
+		return
+	}
+	body, err := r.getRestClient().SendRequestWithStatusCodeCheck(http.MethodGet, leaderStatsPath, nil)
+	if err != nil {
+		r.logger.Error(err, "Unable to get leader replication statistics")
+		return
+	}
+	var leaderStats LeaderStats
+	if err = json.Unmarshal(body, &leaderStats); err != nil {
+		r.logger.Error(err, "Unable to parse leader replication statistics")
+		return
+	}
+	if leaderStats.NumReplicatedIndices == 0 {
+		return
+	}
+	r.logger.Info("The other side replicates indices after failover, resync is finished")
+	resyncedStatus := *failoverStatus
+	resyncedStatus.ResyncRequired = false
+	if err = r.updateFailoverStatus(&resyncedStatus); err != nil {
+		r.logger.Error(err, "Unable to update failover status")
+	}
+}
+
+func (r DisasterRecoveryReconciler) updateFailoverStatus(failoverStatus *opensearchservice.FailoverStatus) error {
+	statusUpdater := util.NewStatusUpdater(r.reconciler.Client, r.cr)
+	return statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		instance.Status.DisasterRecoveryStatus.Failover = failoverStatus
+	})
+}
+
+// collectCheckpoints returns the last known checkpoints of follower indices with running replication
+func collectCheckpoints(replicationManager ReplicationManager) ([]opensearchservice.IndexCheckpoint, error) {
+	indexNames, err := replicationManager.GetRulesIndices()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(indexNames)
+	var checkpoints []opensearchservice.IndexCheckpoint
+	for _, index := range indexNames {
+		replicationIndexStats, err := replicationManager.getIndexReplicationStatus(index)
+		if err != nil {
+			return checkpoints, err
+		}
+		if replicationIndexStats.Status == replicationNotInProgressStatus {
+			continue
+		}
+		checkpoints = append(checkpoints, opensearchservice.IndexCheckpoint{
+			Index:              index,
+			Status:             replicationIndexStats.Status,
+			LeaderCheckpoint:   int64(replicationIndexStats.Details.LeaderCheckpoint),
+			FollowerCheckpoint: int64(replicationIndexStats.Details.FollowerCheckpoint),
+		})
+	}
+	return checkpoints, nil
+}
+
+func newFailoverStatus(checkpoints []opensearchservice.IndexCheckpoint) opensearchservice.FailoverStatus {
+	failoverStatus := opensearchservice.FailoverStatus{
+		Time:           time.Now().UTC().Format(time.RFC3339),
+		Indices:        checkpoints,
+		ResyncRequired: true,
+	}
+	for _, checkpoint := range checkpoints {
+		if checkpoint.LeaderCheckpoint > checkpoint.FollowerCheckpoint {
+			failoverStatus.EstimatedLostOperations += checkpoint.LeaderCheckpoint - checkpoint.FollowerCheckpoint
+		}
+	}
+	return failoverStatus
+}
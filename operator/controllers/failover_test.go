@@ -0,0 +1,101 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCollectCheckpoints_EstimatesLostOperations(t *testing.T) {
+	responses := map[string]string{
+		"/_cat/indices/*": `[{"index":"behind"},{"index":"synced"},{"index":"stopped"}]`,
+		"/_plugins/_replication/behind/_status": `{"status":"SYNCING",
+			"syncing_details":{"leader_checkpoint":120,"follower_checkpoint":100,"seq_no":100}}`,
+		"/_plugins/_replication/synced/_status": `{"status":"PAUSED",
+			"syncing_details":{"leader_checkpoint":7,"follower_checkpoint":7,"seq_no":7}}`,
+		"/_plugins/_replication/stopped/_status": `{"status":"REPLICATION NOT IN PROGRESS"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+	replicationManager := NewReplicationManager(*util.NewRestClient(server.URL, http.Client{}, util.Credentials{}), "",
+		[]ReplicationRule{{Patterns: []string{"*"}}}, logr.Discard())
+
+	checkpoints, err := collectCheckpoints(*replicationManager)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(checkpoints) != 2 || checkpoints[0].Index != "behind" || checkpoints[1].Index != "synced" {
+		t.Fatalf("expected checkpoints of indices with running replication, got %+v", checkpoints)
+	}
+	if checkpoints[1].Status != "PAUSED" {
+		t.Errorf("expected replication status to be recorded, got %+v", checkpoints[1])
+	}
+
+	failoverStatus := newFailoverStatus(checkpoints)
+	if failoverStatus.EstimatedLostOperations != 20 {
+		t.Errorf("expected 20 lost operations, got %d", failoverStatus.EstimatedLostOperations)
+	}
+	if !failoverStatus.ResyncRequired || failoverStatus.Time == "" {
+		t.Errorf("unexpected failover status: %+v", failoverStatus)
+	}
+}
+
+func TestResetFailoverFlag_ClearsFlagAfterFailover(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := opensearchservice.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to register scheme: %v", err)
+	}
+	cr := &opensearchservice.OpenSearchService{
+		ObjectMeta: metav1.ObjectMeta{Name: "opensearch", Namespace: "opensearch-service"},
+		Spec: opensearchservice.OpenSearchServiceSpec{
+			DisasterRecovery: &opensearchservice.DisasterRecovery{Mode: "active", Failover: true},
+		},
+	}
+	reconciler := DisasterRecoveryReconciler{
+		cr:         cr,
+		logger:     logr.Discard(),
+		reconciler: &OpenSearchServiceReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr.DeepCopy()).Build()},
+	}
+	if !reconciler.isFailover() {
+		t.Fatal("expected failover to be requested")
+	}
+	if err := reconciler.resetFailoverFlag(); err != nil {
+		t.Fatalf("unable to reset failover flag: %v", err)
+	}
+
+	instance := &opensearchservice.OpenSearchService{}
+	if err := reconciler.reconciler.Client.Get(context.TODO(),
+		types.NamespacedName{Name: "opensearch", Namespace: "opensearch-service"}, instance); err != nil {
+		t.Fatalf("unable to get custom resource: %v", err)
+	}
+	if instance.Spec.DisasterRecovery.Failover || instance.Spec.DisasterRecovery.Mode != "active" {
+		t.Errorf("expected only failover flag to be reset, got %+v", instance.Spec.DisasterRecovery)
+	}
+}
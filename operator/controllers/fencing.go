@@ -0,0 +1,350 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/disasterrecovery"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	fencingPath             = "fencing"
+	configMapWitnessKind    = "ConfigMap"
+	leaseWitnessKind        = "Lease"
+	witnessEpochKey         = "epoch"
+	witnessSiteKey          = "site"
+	witnessKubeconfigKey    = "kubeconfig"
+	fencingRequestTimeout   = 5 * time.Second
+	fencingConflictTemplate = "split-brain protection: %s"
+)
+
+// witnessRecord is the epoch acquired by the last side switched to `active` mode
+type witnessRecord struct {
+	epoch int64
+	site  string
+}
+
+// fencingWitness stores the witness record in the third-party object. The update fails
+// if the object has been changed by the other side since it was read.
+type fencingWitness interface {
+	get() (witnessRecord, error)
+	update(record witnessRecord) error
+}
+
+type configMapWitness struct {
+	client    client.Client
+	name      string
+	namespace string
+	configMap *corev1.ConfigMap
+}
+
+type leaseWitness struct {
+	client    client.Client
+	name      string
+	namespace string
+	lease     *coordinationv1.Lease
+}
+
+func (r DisasterRecoveryReconciler) isFencingEnabled() bool {
+	fencing := r.cr.Spec.DisasterRecovery.Fencing
+	return fencing != nil && fencing.Enabled
+}
+
+func (r DisasterRecoveryReconciler) getLocalFencingStatus() opensearchservice.FencingStatus {
+	if fencingStatus := r.cr.Status.DisasterRecoveryStatus.Fencing; fencingStatus != nil {
+		return *fencingStatus
+	}
+	return opensearchservice.FencingStatus{}
+}
+
+// reconcileFencing acquires the new epoch when the current side is switched to `active` mode and checks
+// that the higher epoch is not active elsewhere while it stays in `active` mode. In `standby` mode
+// the current side adopts the epoch of the active side. The error means the current side is fenced.
+func (r DisasterRecoveryReconciler) reconcileFencing(switching bool) error {
+	fencing := r.cr.Spec.DisasterRecovery.Fencing
+	local := r.getLocalFencingStatus()
+	remote, err := r.getRemoteFencingState(fencing)
+	if err != nil {
+		r.logger.Error(err, "Unable to get fencing state of the other side, only the witness is checked")
+	}
+	witness, err := r.getFencingWitness(fencing)
+	if err != nil {
+		return r.updateFencingStatus(local.Epoch, err)
+	}
+	var record *witnessRecord
+	if witness != nil {
+		current, err := witness.get()
+		if err != nil {
+			r.logger.Error(err, "Unable to read fencing witness")
+			if r.cr.Spec.DisasterRecovery.Mode == "active" && (switching || local.Conflict != "") {
+				return r.updateFencingStatus(local.Epoch, fmt.Errorf("unable to read fencing witness: %w", err))
+			}
+		} else {
+			record = &current
+		}
+	}
+
+	if r.cr.Spec.DisasterRecovery.Mode != "active" {
+		return r.updateFencingStatus(observedEpoch(local.Epoch, remote, record), nil)
+	}
+	if err = checkEpoch(local.Epoch, remote, record, fencing.SiteName); err != nil {
+		return r.updateFencingStatus(local.Epoch, err)
+	}
+	if !switching && local.Conflict == "" && r.cr.Status.DisasterRecoveryStatus.Fencing != nil {
+		return nil
+	}
+	epoch := observedEpoch(local.Epoch, remote, record) + 1
+	if witness != nil {
+		if err = witness.update(witnessRecord{epoch: epoch, site: fencing.SiteName}); err != nil {
+			return r.updateFencingStatus(local.Epoch, fmt.Errorf("unable to acquire epoch %d in fencing witness: %w", epoch, err))
+		}
+	}
+	r.logger.Info(fmt.Sprintf("Epoch %d is acquired for active mode", epoch))
+	return r.updateFencingStatus(epoch, nil)
+}
+
+// fence keeps client services of the current side disabled while the higher epoch is active elsewhere
+func (r DisasterRecoveryReconciler) fence(conflict error) error {
+	r.logger.Error(conflict, "The current side is fenced and does not serve clients")
+	if err := r.disableClientServices(); err != nil {
+		r.logger.Error(err, "Unable to disable client services of fenced side")
+	}
+	if err := r.updateDisasterRecoveryStatus("failed", fmt.Sprintf("Error occurred during OpenSearch switching: %v", conflict),
+		r.cr.Status.DisasterRecoveryStatus.UsersRecoveryState); err != nil {
+		r.logger.Error(err, "Unable to update Disaster Recovery status")
+	}
+	return conflict
+}
+
+// updateFencingStatus records the epoch and the conflict of the current side and returns the conflict
+func (r DisasterRecoveryReconciler) updateFencingStatus(epoch int64, conflict error) error {
+	fencingStatus := opensearchservice.FencingStatus{Epoch: epoch}
+	if conflict != nil {
+		conflict = fmt.Errorf(fencingConflictTemplate, conflict.Error())
+		fencingStatus.Conflict = conflict.Error()
+	}
+	current := r.cr.Status.DisasterRecoveryStatus.Fencing
+	if current != nil && *current == fencingStatus {
+		return conflict
+	}
+	statusUpdater := util.NewStatusUpdater(r.reconciler.Client, r.cr)
+	err := statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		instance.Status.DisasterRecoveryStatus.Fencing = &fencingStatus
+	})
+	if err != nil {
+		r.logger.Error(err, "Unable to update fencing status")
+	}
+	return conflict
+}
+
+// getRemoteFencingState requests the fencing state from the operator Disaster Recovery server on the other side
+func (r DisasterRecoveryReconciler) getRemoteFencingState(fencing *opensearchservice.Fencing) (*disasterrecovery.FencingState, error) {
+	if fencing.RemoteUrl == "" {
+		return nil, nil
+	}
+	secret, err := r.reconciler.findSecret(fencing.SecretName, r.cr.Namespace, r.logger)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", strings.TrimSuffix(fencing.RemoteUrl, "/"), fencingPath), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", secret.Data[disasterrecovery.FencingTokenKey]))
+	httpClient, err := r.serverSecurity.NewClient(fencingRequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fencing request to the other side failed with [%d] status code: %s", response.StatusCode, body)
+	}
+	var state disasterrecovery.FencingState
+	if err = json.Unmarshal(body, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// getFencingWitness returns the witness in the shared cluster if kubeconfig secret is specified
+// and in the current cluster otherwise, or nil if the witness is not configured
+func (r DisasterRecoveryReconciler) getFencingWitness(fencing *opensearchservice.Fencing) (fencingWitness, error) {
+	witness := fencing.Witness
+	if witness == nil {
+		return nil, nil
+	}
+	if fencing.SiteName == "" {
+		return nil, fmt.Errorf("site name must be specified to use fencing witness")
+	}
+	witnessClient := r.reconciler.Client
+	if witness.KubeconfigSecretName != "" {
+		secret, err := r.reconciler.findSecret(witness.KubeconfigSecretName, r.cr.Namespace, r.logger)
+		if err != nil {
+			return nil, err
+		}
+		config, err := clientcmd.RESTConfigFromKubeConfig(secret.Data[witnessKubeconfigKey])
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse kubeconfig of fencing witness: %w", err)
+		}
+		if witnessClient, err = client.New(config, client.Options{}); err != nil {
+			return nil, err
+		}
+	}
+	namespace := witness.Namespace
+	if namespace == "" {
+		namespace = r.cr.Namespace
+	}
+	return newFencingWitness(witnessClient, witness.Kind, witness.Name, namespace)
+}
+
+func newFencingWitness(witnessClient client.Client, kind string, name string, namespace string) (fencingWitness, error) {
+	switch kind {
+	case configMapWitnessKind:
+		return &configMapWitness{client: witnessClient, name: name, namespace: namespace}, nil
+	case leaseWitnessKind:
+		return &leaseWitness{client: witnessClient, name: name, namespace: namespace}, nil
+	}
+	return nil, fmt.Errorf("unsupported kind of fencing witness [%s], must be %s or %s", kind,
+		configMapWitnessKind, leaseWitnessKind)
+}
+
+// checkEpoch fails if the other side is active with the higher epoch
+// or the higher epoch has been acquired by the other side in the witness
+func checkEpoch(local int64, remote *disasterrecovery.FencingState, record *witnessRecord, site string) error {
+	if remote != nil && remote.IsActive() && remote.Epoch > local {
+		return fmt.Errorf("the other side is active with epoch %d that is higher than epoch %d of the current side",
+			remote.Epoch, local)
+	}
+	if record != nil && record.site != site && record.epoch > local {
+		return fmt.Errorf("fencing witness contains epoch %d of [%s] side that is higher than epoch %d of the current side",
+			record.epoch, record.site, local)
+	}
+	return nil
+}
+
+// observedEpoch returns the highest epoch known to the current side
+func observedEpoch(local int64, remote *disasterrecovery.FencingState, record *witnessRecord) int64 {
+	epoch := local
+	if remote != nil && remote.Epoch > epoch {
+		epoch = remote.Epoch
+	}
+	if record != nil && record.epoch > epoch {
+		epoch = record.epoch
+	}
+	return epoch
+}
+
+func (w *configMapWitness) get() (witnessRecord, error) {
+	configMap := &corev1.ConfigMap{}
+	err := w.client.Get(context.TODO(), types.NamespacedName{Name: w.name, Namespace: w.namespace}, configMap)
+	if errors.IsNotFound(err) {
+		w.configMap = nil
+		return witnessRecord{}, nil
+	}
+	if err != nil {
+		return witnessRecord{}, err
+	}
+	w.configMap = configMap
+	record := witnessRecord{site: configMap.Data[witnessSiteKey]}
+	if value := configMap.Data[witnessEpochKey]; value != "" {
+		if record.epoch, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return witnessRecord{}, fmt.Errorf("unable to parse epoch of fencing witness: %w", err)
+		}
+	}
+	return record, nil
+}
+
+func (w *configMapWitness) update(record witnessRecord) error {
+	data := map[string]string{
+		witnessEpochKey: strconv.FormatInt(record.epoch, 10),
+		witnessSiteKey:  record.site,
+	}
+	if w.configMap == nil {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: w.name, Namespace: w.namespace},
+			Data:       data,
+		}
+		return w.client.Create(context.TODO(), configMap)
+	}
+	w.configMap.Data = data
+	return w.client.Update(context.TODO(), w.configMap)
+}
+
+func (w *leaseWitness) get() (witnessRecord, error) {
+	lease := &coordinationv1.Lease{}
+	err := w.client.Get(context.TODO(), types.NamespacedName{Name: w.name, Namespace: w.namespace}, lease)
+	if errors.IsNotFound(err) {
+		w.lease = nil
+		return witnessRecord{}, nil
+	}
+	if err != nil {
+		return witnessRecord{}, err
+	}
+	w.lease = lease
+	record := witnessRecord{}
+	if lease.Spec.HolderIdentity != nil {
+		record.site = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.LeaseTransitions != nil {
+		record.epoch = int64(*lease.Spec.LeaseTransitions)
+	}
+	return record, nil
+}
+
+// update stores the site as the lease holder and the epoch as the number of lease transitions
+func (w *leaseWitness) update(record witnessRecord) error {
+	site := record.site
+	epoch := int32(record.epoch)
+	acquireTime := metav1.NewMicroTime(time.Now())
+	spec := coordinationv1.LeaseSpec{
+		HolderIdentity:   &site,
+		LeaseTransitions: &epoch,
+		AcquireTime:      &acquireTime,
+	}
+	if w.lease == nil {
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: w.name, Namespace: w.namespace},
+			Spec:       spec,
+		}
+		return w.client.Create(context.TODO(), lease)
+	}
+	w.lease.Spec = spec
+	return w.client.Update(context.TODO(), w.lease)
+}
@@ -0,0 +1,95 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/Netcracker/qubership-opensearch/operator/disasterrecovery"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckEpoch(t *testing.T) {
+	tests := []struct {
+		name     string
+		local    int64
+		remote   *disasterrecovery.FencingState
+		record   *witnessRecord
+		conflict bool
+	}{
+		{name: "no other side", local: 1},
+		{name: "active remote with the same epoch", local: 2,
+			remote: &disasterrecovery.FencingState{Epoch: 2, Mode: "active"}},
+		{name: "active remote with higher epoch", local: 1,
+			remote: &disasterrecovery.FencingState{Epoch: 2, Mode: "active"}, conflict: true},
+		{name: "fenced remote with higher epoch", local: 1,
+			remote: &disasterrecovery.FencingState{Epoch: 2, Mode: "active", Conflict: "fenced"}},
+		{name: "standby remote with higher epoch", local: 1,
+			remote: &disasterrecovery.FencingState{Epoch: 2, Mode: "standby"}},
+		{name: "witness acquired by current site", local: 1, record: &witnessRecord{epoch: 3, site: "left"}},
+		{name: "witness acquired by other site", local: 1, record: &witnessRecord{epoch: 3, site: "right"}, conflict: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkEpoch(test.local, test.remote, test.record, "left")
+			if (err != nil) != test.conflict {
+				t.Errorf("expected conflict: %t, got %v", test.conflict, err)
+			}
+		})
+	}
+}
+
+func TestObservedEpoch(t *testing.T) {
+	epoch := observedEpoch(2, &disasterrecovery.FencingState{Epoch: 4, Mode: "standby"}, &witnessRecord{epoch: 3})
+	if epoch != 4 {
+		t.Errorf("expected epoch 4, got %d", epoch)
+	}
+	if epoch = observedEpoch(5, nil, &witnessRecord{epoch: 3}); epoch != 5 {
+		t.Errorf("expected epoch 5, got %d", epoch)
+	}
+}
+
+func TestFencingWitness_RejectsConcurrentUpdate(t *testing.T) {
+	for _, kind := range []string{configMapWitnessKind, leaseWitnessKind} {
+		t.Run(kind, func(t *testing.T) {
+			witnessClient := fake.NewClientBuilder().Build()
+			left, _ := newFencingWitness(witnessClient, kind, "opensearch-fencing", "opensearch")
+			right, _ := newFencingWitness(witnessClient, kind, "opensearch-fencing", "opensearch")
+
+			if record, err := left.get(); err != nil || record.epoch != 0 {
+				t.Fatalf("expected empty witness, got %+v, %v", record, err)
+			}
+			if err := left.update(witnessRecord{epoch: 1, site: "left"}); err != nil {
+				t.Fatalf("unable to create witness: %v", err)
+			}
+
+			record, err := left.get()
+			if err != nil || record.epoch != 1 || record.site != "left" {
+				t.Fatalf("unexpected witness record: %+v, %v", record, err)
+			}
+			if _, err = right.get(); err != nil {
+				t.Fatalf("unable to read witness: %v", err)
+			}
+			if err = right.update(witnessRecord{epoch: 2, site: "right"}); err != nil {
+				t.Fatalf("unable to update witness: %v", err)
+			}
+			if err = left.update(witnessRecord{epoch: 2, site: "left"}); err == nil {
+				t.Error("expected update with stale witness record to fail")
+			}
+		})
+	}
+}
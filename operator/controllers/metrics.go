@@ -0,0 +1,70 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Disaster Recovery metrics are exposed on the operator metrics endpoint
+var (
+	consistencyCheckedIndices = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "opensearch_dr_consistency_checked_indices",
+		Help: "Number of replicated indices checked during the last data consistency verification",
+	})
+	consistencyMismatchedIndices = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "opensearch_dr_consistency_mismatched_indices",
+		Help: "Number of indices which differ from the leader ones during the last data consistency verification",
+	})
+	consistencyIndexMismatch = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "opensearch_dr_consistency_index_mismatch",
+		Help: "Index differs from the leader one during the last data consistency verification",
+	}, []string{"index"})
+	consistencyLastCheckTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "opensearch_dr_consistency_last_check_timestamp_seconds",
+		Help: "Time of the last data consistency verification",
+	})
+	replicationWatcherChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "opensearch_dr_replication_watcher_checks_total",
+		Help: "Number of replication checks performed by the replication watcher",
+	}, []string{"result"})
+	replicationWatcherRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "opensearch_dr_replication_watcher_restarts_total",
+		Help: "Number of replication repair actions performed by the replication watcher",
+	}, []string{"action"})
+	replicationWatcherBudgetExhausted = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "opensearch_dr_replication_watcher_budget_exhausted",
+		Help: "Replication watcher is stopped because the maximum number of replication restarts is reached",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(consistencyCheckedIndices, consistencyMismatchedIndices, consistencyIndexMismatch,
+		consistencyLastCheckTimestamp, replicationWatcherChecks, replicationWatcherRestarts, replicationWatcherBudgetExhausted)
+}
+
+func recordConsistencyMetrics(status opensearchservice.ConsistencyCheckStatus, checkTime time.Time) {
+	consistencyCheckedIndices.Set(float64(status.CheckedIndices))
+	consistencyMismatchedIndices.Set(float64(len(status.Mismatches)))
+	consistencyIndexMismatch.Reset()
+	for _, mismatch := range status.Mismatches {
+		consistencyIndexMismatch.WithLabelValues(mismatch.Index).Set(1)
+	}
+	consistencyLastCheckTimestamp.Set(float64(checkTime.Unix()))
+}
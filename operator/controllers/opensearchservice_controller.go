@@ -230,9 +230,12 @@ func (r *OpenSearchServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	statusPredicate := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			// Ignore updates to CR status in which case metadata.Generation does not change
-			if value, ok := e.ObjectNew.GetAnnotations()[util.SwitchoverAnnotationKey]; ok {
-				if value != e.ObjectOld.GetAnnotations()[util.SwitchoverAnnotationKey] {
-					return true
+			for _, key := range []string{util.SwitchoverAnnotationKey, util.SwitchoverDryRunAnnotationKey,
+				util.ConsistencyCheckAnnotationKey} {
+				if value, ok := e.ObjectNew.GetAnnotations()[key]; ok {
+					if value != e.ObjectOld.GetAnnotations()[key] {
+						return true
+					}
 				}
 			}
 			return e.ObjectNew.GetGeneration() == 0 || e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
@@ -64,12 +64,15 @@ const (
 // OpenSearchServiceReconciler reconciles a OpenSearchService object
 type OpenSearchServiceReconciler struct {
 	client.Client
-	Scheme                *runtime.Scheme
-	ResourceHashes        map[string]string
-	ReplicationWatcher    ReplicationWatcher
-	SlowLogIndicesWatcher SlowLogIndicesWatcher
-	IndexSettingsWatcher  IndexSettingsWatcher
-	StatusUpdater         util.StatusUpdater
+	Scheme                   *runtime.Scheme
+	ResourceHashes           map[string]string
+	ReplicationWatcher       ReplicationWatcher
+	SlowLogIndicesWatcher    SlowLogIndicesWatcher
+	IndexSettingsWatcher     IndexSettingsWatcher
+	ConfigurationSyncWatcher ConfigurationSyncWatcher
+	ConsistencyWatcher       ConsistencyWatcher
+	SnapshotShippingWatcher  SnapshotShippingWatcher
+	StatusUpdater            util.StatusUpdater
 }
 
 // findSecret returns the secret found by name and namespace and error if it occurred
@@ -19,7 +19,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 
@@ -31,6 +30,9 @@ const (
 	leaderAlias                    = "leader-cluster"
 	startFullReplicationPath       = "_plugins/_replication/_autofollow"
 	indexReplicationStatusPattern  = "_plugins/_replication/%s/_status"
+	resumeIndexReplicationPattern  = "_plugins/_replication/%s/_resume"
+	pauseIndexReplicationPattern   = "_plugins/_replication/%s/_pause"
+	startIndexReplicationPattern   = "_plugins/_replication/%s/_start"
 	replicationName                = "dr-replication"
 	replicationNotInProgressStatus = "REPLICATION NOT IN PROGRESS"
 	replicationAttemptsNumber      = 5
@@ -40,7 +42,7 @@ const (
 type ReplicationManager struct {
 	restClient util.RestClient
 	remoteUrl  string
-	pattern    string
+	rules      []ReplicationRule
 	logger     logr.Logger
 }
 
@@ -159,11 +161,11 @@ type PluginReplicationError struct {
 	Status int `json:"status"`
 }
 
-func NewReplicationManager(restClient util.RestClient, remoteUrl string, indexPattern string, logger logr.Logger) *ReplicationManager {
+func NewReplicationManager(restClient util.RestClient, remoteUrl string, rules []ReplicationRule, logger logr.Logger) *ReplicationManager {
 	return &ReplicationManager{
 		restClient: restClient,
 		remoteUrl:  remoteUrl,
-		pattern:    indexPattern,
+		rules:      rules,
 		logger:     logger,
 	}
 }
@@ -194,18 +196,31 @@ func (rm ReplicationManager) Configure() error {
 	return nil
 }
 
+// Start creates autofollow replication rules for all include patterns of configured replication rules
 func (rm ReplicationManager) Start() error {
+	for _, rule := range rm.rules {
+		for _, autofollow := range rule.autofollowRules() {
+			rm.logger.Info(fmt.Sprintf("Create autofollow replication rule [%s] for [%s] indices", autofollow.name, autofollow.pattern))
+			if err := rm.startRule(autofollow, rule.roles()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (rm ReplicationManager) startRule(autofollow autofollowRule, roles UseRoles) error {
 	body := fmt.Sprintf(`
 {
   "leader_alias": "%s",
   "pattern": "%s",
   "name": "%s",
   "use_roles": {
-    "leader_cluster_role": "all_access",
-	"follower_cluster_role": "all_access"
+    "leader_cluster_role": "%s",
+	"follower_cluster_role": "%s"
   }
 }
-`, leaderAlias, rm.pattern, replicationName)
+`, leaderAlias, autofollow.pattern, autofollow.name, roles.LeaderClusterRole, roles.FollowerClusterRole)
 	statusCode, respBody, err := rm.restClient.SendRequest(http.MethodPost, startFullReplicationPath, strings.NewReader(body))
 	if err != nil {
 		return err
@@ -237,24 +252,74 @@ func (rm ReplicationManager) Start() error {
 	return fmt.Errorf("error occurred during start dr replication - [%v]", replicationErrorData)
 }
 
-func (rm ReplicationManager) RemoveReplicationRule() error {
-	rule, err := rm.GetAutoFollowRuleStats()
+// RemoveReplicationRules removes all autofollow replication rules created for Disaster Recovery
+// including the rules which are not present in the configuration anymore
+func (rm ReplicationManager) RemoveReplicationRules() error {
+	stats, err := rm.getAutofollowStats()
 	if err != nil {
-		return fmt.Errorf("failed to get replication rule: %w", err)
+		return fmt.Errorf("failed to get replication rules: %w", err)
 	}
-	if rule == nil {
+	removed := false
+	for _, rule := range stats.AutofollowRuleStats {
+		if !isDisasterRecoveryRule(rule.Name) {
+			continue
+		}
+		body := fmt.Sprintf(`{"leader_alias": "%s","name": "%s"}`, leaderAlias, rule.Name)
+		statusCode, _, err := rm.restClient.SendRequest(http.MethodDelete, startFullReplicationPath, strings.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if statusCode >= 400 && statusCode != http.StatusNotFound {
+			return fmt.Errorf("internal server error with %d status code", statusCode)
+		}
+		rm.logger.Info(fmt.Sprintf("Autofollow replication rule [%s] was removed", rule.Name))
+		removed = true
+	}
+	if !removed {
 		rm.logger.Info("Skipping replication rule removal since its does not exist")
-		return nil
 	}
-	body := fmt.Sprintf(`{"leader_alias": "%s","name": "%s"}`, leaderAlias, replicationName)
-	statusCode, _, err := rm.restClient.SendRequest(http.MethodDelete, startFullReplicationPath, strings.NewReader(body))
+	return nil
+}
+
+// isDisasterRecoveryRule checks whether autofollow rule with the given name is managed by the operator
+func isDisasterRecoveryRule(name string) bool {
+	return name == replicationName || strings.HasPrefix(name, replicationName+"-")
+}
+
+// matches checks whether the index is replicated by any of replication rules
+func (rm ReplicationManager) matches(index string) bool {
+	_, found := rm.ruleFor(index)
+	return found
+}
+
+// ruleFor returns the first replication rule the index is replicated by
+func (rm ReplicationManager) ruleFor(index string) (ReplicationRule, bool) {
+	for _, rule := range rm.rules {
+		if rule.matches(index) {
+			return rule, true
+		}
+	}
+	return ReplicationRule{}, false
+}
+
+// StopExcludedIndicesReplication stops replication of indices started by autofollow rules,
+// but matching exclude patterns of replication rules
+func (rm ReplicationManager) StopExcludedIndicesReplication() error {
+	indexNames, err := rm.getReplicatedIndices()
 	if err != nil {
 		return err
 	}
-	if statusCode >= 400 && statusCode != http.StatusNotFound {
-		return fmt.Errorf("internal server error with %d status code", statusCode)
+	var excluded []string
+	for _, index := range indexNames {
+		if !strings.HasPrefix(index, ".") && !rm.matches(index) {
+			excluded = append(excluded, index)
+		}
 	}
-	return nil
+	if len(excluded) == 0 {
+		return nil
+	}
+	rm.logger.Info(fmt.Sprintf("Stop replication of excluded indices %v", excluded))
+	return rm.stopIndicesReplication(excluded)
 }
 
 func (rm ReplicationManager) StopReplication() error {
@@ -293,34 +358,19 @@ func (rm ReplicationManager) getReplicatedIndices() ([]string, error) {
 
 func (rm ReplicationManager) executeReplicationCheck(indexNames []string) error {
 	//TODO: should we execute replication health check here?
+	progress, failedIndices, err := rm.getReplicationProgress(indexNames)
+	if err != nil {
+		return err
+	}
 	inProgressIndices := make(map[string]int)
-	var failedIndices []string
-	pattern := strings.ReplaceAll(rm.pattern, "*", ".*")
-	for _, index := range indexNames {
-		matched, err := regexp.MatchString(pattern, index)
-		if err != nil {
-			rm.logger.Error(err, fmt.Sprintf("Regular expression - [%s] is invalid", rm.pattern))
-			return err
-		}
-		if !matched {
-			continue
-		}
-		replicationIndexStats, err := rm.getIndexReplicationStatus(index)
-		if err != nil {
-			rm.logger.Error(err, fmt.Sprintf("Can not get replication index stats for [%s] index", index))
-			return err
-		}
-		if replicationIndexStats.Status == "SYNCING" || replicationIndexStats.Status == "BOOTSTRAPPING" {
-			if replicationIndexStats.Details.LeaderCheckpoint != replicationIndexStats.Details.FollowerCheckpoint {
-				inProgressIndices[index] = replicationIndexStats.Details.LeaderCheckpoint
-			}
-		} else {
-			failedIndices = append(failedIndices, index)
+	for index, details := range progress {
+		if details.LeaderCheckpoint != details.FollowerCheckpoint {
+			inProgressIndices[index] = details.LeaderCheckpoint
 		}
 	}
 
 	if len(failedIndices) > 0 {
-		err := fmt.Errorf("some replication indices are failed")
+		err = fmt.Errorf("some replication indices are failed")
 		rm.logger.Error(err, fmt.Sprintf("Replication check is failed because there are failed replication indices: [%v]", failedIndices))
 		return err
 	}
@@ -343,6 +393,44 @@ func (rm ReplicationManager) executeReplicationCheck(indexNames []string) error
 	return fmt.Errorf("replication check was failed after 5 attempts")
 }
 
+// getReplicationProgress returns syncing details of replicated indices matching replication rules
+// and the list of indices with replication in other states
+func (rm ReplicationManager) getReplicationProgress(indexNames []string) (map[string]IndexDetails, []string, error) {
+	progress := make(map[string]IndexDetails)
+	var failedIndices []string
+	for _, index := range indexNames {
+		if !rm.matches(index) {
+			continue
+		}
+		replicationIndexStats, err := rm.getIndexReplicationStatus(index)
+		if err != nil {
+			return nil, nil, err
+		}
+		if replicationIndexStats.Status == "SYNCING" || replicationIndexStats.Status == "BOOTSTRAPPING" {
+			progress[index] = replicationIndexStats.Details
+		} else {
+			failedIndices = append(failedIndices, index)
+		}
+	}
+	return progress, failedIndices, nil
+}
+
+// getDocumentsCount returns the number of documents in the index. Use `leader-cluster:` prefix
+// to get the number of documents in the leader index.
+func (rm ReplicationManager) getDocumentsCount(index string) (int64, error) {
+	body, err := rm.restClient.SendRequestWithStatusCodeCheck(http.MethodGet, fmt.Sprintf("%s/_count", index), nil)
+	if err != nil {
+		return 0, err
+	}
+	var response struct {
+		Count int64 `json:"count"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return 0, err
+	}
+	return response.Count, nil
+}
+
 func (rm ReplicationManager) getIndexReplicationStatus(index string) (ReplicationIndexStats, error) {
 	path := fmt.Sprintf(indexReplicationStatusPattern, index)
 	replicationIndexStats, err := rm.getReplicationIndexStats(path)
@@ -406,29 +494,138 @@ func (rm ReplicationManager) stopIndicesReplication(indexNames []string) error {
 	return nil
 }
 
-func (rm ReplicationManager) DeleteIndices() error {
-	if rm.pattern != "*" {
-		if err := rm.DeleteIndicesByPatternWithUnlock(rm.pattern); err != nil {
+// ResumeIndexReplication resumes paused replication of the given follower index
+func (rm ReplicationManager) ResumeIndexReplication(index string) error {
+	statusCode, responseBody, err := rm.restClient.SendRequest(http.MethodPost,
+		fmt.Sprintf(resumeIndexReplicationPattern, index), strings.NewReader(`{}`))
+	if err != nil {
+		return err
+	}
+	if statusCode >= 400 {
+		return fmt.Errorf("can not resume replication for [%s] index with status code - [%d], response - [%s]",
+			index, statusCode, string(responseBody))
+	}
+	rm.logger.Info(fmt.Sprintf("Replication was resumed for index [%s]", index))
+	return nil
+}
+
+// PauseIndexReplication pauses replication of the given follower index keeping its data
+func (rm ReplicationManager) PauseIndexReplication(index string) error {
+	statusCode, responseBody, err := rm.restClient.SendRequest(http.MethodPost,
+		fmt.Sprintf(pauseIndexReplicationPattern, index), strings.NewReader(`{}`))
+	if err != nil {
+		return err
+	}
+	if statusCode >= 400 {
+		return fmt.Errorf("can not pause replication for [%s] index with status code - [%d], response - [%s]",
+			index, statusCode, string(responseBody))
+	}
+	rm.logger.Info(fmt.Sprintf("Replication was paused for index [%s]", index))
+	return nil
+}
+
+// StartIndexReplication starts replication of the given index from the leader cluster
+// with roles of the replication rule the index matches
+func (rm ReplicationManager) StartIndexReplication(index string) error {
+	rule, _ := rm.ruleFor(index)
+	roles := rule.roles()
+	body := fmt.Sprintf(`
+{
+  "leader_alias": "%s",
+  "leader_index": "%s",
+  "use_roles": {
+    "leader_cluster_role": "%s",
+	"follower_cluster_role": "%s"
+  }
+}
+`, leaderAlias, index, roles.LeaderClusterRole, roles.FollowerClusterRole)
+	statusCode, responseBody, err := rm.restClient.SendRequest(http.MethodPut,
+		fmt.Sprintf(startIndexReplicationPattern, index), strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if statusCode >= 400 {
+		return fmt.Errorf("can not start replication for [%s] index with status code - [%d], response - [%s]",
+			index, statusCode, string(responseBody))
+	}
+	rm.logger.Info(fmt.Sprintf("Replication was started for index [%s]", index))
+	return nil
+}
+
+// RefollowIndex stops replication of the given follower index, removes it and starts its replication from scratch
+func (rm ReplicationManager) RefollowIndex(index string) error {
+	replicationStatus, err := rm.getIndexReplicationStatus(index)
+	if err != nil {
+		return err
+	}
+	if replicationStatus.Status != replicationNotInProgressStatus {
+		if err = rm.stopIndicesReplication([]string{index}); err != nil {
 			return err
 		}
-		return nil
 	}
-
-	indices, err := rm.restClient.GetArrayData("_cat/indices?h=index", "index", func(s string) bool {
-		return !strings.HasPrefix(s, ".")
-	})
+	statusCode, _, err := rm.restClient.SendRequest(http.MethodDelete, index, nil)
 	if err != nil {
 		return err
 	}
+	if statusCode >= 400 && statusCode != http.StatusNotFound {
+		return fmt.Errorf("can not delete [%s] index with status code - [%d]", index, statusCode)
+	}
+	return rm.StartIndexReplication(index)
+}
 
-	for _, index := range indices {
-		if err = rm.DeleteIndicesByPatternWithUnlock(index); err != nil {
+func (rm ReplicationManager) DeleteIndices() error {
+	for _, rule := range rm.rules {
+		if !rule.includesAll() {
+			if err := rm.DeleteIndicesByPatternWithUnlock(rule.indexExpression()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		indices, err := rm.GetIndicesByPatternExcludeService(rule.indexExpression())
+		if err != nil {
 			return err
 		}
+
+		for _, index := range indices {
+			if err = rm.DeleteIndicesByPatternWithUnlock(index); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+// StopRulesIndicesReplication stops replication for all indices matching replication rules
+func (rm ReplicationManager) StopRulesIndicesReplication() error {
+	for _, rule := range rm.rules {
+		rm.logger.Info(fmt.Sprintf("Try to stop running replication for all indices match replication pattern [%s].", rule.indexExpression()))
+		if err := rm.StopIndicesReplicationByPattern(rule.indexExpression()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRulesIndices returns non-service indices matching replication rules
+func (rm ReplicationManager) GetRulesIndices() ([]string, error) {
+	var result []string
+	found := make(map[string]bool)
+	for _, rule := range rm.rules {
+		indices, err := rm.GetIndicesByPatternExcludeService(rule.indexExpression())
+		if err != nil {
+			return nil, err
+		}
+		for _, index := range indices {
+			if !found[index] {
+				found[index] = true
+				result = append(result, index)
+			}
+		}
+	}
+	return result, nil
+}
+
 func (rm ReplicationManager) StopIndicesReplicationByPattern(pattern string) error {
 	path := fmt.Sprintf("_cat/indices/%s?h=index", pattern)
 	indices, err := rm.restClient.GetArrayData(path, "index", func(index string) bool {
@@ -529,23 +726,48 @@ func (rm ReplicationManager) DeleteAdminReplicationTasks() error {
 	return nil
 }
 
-func (rm ReplicationManager) GetAutoFollowRuleStats() (*RuleStats, error) {
-	_, body, err := rm.restClient.SendRequest(http.MethodGet, "_plugins/_replication/autofollow_stats", nil)
+// GetAutoFollowRulesStats returns statistics of configured autofollow replication rules.
+// Returned list is nil without error if any of configured rules does not exist.
+func (rm ReplicationManager) GetAutoFollowRulesStats() ([]RuleStats, error) {
+	stats, err := rm.getAutofollowStats()
 	if err != nil {
-		rm.logger.Error(err, "unable to read autofollow statistic")
 		return nil, err
 	}
+	rulesStats := make([]RuleStats, 0, len(rm.rules))
+	for _, rule := range rm.rules {
+		for _, autofollow := range rule.autofollowRules() {
+			found := false
+			for _, ruleStats := range stats.AutofollowRuleStats {
+				if ruleStats.Name == autofollow.name {
+					rulesStats = append(rulesStats, ruleStats)
+					found = true
+					break
+				}
+			}
+			if !found {
+				rm.logger.Info(fmt.Sprintf("Unable to find existing DR replication rule [%s]", autofollow.name))
+				return nil, nil
+			}
+		}
+	}
+	return rulesStats, nil
+}
+
+func (rm ReplicationManager) getAutofollowStats() (AutofollowStats, error) {
 	var stats AutofollowStats
+	statusCode, body, err := rm.restClient.SendRequest(http.MethodGet, "_plugins/_replication/autofollow_stats", nil)
+	if err != nil {
+		rm.logger.Error(err, "unable to read autofollow statistic")
+		return stats, err
+	}
+	if statusCode >= 400 {
+		return stats, fmt.Errorf("unable to read autofollow statistic with status code - [%d], response - [%s]",
+			statusCode, string(body))
+	}
 	err = json.Unmarshal(body, &stats)
 	if err != nil {
 		rm.logger.Error(err, "unable to unmarshal autofollow statistic")
-		return nil, err
-	}
-	for _, rule := range stats.AutofollowRuleStats {
-		if rule.Name == replicationName {
-			return &rule, nil
-		}
+		return stats, err
 	}
-	rm.logger.Info("Unable to find existing DR replication rule")
-	return nil, nil
+	return stats, nil
 }
@@ -0,0 +1,135 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+)
+
+// recordingServer returns the server which responds with the given bodies by path and records requests to it
+func recordingServer(responses map[string]string) (*httptest.Server, func() []map[string]interface{}, func() []string) {
+	var lock sync.Mutex
+	var bodies []map[string]interface{}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method != http.MethodGet {
+			var body map[string]interface{}
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &body)
+			bodies = append(bodies, body)
+		}
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			response = `{"acknowledged":true}`
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	return server, func() []map[string]interface{} {
+			lock.Lock()
+			defer lock.Unlock()
+			return bodies
+		}, func() []string {
+			lock.Lock()
+			defer lock.Unlock()
+			return requests
+		}
+}
+
+func newTestReplicationManager(server *httptest.Server, rules []ReplicationRule) ReplicationManager {
+	restClient := util.NewRestClient(server.URL, http.Client{}, util.Credentials{})
+	return *NewReplicationManager(*restClient, "", rules, logr.Discard())
+}
+
+var testReplicationRules = []ReplicationRule{
+	{
+		Name:            "orders",
+		Patterns:        []string{"orders-*", "payments-*"},
+		ExcludePatterns: []string{"orders-tmp"},
+		UseRoles:        &UseRoles{LeaderClusterRole: "leader_role", FollowerClusterRole: "follower_role"},
+	},
+	{Patterns: []string{"users"}},
+}
+
+func TestStart_CreatesAutofollowRulePerPattern(t *testing.T) {
+	server, bodies, _ := recordingServer(nil)
+	defer server.Close()
+
+	if err := newTestReplicationManager(server, testReplicationRules).Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"dr-replication-orders-1": "orders-*",
+		"dr-replication-orders-2": "payments-*",
+		"dr-replication":          "users",
+	}
+	if len(bodies()) != len(expected) {
+		t.Fatalf("expected %d autofollow rules, got %v", len(expected), bodies())
+	}
+	for _, body := range bodies() {
+		name, _ := body["name"].(string)
+		if pattern, ok := expected[name]; !ok || body["pattern"] != pattern {
+			t.Errorf("unexpected autofollow rule: %v", body)
+		}
+	}
+}
+
+func TestStartIndexReplication_UsesRuleRoles(t *testing.T) {
+	server, bodies, _ := recordingServer(nil)
+	defer server.Close()
+	replicationManager := newTestReplicationManager(server, testReplicationRules)
+
+	if err := replicationManager.StartIndexReplication("payments-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := replicationManager.StartIndexReplication("users"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	roles := bodies()[0]["use_roles"].(map[string]interface{})
+	if roles["leader_cluster_role"] != "leader_role" || roles["follower_cluster_role"] != "follower_role" {
+		t.Errorf("expected roles of the rule to be used, got %v", roles)
+	}
+	roles = bodies()[1]["use_roles"].(map[string]interface{})
+	if roles["leader_cluster_role"] != defaultClusterRole || roles["follower_cluster_role"] != defaultClusterRole {
+		t.Errorf("expected default roles to be used, got %v", roles)
+	}
+}
+
+func TestStopExcludedIndicesReplication(t *testing.T) {
+	server, _, requests := recordingServer(map[string]string{
+		"/_plugins/_replication/follower_stats": `{"index_stats":{"orders-1":{},"orders-tmp":{},".service":{}}}`,
+	})
+	defer server.Close()
+
+	if err := newTestReplicationManager(server, testReplicationRules).StopExcludedIndicesReplication(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stopped := requests()[1:]
+	if len(stopped) != 1 || stopped[0] != "POST /_plugins/_replication/orders-tmp/_stop" {
+		t.Errorf("expected replication of the only excluded index to be stopped, got %v", stopped)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+)
+
+const drReplicationPauseHashName = "spec.disasterRecovery.replicationPause"
+
+// isPausedOnRequest checks whether replication of the index is paused on purpose by the replication pause parameters
+func isPausedOnRequest(pause *opensearchservice.ReplicationPause, index string) bool {
+	return pause != nil && pause.Enabled && (pause.Pattern == "" || matchPattern(pause.Pattern, index))
+}
+
+// reconcileReplicationPause pauses replication of requested follower indices on the standby side
+// and resumes replication of indices paused before when the pause is disabled
+func (r DisasterRecoveryReconciler) reconcileReplicationPause() {
+	pause := r.cr.Spec.DisasterRecovery.ReplicationPause
+	status := r.cr.Status.DisasterRecoveryStatus.ReplicationPause
+	if r.cr.Spec.DisasterRecovery.Mode != "standby" || r.isSnapshotShippingEnabled() {
+		// Replication is stopped in other modes, so there is nothing to resume
+		delete(r.reconciler.ResourceHashes, drReplicationPauseHashName)
+		if status != nil {
+			r.updateReplicationPauseStatus(nil)
+		}
+		return
+	}
+	if (pause == nil || !pause.Enabled) && (status == nil || len(status.Indices) == 0) {
+		delete(r.reconciler.ResourceHashes, drReplicationPauseHashName)
+		return
+	}
+	pauseHash, err := util.Hash(pause)
+	if err != nil {
+		r.logger.Error(err, "Unable to calculate hash of replication pause parameters")
+		return
+	}
+	if r.reconciler.ResourceHashes[drReplicationPauseHashName] == pauseHash {
+		return
+	}
+	replicationManager, err := r.getReplicationManager()
+	if err != nil {
+		r.logger.Error(err, "Unable to pause replication")
+		return
+	}
+	var previouslyPaused []string
+	if status != nil {
+		previouslyPaused = status.Indices
+	}
+	paused, err := applyReplicationPause(replicationManager, pause, previouslyPaused)
+	newStatus := &opensearchservice.ReplicationPauseStatus{
+		Indices: paused,
+		Time:    time.Now().UTC().Format(time.RFC3339),
+	}
+	if err != nil {
+		r.logger.Error(err, "Unable to pause or resume replication of some indices")
+		newStatus.Error = err.Error()
+	} else {
+		r.reconciler.ResourceHashes[drReplicationPauseHashName] = pauseHash
+		if len(paused) == 0 && (pause == nil || !pause.Enabled) {
+			newStatus = nil
+		}
+	}
+	r.updateReplicationPauseStatus(newStatus)
+}
+
+// applyReplicationPause pauses replication of indices matching the pause parameters and resumes previously paused
+// indices which do not match them anymore. It returns indices which replication is paused by the operator.
+func applyReplicationPause(replicationManager ReplicationManager, pause *opensearchservice.ReplicationPause,
+	previouslyPaused []string) ([]string, error) {
+	var errs []error
+	paused := make([]string, 0)
+	for _, index := range previouslyPaused {
+		if isPausedOnRequest(pause, index) {
+			continue
+		}
+		replicationStatus, err := replicationManager.getIndexReplicationStatus(index)
+		if err != nil {
+			errs = append(errs, err)
+			paused = append(paused, index)
+			continue
+		}
+		if replicationStatus.Status != replicationPausedStatus {
+			continue
+		}
+		if err = replicationManager.ResumeIndexReplication(index); err != nil {
+			errs = append(errs, err)
+			paused = append(paused, index)
+		}
+	}
+	if pause != nil && pause.Enabled {
+		indices, err := replicationManager.GetRulesIndices()
+		if err != nil {
+			return previouslyPaused, fmt.Errorf("unable to get replicated indices: %w", err)
+		}
+		for _, index := range indices {
+			if !isPausedOnRequest(pause, index) {
+				continue
+			}
+			replicationStatus, err := replicationManager.getIndexReplicationStatus(index)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			switch replicationStatus.Status {
+			case replicationPausedStatus:
+				paused = append(paused, index)
+			case replicationNotInProgressStatus, failedStatus:
+				replicationManager.logger.Info(fmt.Sprintf("Replication of [%s] index is %s, it is not paused",
+					index, replicationStatus.Status))
+			default:
+				if err = replicationManager.PauseIndexReplication(index); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				paused = append(paused, index)
+			}
+		}
+	}
+	sort.Strings(paused)
+	return paused, errors.Join(errs...)
+}
+
+func (r DisasterRecoveryReconciler) updateReplicationPauseStatus(status *opensearchservice.ReplicationPauseStatus) {
+	statusUpdater := util.NewStatusUpdater(r.reconciler.Client, r.cr)
+	err := statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		instance.Status.DisasterRecoveryStatus.ReplicationPause = status
+	})
+	if err != nil {
+		r.logger.Error(err, "Unable to update replication pause status")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+)
+
+// newPauseTestReplicationManager returns replication manager for the server with given index statuses
+// which records paused and resumed indices
+func newPauseTestReplicationManager(t *testing.T, statuses map[string]string) (ReplicationManager, *[]string) {
+	var lock sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_plugins/_replication/autofollow_stats" {
+			_, _ = w.Write([]byte(`{"autofollow_stats":[{"name":"dr-replication","pattern":"*","failed_indices":[]}]}`))
+			return
+		}
+		if r.URL.Path == "/_cat/indices/*" {
+			_, _ = w.Write([]byte(`[{"index":"logs-1"},{"index":"logs-2"},{"index":"orders"},{"index":"failed"}]`))
+			return
+		}
+		if r.Method == http.MethodPost {
+			lock.Lock()
+			requests = append(requests, r.URL.Path)
+			lock.Unlock()
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+			return
+		}
+		for index, status := range statuses {
+			if r.URL.Path == "/_plugins/_replication/"+index+"/_status" {
+				_, _ = w.Write([]byte(`{"status":"` + status + `"}`))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	restClient := util.NewRestClient(server.URL, http.Client{}, util.Credentials{})
+	return *NewReplicationManager(*restClient, "", []ReplicationRule{{Patterns: []string{"*"}}}, logr.Discard()), &requests
+}
+
+func TestApplyReplicationPause_PausesMatchingIndices(t *testing.T) {
+	replicationManager, requests := newPauseTestReplicationManager(t, map[string]string{
+		"logs-1": "SYNCING", "logs-2": replicationPausedStatus, "orders": "SYNCING", "failed": failedStatus,
+	})
+	paused, err := applyReplicationPause(replicationManager,
+		&opensearchservice.ReplicationPause{Enabled: true, Pattern: "logs-*"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(paused, []string{"logs-1", "logs-2"}) {
+		t.Errorf("unexpected paused indices: %v", paused)
+	}
+	if !reflect.DeepEqual(*requests, []string{"/_plugins/_replication/logs-1/_pause"}) {
+		t.Errorf("unexpected requests: %v", *requests)
+	}
+}
+
+func TestApplyReplicationPause_ResumesPreviouslyPausedIndices(t *testing.T) {
+	replicationManager, requests := newPauseTestReplicationManager(t, map[string]string{
+		"logs-1": replicationPausedStatus, "logs-2": "SYNCING",
+	})
+	paused, err := applyReplicationPause(replicationManager, &opensearchservice.ReplicationPause{Enabled: false},
+		[]string{"logs-1", "logs-2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paused) != 0 {
+		t.Errorf("expected no paused indices, got %v", paused)
+	}
+	if !reflect.DeepEqual(*requests, []string{"/_plugins/_replication/logs-1/_resume"}) {
+		t.Errorf("unexpected requests: %v", *requests)
+	}
+}
+
+func TestGetFailedReplications_SkipsIndicesPausedOnRequest(t *testing.T) {
+	replicationManager, _ := newPauseTestReplicationManager(t, map[string]string{
+		"logs-1": replicationPausedStatus, "logs-2": "SYNCING", "orders": replicationPausedStatus, "failed": failedStatus,
+	})
+	var mu sync.Mutex
+	rw := NewReplicationWatcher(&mu, nil)
+	_, failedReplications, _ := rw.getFailedReplications(replicationManager,
+		&opensearchservice.ReplicationPause{Enabled: true, Pattern: "logs-*"}, logr.Discard())
+
+	expected := map[string]string{"orders": replicationPausedStatus, "failed": failedStatus}
+	if !reflect.DeepEqual(failedReplications, expected) {
+		t.Errorf("expected %v failed replications, got %v", expected, failedReplications)
+	}
+}
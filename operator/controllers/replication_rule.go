@@ -0,0 +1,154 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	replicationRulesKey = "replicationRules"
+	defaultClusterRole  = "all_access"
+)
+
+// ReplicationRule describes autofollow replication rule from Disaster Recovery configuration.
+// Indices matching any of Patterns and not matching any of ExcludePatterns are replicated.
+type ReplicationRule struct {
+	Name            string    `yaml:"name"`
+	Patterns        []string  `yaml:"patterns"`
+	ExcludePatterns []string  `yaml:"excludePatterns"`
+	UseRoles        *UseRoles `yaml:"useRoles"`
+}
+
+// autofollowRule describes autofollow rule created in OpenSearch for one of include patterns of the replication rule,
+// because autofollow rules do not support multi-target expressions
+type autofollowRule struct {
+	name    string
+	pattern string
+}
+
+// UseRoles describes roles the replication is run with on leader and follower clusters
+type UseRoles struct {
+	LeaderClusterRole   string `yaml:"leaderClusterRole"`
+	FollowerClusterRole string `yaml:"followerClusterRole"`
+}
+
+// parseReplicationRules reads replication rules from Disaster Recovery ConfigMap data.
+// If there are no rules, the only rule is built from legacy `indicesPattern` value.
+func parseReplicationRules(data map[string]string) ([]ReplicationRule, error) {
+	rulesData := strings.TrimSpace(data[replicationRulesKey])
+	if rulesData == "" || rulesData == "[]" || rulesData == "null" {
+		return []ReplicationRule{{Patterns: []string{data[replicationPatternKey]}}}, nil
+	}
+	var rules []ReplicationRule
+	if err := yaml.Unmarshal([]byte(rulesData), &rules); err != nil {
+		return nil, fmt.Errorf("unable to parse replication rules: %w", err)
+	}
+	names := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if len(rule.Patterns) == 0 {
+			return nil, fmt.Errorf("replication rule [%s] must have at least one pattern", rule.Name)
+		}
+		for _, autofollow := range rule.autofollowRules() {
+			if names[autofollow.name] {
+				return nil, fmt.Errorf("replication rule name [%s] is not unique", rule.Name)
+			}
+			names[autofollow.name] = true
+		}
+	}
+	return rules, nil
+}
+
+// ruleName returns the name of autofollow rule in OpenSearch. Unnamed rule keeps the legacy `dr-replication` name.
+func (rule ReplicationRule) ruleName() string {
+	if rule.Name == "" {
+		return replicationName
+	}
+	return fmt.Sprintf("%s-%s", replicationName, rule.Name)
+}
+
+// autofollowRules returns autofollow rules for include patterns of the rule. The rule with the only pattern keeps its name,
+// otherwise the number of the pattern is added to the name. Exclude patterns are applied by the operator.
+func (rule ReplicationRule) autofollowRules() []autofollowRule {
+	if len(rule.Patterns) == 1 {
+		return []autofollowRule{{name: rule.ruleName(), pattern: rule.Patterns[0]}}
+	}
+	autofollowRules := make([]autofollowRule, 0, len(rule.Patterns))
+	for i, pattern := range rule.Patterns {
+		autofollowRules = append(autofollowRules, autofollowRule{name: fmt.Sprintf("%s-%d", rule.ruleName(), i+1), pattern: pattern})
+	}
+	return autofollowRules
+}
+
+// indexExpression returns OpenSearch multi-target expression with include and exclude patterns of the rule
+func (rule ReplicationRule) indexExpression() string {
+	expressions := make([]string, 0, len(rule.Patterns)+len(rule.ExcludePatterns))
+	expressions = append(expressions, rule.Patterns...)
+	for _, pattern := range rule.ExcludePatterns {
+		expressions = append(expressions, "-"+pattern)
+	}
+	return strings.Join(expressions, ",")
+}
+
+// includesAll checks whether the rule replicates all indices
+func (rule ReplicationRule) includesAll() bool {
+	for _, pattern := range rule.Patterns {
+		if pattern == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule ReplicationRule) matches(index string) bool {
+	for _, pattern := range rule.ExcludePatterns {
+		if matchPattern(pattern, index) {
+			return false
+		}
+	}
+	for _, pattern := range rule.Patterns {
+		if matchPattern(pattern, index) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule ReplicationRule) roles() UseRoles {
+	roles := UseRoles{LeaderClusterRole: defaultClusterRole, FollowerClusterRole: defaultClusterRole}
+	if rule.UseRoles != nil {
+		if rule.UseRoles.LeaderClusterRole != "" {
+			roles.LeaderClusterRole = rule.UseRoles.LeaderClusterRole
+		}
+		if rule.UseRoles.FollowerClusterRole != "" {
+			roles.FollowerClusterRole = rule.UseRoles.FollowerClusterRole
+		}
+	}
+	return roles
+}
+
+// matchPattern checks that the index name matches wildcard pattern
+func matchPattern(pattern string, index string) bool {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	matched, _ := regexp.MatchString(fmt.Sprintf("^%s$", strings.Join(parts, ".*")), index)
+	return matched
+}
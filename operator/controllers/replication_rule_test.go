@@ -0,0 +1,83 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"testing"
+)
+
+func TestParseReplicationRules_LegacyPattern(t *testing.T) {
+	rules, err := parseReplicationRules(map[string]string{replicationPatternKey: "test*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 1 || rules[0].ruleName() != replicationName || rules[0].indexExpression() != "test*" {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+	roles := rules[0].roles()
+	if roles.LeaderClusterRole != defaultClusterRole || roles.FollowerClusterRole != defaultClusterRole {
+		t.Errorf("unexpected default roles: %+v", roles)
+	}
+}
+
+func TestParseReplicationRules_MultipleRules(t *testing.T) {
+	data := map[string]string{
+		replicationPatternKey: "*",
+		replicationRulesKey: `
+- patterns: ["*"]
+  excludePatterns: ["scratch-*", "tmp-*"]
+- name: logs
+  patterns: ["logs-*"]
+  useRoles:
+    leaderClusterRole: leader_role
+    followerClusterRole: follower_role
+`,
+	}
+	rules, err := parseReplicationRules(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected two rules, got %+v", rules)
+	}
+	if expression := rules[0].indexExpression(); expression != "*,-scratch-*,-tmp-*" {
+		t.Errorf("unexpected index expression: %s", expression)
+	}
+	if name := rules[1].ruleName(); name != "dr-replication-logs" {
+		t.Errorf("unexpected rule name: %s", name)
+	}
+	if roles := rules[1].roles(); roles.LeaderClusterRole != "leader_role" || roles.FollowerClusterRole != "follower_role" {
+		t.Errorf("unexpected roles: %+v", roles)
+	}
+	if !rules[0].matches("orders") || rules[0].matches("scratch-1") || rules[1].matches("orders") {
+		t.Error("rules match unexpected indices")
+	}
+}
+
+func TestParseReplicationRules_InvalidRules(t *testing.T) {
+	invalid := []string{
+		`[{"name": "empty"}]`,
+		`[{"patterns": ["a*"]}, {"patterns": ["b*"]}]`,
+		`[{"name": "logs", "patterns": ["a*", "b*"]}, {"name": "logs-1", "patterns": ["c*"]}]`,
+		`not a list`,
+	}
+	for _, rulesData := range invalid {
+		if _, err := parseReplicationRules(map[string]string{replicationRulesKey: rulesData}); err == nil {
+			t.Errorf("expected error for %s", rulesData)
+		}
+	}
+}
@@ -18,156 +18,412 @@ import (
 	"context"
 	"fmt"
 	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/disasterrecovery"
 	"github.com/Netcracker/qubership-opensearch/operator/util"
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
 const (
-	failedStatus         = "FAILED"
-	runningState         = "running"
-	pausedState          = "paused"
-	defaultWatchInterval = 30
-	restartWaitPeriod    = 60
+	failedStatus                      = "FAILED"
+	replicationPausedStatus           = "PAUSED"
+	defaultWatchInterval              = 30
+	restartWaitPeriod                 = 60
+	maxIndexRestartBackoff            = 30 * time.Minute
+	defaultMaxReplicationRestarts     = 5
+	defaultReplicationRestartWindow   = time.Hour
+	maxRestartJitterFraction          = 0.2
+	resumeAction                      = "resume"
+	refollowAction                    = "refollow"
+	restartAction                     = "restart"
+	replicationWatcherConditionReason = "ReplicationWatcherStatus"
 )
 
+// ReplicationWatcher periodically checks replication on the standby side and repairs it.
+// The watcher is stopped by context cancellation when the side is switched or the watcher is disabled.
 type ReplicationWatcher struct {
-	Lock  *sync.Mutex
-	state *string
+	Lock          *sync.Mutex
+	Info          *disasterrecovery.WatcherInfo
+	cancel        *context.CancelFunc
+	indexRestarts map[string]*indexRestartState
 }
 
-func NewReplicationWatcher(lock *sync.Mutex) ReplicationWatcher {
-	state := pausedState
+// indexRestartState keeps repair attempts of the follower index replication
+type indexRestartState struct {
+	attempts    int
+	nextAttempt time.Time
+}
+
+// replicationRestarts keeps restarts of the whole replication performed by the watcher.
+// Restarts are performed with exponential backoff and limited by the budget within the window.
+type replicationRestarts struct {
+	maxRestarts int
+	window      time.Duration
+	// times - Times of restarts within the window.
+	times []time.Time
+	// attempts - Number of restarts since the last successful check.
+	attempts    int
+	nextAttempt time.Time
+}
+
+func NewReplicationWatcher(lock *sync.Mutex, info *disasterrecovery.WatcherInfo) ReplicationWatcher {
+	var cancel context.CancelFunc
 	return ReplicationWatcher{
-		Lock:  lock,
-		state: &state,
+		Lock:          lock,
+		Info:          info,
+		cancel:        &cancel,
+		indexRestarts: make(map[string]*indexRestartState),
 	}
 }
 
+// isStarted checks whether the watcher is started. The watcher stays started when its restart budget is exhausted,
+// so it is not run again by reconciliation until it is paused by the switchover.
+func (rw ReplicationWatcher) isStarted() bool {
+	return *rw.cancel != nil
+}
+
 func (rw ReplicationWatcher) start(drr DisasterRecoveryReconciler, logger logr.Logger) {
-	if *rw.state == runningState {
+	if rw.isStarted() {
 		return
-	} else {
-		*rw.state = runningState
 	}
 	logger.Info("Start Replication Watcher")
-	watchInterval := drr.cr.Spec.DisasterRecovery.ReplicationWatcherInterval
+	ctx, cancel := context.WithCancel(context.Background())
+	*rw.cancel = cancel
+	disasterRecovery := drr.cr.Spec.DisasterRecovery
+	watchInterval := disasterRecovery.ReplicationWatcherInterval
 	if watchInterval <= 0 {
 		watchInterval = defaultWatchInterval
 	}
-	go rw.watch(drr, logger, watchInterval)
+	restarts := &replicationRestarts{
+		maxRestarts: defaultMaxReplicationRestarts,
+		window:      defaultReplicationRestartWindow,
+	}
+	if disasterRecovery.ReplicationWatcherMaxRestarts > 0 {
+		restarts.maxRestarts = disasterRecovery.ReplicationWatcherMaxRestarts
+	}
+	if disasterRecovery.ReplicationWatcherRestartWindow > 0 {
+		restarts.window = time.Duration(disasterRecovery.ReplicationWatcherRestartWindow) * time.Second
+	}
+	replicationWatcherBudgetExhausted.Set(0)
+	// The condition is only reset if the watcher was stopped before to not add it to every custom resource
+	if hasCondition(drr.cr.Status.Conditions, replicationWatcherConditionReason) {
+		drr.updateReplicationWatcherCondition(statusTrue, typeSuccessful, "Replication Watcher is running")
+	}
+	go rw.watch(ctx, drr, logger, time.Duration(watchInterval)*time.Second, restarts)
+}
+
+func (rw ReplicationWatcher) pause(logger logr.Logger) {
+	if !rw.isStarted() {
+		return
+	}
+	logger.Info("Stop Replication Watcher")
+	(*rw.cancel)()
+	*rw.cancel = nil
 }
 
-func (rw ReplicationWatcher) watch(drr DisasterRecoveryReconciler, logger logr.Logger, interval int) {
+func (rw ReplicationWatcher) watch(ctx context.Context, drr DisasterRecoveryReconciler, logger logr.Logger,
+	interval time.Duration, restarts *replicationRestarts) {
 	for {
-		if *rw.state == pausedState {
-			logger.Info("Replication Watcher was stopped, exit from watch loop")
-			return
-		}
 		// Fetch the OpenSearchService instance
 		instance := &opensearchservice.OpenSearchService{}
-		var err error
-		if err = drr.reconciler.Client.Get(context.TODO(), types.NamespacedName{
+		if err := drr.reconciler.Client.Get(ctx, types.NamespacedName{
 			Namespace: drr.cr.Namespace,
 			Name:      drr.cr.Name,
 		}, instance); err != nil {
-			logger.Error(err, "")
-		} else {
-			if instance.Spec.DisasterRecovery.Mode == "standby" &&
-				instance.Status.DisasterRecoveryStatus.Mode == "standby" &&
-				instance.Status.DisasterRecoveryStatus.Status == "done" {
-				rw.restartReplicationOnFailure(drr, logger)
-				if *rw.state == pausedState {
-					logger.Info("Replication Watcher was stopped, exit from watch loop")
-					return
-				}
+			if ctx.Err() == nil {
+				logger.Error(err, "")
+			}
+		} else if instance.Spec.DisasterRecovery.Mode == "standby" &&
+			instance.Status.DisasterRecoveryStatus.Mode == "standby" &&
+			instance.Status.DisasterRecoveryStatus.Status == "done" {
+			if !rw.restartReplicationOnFailure(ctx, drr, instance.Spec.DisasterRecovery.ReplicationPause, logger, restarts) {
+				message := fmt.Sprintf("Replication Watcher is stopped because replication was restarted %d times within %s, "+
+					"check replication and perform the switchover to standby mode to run the watcher again",
+					restarts.maxRestarts, restarts.window)
+				logger.Info(message)
+				replicationWatcherBudgetExhausted.Set(1)
+				drr.updateReplicationWatcherCondition(statusFalse, typeFailed, message)
+				return
 			}
 		}
-		time.Sleep(time.Duration(interval) * time.Second)
+		select {
+		case <-ctx.Done():
+			logger.Info("Replication Watcher was stopped, exit from watch loop")
+			return
+		case <-time.After(interval):
+		}
 	}
 }
 
-func (rw ReplicationWatcher) restartReplicationOnFailure(drr DisasterRecoveryReconciler, logger logr.Logger) {
+// restartReplicationOnFailure checks replication and repairs it.
+// It returns false if the whole replication needs to be restarted, but the restart budget is exhausted.
+// Indices paused on request are not considered as failed and the whole replication is not restarted while the pause is enabled.
+func (rw ReplicationWatcher) restartReplicationOnFailure(ctx context.Context, drr DisasterRecoveryReconciler,
+	pause *opensearchservice.ReplicationPause, logger logr.Logger, restarts *replicationRestarts) bool {
 	defer rw.Lock.Unlock()
 	rw.Lock.Lock()
-	if err := rw.checkReplication(drr, false, logger); err != nil {
-		if *rw.state == pausedState {
-			logger.Info("Replication Watcher was stopped, exit from watch loop")
-			return
+	if ctx.Err() != nil {
+		return true
+	}
+	replicationManager, err := drr.getReplicationManager()
+	if err != nil {
+		logger.Error(err, "Unable to get replication configuration")
+		return true
+	}
+	rulesStats, failedReplications, err := rw.getFailedReplications(replicationManager, pause, logger)
+	if ctx.Err() != nil {
+		return true
+	}
+	if err != nil {
+		// The state of autofollow rules is unknown, so the replication is not restarted until the next check
+		replicationWatcherChecks.WithLabelValues(replicationCheckFailed).Inc()
+		logger.Error(err, "Cannot check autofollow replication rules, skip replication repair")
+		return true
+	}
+	now := time.Now()
+	if rulesStats == nil {
+		replicationWatcherChecks.WithLabelValues(replicationCheckFailed).Inc()
+		if pause != nil && pause.Enabled {
+			logger.Info("Autofollow rule is broken, but replication is paused, skip replication restart")
+			return true
+		}
+		if now.Before(restarts.nextAttempt) {
+			logger.Info(fmt.Sprintf("Autofollow rule is broken, skip replication restart till %s",
+				restarts.nextAttempt.Format(time.RFC3339)))
+			return true
+		}
+		if restarts.exhausted(now) {
+			return false
 		}
-		logger.Info(fmt.Sprintf("Try to restart replication because of error: %v", err))
-		rw.restartReplication(drr, logger)
+		logger.Info("Try to restart replication because autofollow rule is broken")
+		restarts.record(now)
+		rw.restartReplication(ctx, drr, logger)
+		return true
 	}
+	if len(failedReplications) == 0 {
+		replicationWatcherChecks.WithLabelValues(replicationCheckPassed).Inc()
+		logger.Info("Replication works correctly, there are no failed indices")
+		restarts.reset()
+	} else {
+		replicationWatcherChecks.WithLabelValues(replicationCheckFailed).Inc()
+	}
+	if err = replicationManager.StopExcludedIndicesReplication(); err != nil {
+		logger.Error(err, "Cannot stop replication of excluded indices")
+	}
+	rw.repairIndices(drr, replicationManager, failedReplications, logger)
+	return true
+}
+
+// exhausted checks whether the maximum number of restarts is reached within the window
+func (rr *replicationRestarts) exhausted(now time.Time) bool {
+	times := rr.times[:0]
+	for _, restartTime := range rr.times {
+		if now.Sub(restartTime) < rr.window {
+			times = append(times, restartTime)
+		}
+	}
+	rr.times = times
+	return len(rr.times) >= rr.maxRestarts
+}
+
+// record adds the restart and postpones the next one with exponential backoff and jitter
+func (rr *replicationRestarts) record(now time.Time) {
+	rr.times = append(rr.times, now)
+	rr.attempts++
+	rr.nextAttempt = now.Add(withJitter(indexRestartBackoff(rr.attempts)))
+}
+
+// reset allows the next restart without delay after replication has been recovered
+func (rr *replicationRestarts) reset() {
+	rr.attempts = 0
+	rr.nextAttempt = time.Time{}
 }
 
 func (rw ReplicationWatcher) checkReplication(drr DisasterRecoveryReconciler, allowNoAutofollowRule bool, logger logr.Logger) error {
 	logger.Info("Start checking replication status")
-	replicationManager := drr.getReplicationManager()
-	autoFollowRuleStats, err := replicationManager.GetAutoFollowRuleStats()
+	replicationManager, err := drr.getReplicationManager()
 	if err != nil {
-		logger.Error(err, "Cannot check autofollow replication rule")
-	}
-	if autoFollowRuleStats != nil {
-		failedIndices := util.FilterSlice(autoFollowRuleStats.FailedIndices, func(s string) bool {
-			return !strings.HasPrefix(s, ".")
-		})
-		if len(failedIndices) > 0 {
-			return fmt.Errorf("replication does not work correctly, there are failed indices: %s", failedIndices)
-		} else {
-			indices, err := replicationManager.GetIndicesByPatternExcludeService(replicationManager.pattern)
-			if err != nil {
-				log.Error(err, fmt.Sprintf("Cannot get indices by pattern [%s]", replicationManager.pattern))
+		return err
+	}
+	rulesStats, failedReplications, err := rw.getFailedReplications(replicationManager, nil, logger)
+	if err != nil {
+		return fmt.Errorf("cannot check autofollow replication rules: %w", err)
+	}
+	if rulesStats == nil {
+		if !allowNoAutofollowRule {
+			return fmt.Errorf("there is no autofollow rule")
+		}
+		return nil
+	}
+	if len(failedReplications) > 0 {
+		failedIndices := make([]string, 0, len(failedReplications))
+		for index := range failedReplications {
+			failedIndices = append(failedIndices, index)
+		}
+		sort.Strings(failedIndices)
+		return fmt.Errorf("replication does not work correctly, there are failed indices: %s", failedIndices)
+	}
+	logger.Info("Replication works correctly, there are no failed indices")
+	return nil
+}
+
+// getFailedReplications returns the autofollow rules statistics and follower indices with broken replication
+// mapped to their replication status. Indices the autofollow rules failed to start replication for have empty status.
+// Returned rules statistics is nil if any of autofollow rules does not exist, the error is returned
+// if statistics cannot be received. Indices paused on request by the pause parameters are not considered as failed.
+func (rw ReplicationWatcher) getFailedReplications(replicationManager ReplicationManager,
+	pause *opensearchservice.ReplicationPause, logger logr.Logger) ([]RuleStats, map[string]string, error) {
+	failedReplications := make(map[string]string)
+	rulesStats, err := replicationManager.GetAutoFollowRulesStats()
+	if err != nil {
+		return nil, failedReplications, err
+	}
+	if rulesStats == nil {
+		return nil, failedReplications, nil
+	}
+	for _, ruleStats := range rulesStats {
+		for _, index := range ruleStats.FailedIndices {
+			// Excluded indices are not followed, so autofollow rules fail to start their replication
+			if !strings.HasPrefix(index, ".") && replicationManager.matches(index) {
+				failedReplications[index] = ""
+			}
+		}
+	}
+	indices, err := replicationManager.GetRulesIndices()
+	if err != nil {
+		logger.Error(err, "Cannot get indices by replication rules")
+		return rulesStats, failedReplications, nil
+	}
+	for _, index := range indices {
+		replicationStatus, err := replicationManager.getIndexReplicationStatus(index)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Cannot get replication status of [%s] index", index))
+		} else if replicationStatus.Status == failedStatus {
+			failedReplications[index] = replicationStatus.Status
+		} else if replicationStatus.Status == replicationPausedStatus {
+			if isPausedOnRequest(pause, index) {
+				logger.Info(fmt.Sprintf("Replication for index [%s] is paused on request", index))
+			} else if strings.Contains(replicationStatus.Reason, "IndexNotFoundException") {
+				logger.Info(fmt.Sprintf("Replication for index [%s] is paused because index was lost on active side, make sure active side has right content and remove standby index", index))
 			} else {
-				var failedReplications []string
-				for _, index := range indices {
-					replicationStatus, err := replicationManager.getIndexReplicationStatus(index)
-					if err != nil {
-						log.Error(err, fmt.Sprintf("Cannot get replication status of [%s] index", index))
-					} else if replicationStatus.Status == failedStatus {
-						failedReplications = append(failedReplications, index)
-					} else if replicationStatus.Status == "PAUSED" {
-						if strings.Contains(replicationStatus.Reason, "IndexNotFoundException") {
-							logger.Info(fmt.Sprintf("Replication for index [%s] is paused because index was lost on active side, make sure active side has right content and remove standby index", index))
-						} else {
-							failedReplications = append(failedReplications, index)
-						}
-					}
-				}
-				if len(failedReplications) > 0 {
-					return fmt.Errorf("replication does not work correctly, there are failed indices: %s", failedReplications)
-				} else {
-					logger.Info("Replication works correctly, there are no failed indices")
-				}
+				failedReplications[index] = replicationStatus.Status
 			}
+		}
+	}
+	return rulesStats, failedReplications, nil
+}
 
+// repairIndices tries to restore replication of each failed index separately. Paused index is resumed at first,
+// if it does not help or replication is failed, the index is removed and replicated again.
+// Attempts for the same index are performed with exponential backoff.
+func (rw ReplicationWatcher) repairIndices(drr DisasterRecoveryReconciler, replicationManager ReplicationManager,
+	failedReplications map[string]string, logger logr.Logger) {
+	for index := range rw.indexRestarts {
+		if _, ok := failedReplications[index]; !ok {
+			logger.Info(fmt.Sprintf("Replication of [%s] index was recovered", index))
+			delete(rw.indexRestarts, index)
 		}
-	} else if !allowNoAutofollowRule {
-		return fmt.Errorf("there is no autofollow rule")
 	}
-	return nil
+	now := time.Now()
+	for index, status := range failedReplications {
+		restartState, ok := rw.indexRestarts[index]
+		if !ok {
+			restartState = &indexRestartState{}
+			rw.indexRestarts[index] = restartState
+		}
+		if now.Before(restartState.nextAttempt) {
+			logger.Info(fmt.Sprintf("Skip repairing of [%s] index replication till %s", index,
+				restartState.nextAttempt.Format(time.RFC3339)))
+			continue
+		}
+		action := refollowAction
+		if status == replicationPausedStatus && restartState.attempts == 0 {
+			action = resumeAction
+		}
+		logger.Info(fmt.Sprintf("Try to %s replication of [%s] index with [%s] status", action, index, status))
+		var err error
+		if action == resumeAction {
+			err = replicationManager.ResumeIndexReplication(index)
+		} else {
+			err = replicationManager.RefollowIndex(index)
+		}
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Unable to %s replication of [%s] index", action, index))
+		}
+		replicationWatcherRestarts.WithLabelValues(action).Inc()
+		restartState.attempts++
+		restartState.nextAttempt = now.Add(withJitter(indexRestartBackoff(restartState.attempts)))
+		drr.addReplicationAction(index, action, err)
+	}
 }
 
-func (rw ReplicationWatcher) pause(logger logr.Logger) {
-	logger.Info("Stop Replication Watcher")
-	*rw.state = pausedState
+// indexRestartBackoff returns the delay before the next repair attempt for the index,
+// it doubles with each attempt up to maxIndexRestartBackoff
+func indexRestartBackoff(attempts int) time.Duration {
+	backoff := time.Second * restartWaitPeriod
+	for i := 1; i < attempts && backoff < maxIndexRestartBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxIndexRestartBackoff {
+		backoff = maxIndexRestartBackoff
+	}
+	return backoff
 }
 
-func (rw ReplicationWatcher) restartReplication(drr DisasterRecoveryReconciler, logger logr.Logger) {
+func (rw ReplicationWatcher) restartReplication(ctx context.Context, drr DisasterRecoveryReconciler, logger logr.Logger) {
 	logger.Info("Restart replication")
-	replicationManager := drr.getReplicationManager()
-	err := drr.removePreviousReplication(replicationManager)
+	if rw.Info != nil {
+		rw.Info.RecordRestart(time.Now())
+	}
+	replicationWatcherRestarts.WithLabelValues(restartAction).Inc()
+	replicationManager, err := drr.getReplicationManager()
+	if err != nil {
+		logger.Error(err, "Unable to get replication configuration")
+		return
+	}
+	for index := range rw.indexRestarts {
+		delete(rw.indexRestarts, index)
+	}
+	err = drr.removePreviousReplication(replicationManager)
 	if err != nil {
 		logger.Error(err, "Previous replication cannot be stopped")
+		drr.addReplicationAction("", restartAction, err)
 		return
 	}
 	err = drr.runReplicationProcess(replicationManager)
+	drr.addReplicationAction("", restartAction, err)
 	if err != nil {
 		logger.Error(err, "Replication cannot be started")
 		return
 	}
 	logger.Info("Replication was restarted")
-	time.Sleep(time.Second * restartWaitPeriod)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second * restartWaitPeriod):
+	}
+}
+
+// withJitter adds random delay up to maxRestartJitterFraction of the backoff
+// to avoid simultaneous restarts of replication on different sides and indices
+func withJitter(backoff time.Duration) time.Duration {
+	return backoff + time.Duration(rand.Float64()*maxRestartJitterFraction*float64(backoff))
+}
+
+// updateReplicationWatcherCondition sets the condition of the replication watcher in status
+func (r DisasterRecoveryReconciler) updateReplicationWatcherCondition(status string, conditionType string, message string) {
+	statusUpdater := util.NewStatusUpdater(r.reconciler.Client, r.cr)
+	err := statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		condition := NewCondition(status, conditionType, replicationWatcherConditionReason, message)
+		condition.LastTransitionTime = metav1.Now().String()
+		instance.Status.Conditions = addCondition(instance.Status.Conditions, condition)
+	})
+	if err != nil {
+		r.logger.Error(err, "Unable to update Replication Watcher condition")
+	}
 }
@@ -0,0 +1,164 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+)
+
+func TestIndexRestartBackoff_GrowsExponentiallyUpToLimit(t *testing.T) {
+	expected := map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		3:  4 * time.Minute,
+		5:  16 * time.Minute,
+		6:  maxIndexRestartBackoff,
+		20: maxIndexRestartBackoff,
+	}
+	for attempts, backoff := range expected {
+		if actual := indexRestartBackoff(attempts); actual != backoff {
+			t.Errorf("expected %v backoff for %d attempts, got %v", backoff, attempts, actual)
+		}
+	}
+}
+
+func TestWithJitter_AddsLimitedDelay(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		backoff := withJitter(time.Minute)
+		if backoff < time.Minute || backoff > time.Minute+time.Duration(maxRestartJitterFraction*float64(time.Minute)) {
+			t.Fatalf("unexpected backoff with jitter: %v", backoff)
+		}
+	}
+}
+
+func TestReplicationRestarts_ExhaustedWithinWindow(t *testing.T) {
+	restarts := &replicationRestarts{maxRestarts: 2, window: time.Hour}
+	now := time.Now()
+	restarts.record(now.Add(-2 * time.Hour))
+	restarts.record(now.Add(-time.Minute))
+	if restarts.exhausted(now) {
+		t.Fatal("expected restart outside the window to be ignored")
+	}
+	if len(restarts.times) != 1 {
+		t.Errorf("expected only restarts within the window to be kept, got %v", restarts.times)
+	}
+	restarts.record(now)
+	if !restarts.exhausted(now) {
+		t.Error("expected restart budget to be exhausted")
+	}
+}
+
+func TestReplicationRestarts_BackoffIsResetAfterRecovery(t *testing.T) {
+	restarts := &replicationRestarts{maxRestarts: 5, window: time.Hour}
+	now := time.Now()
+	restarts.record(now)
+	restarts.record(now)
+	if restarts.attempts != 2 || restarts.nextAttempt.Before(now.Add(2*time.Minute)) {
+		t.Errorf("expected the second restart to be postponed at least for 2 minutes, got %v", restarts.nextAttempt)
+	}
+	restarts.reset()
+	if restarts.attempts != 0 || !restarts.nextAttempt.IsZero() || len(restarts.times) != 2 {
+		t.Errorf("expected only backoff to be reset, got %+v", restarts)
+	}
+}
+
+func TestReplicationWatcher_PauseCancelsContext(t *testing.T) {
+	var mu sync.Mutex
+	rw := NewReplicationWatcher(&mu, nil)
+	rw.pause(logr.Discard())
+	ctx, cancel := context.WithCancel(context.Background())
+	*rw.cancel = cancel
+	if !rw.isStarted() {
+		t.Fatal("expected watcher to be started")
+	}
+	rw.pause(logr.Discard())
+	if rw.isStarted() || ctx.Err() == nil {
+		t.Error("expected watcher context to be cancelled")
+	}
+}
+
+func TestGetFailedReplications_CollectsFailedAndPausedIndices(t *testing.T) {
+	responses := map[string]string{
+		"/_plugins/_replication/autofollow_stats": `{"autofollow_stats":[{"name":"dr-replication","pattern":"*",
+			"failed_indices":["not-started",".service","scratch-1"]}]}`,
+		"/_cat/indices/*,-scratch-*":             `[{"index":"syncing"},{"index":"failed"},{"index":"paused"},{"index":"lost"}]`,
+		"/_plugins/_replication/syncing/_status": `{"status":"SYNCING"}`,
+		"/_plugins/_replication/failed/_status":  `{"status":"FAILED"}`,
+		"/_plugins/_replication/paused/_status":  `{"status":"PAUSED","reason":"network issue"}`,
+		"/_plugins/_replication/lost/_status":    `{"status":"PAUSED","reason":"IndexNotFoundException"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+	restClient := util.NewRestClient(server.URL, http.Client{}, util.Credentials{})
+	replicationManager := NewReplicationManager(*restClient, "", []ReplicationRule{{Patterns: []string{"*"}, ExcludePatterns: []string{"scratch-*"}}}, logr.Discard())
+
+	var mu sync.Mutex
+	rw := NewReplicationWatcher(&mu, nil)
+	rule, failedReplications, err := rw.getFailedReplications(*replicationManager, nil, logr.Discard())
+
+	if err != nil || rule == nil {
+		t.Fatal("expected autofollow rule to be found")
+	}
+	expected := map[string]string{"not-started": "", "failed": failedStatus, "paused": replicationPausedStatus}
+	if len(failedReplications) != len(expected) {
+		t.Fatalf("expected %v failed replications, got %v", expected, failedReplications)
+	}
+	for index, status := range expected {
+		if actual, ok := failedReplications[index]; !ok || actual != status {
+			t.Errorf("expected [%s] index with %q status, got %q", index, status, actual)
+		}
+	}
+}
+
+func TestGetFailedReplications_ReturnsErrorIfStatisticsIsUnavailable(t *testing.T) {
+	var available atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"autofollow_stats":[{"name":"other-rule","pattern":"*"}]}`))
+	}))
+	defer server.Close()
+	restClient := util.NewRestClient(server.URL, http.Client{}, util.Credentials{})
+	replicationManager := NewReplicationManager(*restClient, "", []ReplicationRule{{Patterns: []string{"*"}}}, logr.Discard())
+
+	var mu sync.Mutex
+	rw := NewReplicationWatcher(&mu, nil)
+	rule, _, err := rw.getFailedReplications(*replicationManager, nil, logr.Discard())
+	if err == nil || rule != nil {
+		t.Errorf("expected error for unavailable statistics, got %v, %v", rule, err)
+	}
+
+	available.Store(true)
+	rule, _, err = rw.getFailedReplications(*replicationManager, nil, logr.Discard())
+	if err != nil || rule != nil {
+		t.Errorf("expected absent autofollow rule without error, got %v, %v", rule, err)
+	}
+}
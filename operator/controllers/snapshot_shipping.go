@@ -0,0 +1,170 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+)
+
+const drSnapshotShippingHashName = "spec.disasterRecovery.snapshotShipping"
+
+// isSnapshotShippingEnabled checks whether Disaster Recovery is based on snapshots instead of the cross cluster replication
+func (r DisasterRecoveryReconciler) isSnapshotShippingEnabled() bool {
+	snapshotShipping := r.cr.Spec.DisasterRecovery.SnapshotShipping
+	return snapshotShipping != nil && snapshotShipping.Enabled
+}
+
+// switchoverSnapshotShipping takes the final snapshot when the active side is switched to `standby` mode
+// and restores it when the standby side is switched to `active` mode. In `disable` mode the snapshot shipping is only stopped.
+func (r DisasterRecoveryReconciler) switchoverSnapshotShipping(replicationManager ReplicationManager,
+	historyEntry *opensearchservice.SwitchoverHistoryEntry) (string, error) {
+	watcher := r.reconciler.SnapshotShippingWatcher
+	// The running shipping is cancelled, so the lock is released without waiting for the snapshot or restore
+	watcher.stop()
+	if r.cr.Spec.DisasterRecovery.Mode == "disable" {
+		return "The snapshot shipping has stopped successfully", nil
+	}
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+
+	helper, err := r.prepareSnapshotShippingHelper(replicationManager)
+	if err != nil {
+		return "", err
+	}
+	previousMode := r.cr.Status.DisasterRecoveryStatus.Mode
+	if r.cr.Spec.DisasterRecovery.Mode == "standby" {
+		if previousMode != "active" {
+			return "The snapshot shipping has started successfully", nil
+		}
+		r.logger.Info("Take the final snapshot before switchover to standby mode")
+		snapshot, err := helper.takeSnapshot(context.Background())
+		if err != nil {
+			return "", fmt.Errorf("unable to take the final snapshot: %w", err)
+		}
+		// The data of the current side corresponds to the final snapshot, so it is not restored in `standby` mode
+		*watcher.lastRestored = snapshot.Snapshot
+		helper.updateStatus("standby", &snapshot, nil)
+		return fmt.Sprintf("The final snapshot [%s] has been taken successfully", snapshot.Snapshot), nil
+	}
+
+	if previousMode != "standby" {
+		return "Snapshot shipping mode has been changed without the final restore", nil
+	}
+	r.logger.Info("Restore the final snapshot before switchover to active mode")
+	snapshot, err := helper.restoreLatestSnapshot(context.Background())
+	if r.isFailover() {
+		// The repository can be unavailable together with the other side, so the last restored data is kept
+		if err != nil {
+			r.logger.Error(err, "Unable to restore the final snapshot during failover")
+			return "The failover has been performed without the final restore", nil
+		}
+		*watcher.lastRestored = snapshot.Snapshot
+		helper.updateStatus("standby", &snapshot, nil)
+		return fmt.Sprintf("The failover has been performed, snapshot [%s] is restored", snapshot.Snapshot), nil
+	}
+	historyEntry.ReplicationCheck = replicationCheckResult(err)
+	if err != nil {
+		return "", fmt.Errorf("unable to restore the final snapshot: %w", err)
+	}
+	*watcher.lastRestored = snapshot.Snapshot
+	helper.updateStatus("standby", &snapshot, nil)
+	return fmt.Sprintf("The final snapshot [%s] has been restored successfully", snapshot.Snapshot), nil
+}
+
+// reconcileSnapshotShipping runs taking snapshots in `active` mode and restoring them in `standby` mode
+func (r DisasterRecoveryReconciler) reconcileSnapshotShipping() {
+	watcher := r.reconciler.SnapshotShippingWatcher
+	mode := r.cr.Spec.DisasterRecovery.Mode
+	if !r.isSnapshotShippingEnabled() || (mode != "active" && mode != "standby") {
+		watcher.stop()
+		delete(r.reconciler.ResourceHashes, drSnapshotShippingHashName)
+		return
+	}
+	snapshotShipping := r.cr.Spec.DisasterRecovery.SnapshotShipping
+	snapshotShippingHash, err := util.Hash(struct {
+		Mode             string
+		SnapshotShipping opensearchservice.SnapshotShipping
+	}{mode, *snapshotShipping})
+	if err != nil {
+		r.logger.Error(err, "Unable to calculate hash of snapshot shipping parameters")
+		return
+	}
+	if r.reconciler.ResourceHashes[drSnapshotShippingHashName] == snapshotShippingHash && watcher.isRunning() {
+		return
+	}
+	replicationManager, err := r.getReplicationManager()
+	if err != nil {
+		r.logger.Error(err, "Unable to start snapshot shipping")
+		return
+	}
+	helper, err := r.prepareSnapshotShippingHelper(replicationManager)
+	if err != nil {
+		r.logger.Error(err, "Unable to start snapshot shipping")
+		return
+	}
+	r.reconciler.ResourceHashes[drSnapshotShippingHashName] = snapshotShippingHash
+	shippingInterval := defaultSnapshotShippingInterval
+	if snapshotShipping.Interval > 0 {
+		shippingInterval = time.Duration(snapshotShipping.Interval) * time.Second
+	}
+	r.logger.Info(fmt.Sprintf("Start snapshot shipping in [%s] mode", mode))
+	watcher.start(helper, mode, shippingInterval)
+}
+
+func (r DisasterRecoveryReconciler) prepareSnapshotShippingHelper(replicationManager ReplicationManager) (SnapshotShippingHelper, error) {
+	snapshotShipping := r.cr.Spec.DisasterRecovery.SnapshotShipping
+	repository := snapshotShipping.RepositoryName
+	if repository == "" && r.cr.Spec.OpenSearch != nil && r.cr.Spec.OpenSearch.Snapshots != nil {
+		repository = r.cr.Spec.OpenSearch.Snapshots.RepositoryName
+	}
+	if repository == "" {
+		return SnapshotShippingHelper{}, fmt.Errorf("snapshots repository is not specified for snapshot shipping")
+	}
+	retention := defaultSnapshotRetention
+	if snapshotShipping.Retention > 0 {
+		retention = snapshotShipping.Retention
+	}
+	lastRestored := ""
+	if status := r.cr.Status.DisasterRecoveryStatus.SnapshotShipping; status != nil && status.Mode == "standby" {
+		lastRestored = status.Snapshot
+	}
+	return SnapshotShippingHelper{
+		logger:            r.logger,
+		restClient:        replicationManager.restClient,
+		statusUpdater:     util.NewStatusUpdater(r.reconciler.Client, r.cr),
+		repository:        repository,
+		indices:           snapshotIndices(replicationManager.rules),
+		retention:         retention,
+		renamePattern:     snapshotShipping.RenamePattern,
+		renameReplacement: snapshotShipping.RenameReplacement,
+		lastRestored:      lastRestored,
+	}, nil
+}
+
+// snapshotIndices returns OpenSearch multi-target expression with indices of all replication rules,
+// service indices are never shipped as it is done for the cross cluster replication
+func snapshotIndices(rules []ReplicationRule) string {
+	expressions := make([]string, 0, len(rules)+1)
+	for _, rule := range rules {
+		expressions = append(expressions, rule.indexExpression())
+	}
+	return strings.Join(append(expressions, "-.*"), ",")
+}
@@ -0,0 +1,368 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/disasterrecovery"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	drSnapshotPrefix                = "dr-snapshot-"
+	drSnapshotNameLayout            = "20060102150405"
+	defaultSnapshotShippingInterval = disasterrecovery.DefaultSnapshotShippingInterval
+	defaultSnapshotRetention        = 10
+	snapshotShippingTimeout         = 30 * time.Minute
+	snapshotSuccessState            = "SUCCESS"
+	snapshotInProgressState         = "IN_PROGRESS"
+)
+
+type SnapshotShippingHelper struct {
+	logger            logr.Logger
+	restClient        util.RestClient
+	statusUpdater     util.StatusUpdater
+	repository        string
+	indices           string
+	retention         int
+	renamePattern     string
+	renameReplacement string
+	// lastRestored is the name of the last snapshot restored on the standby side
+	lastRestored string
+}
+
+// SnapshotShippingWatcher periodically takes snapshots of replicated indices in `active` mode
+// and restores new snapshots in `standby` mode
+type SnapshotShippingWatcher struct {
+	lock   *sync.Mutex
+	cancel *context.CancelFunc
+	// lastRestored is the name of the snapshot the data of the current side corresponds to
+	lastRestored *string
+}
+
+type drSnapshot struct {
+	Snapshot          string   `json:"snapshot"`
+	State             string   `json:"state"`
+	Indices           []string `json:"indices"`
+	StartTimeInMillis int64    `json:"start_time_in_millis"`
+}
+
+type restoreResponse struct {
+	Accepted bool `json:"accepted"`
+}
+
+type indicesHealth struct {
+	Status string `json:"status"`
+}
+
+func NewSnapshotShippingWatcher(mutex *sync.Mutex) SnapshotShippingWatcher {
+	var cancel context.CancelFunc
+	var lastRestored string
+	return SnapshotShippingWatcher{
+		lock:         mutex,
+		cancel:       &cancel,
+		lastRestored: &lastRestored,
+	}
+}
+
+func (sw SnapshotShippingWatcher) isRunning() bool {
+	return *sw.cancel != nil
+}
+
+func (sw SnapshotShippingWatcher) start(helper SnapshotShippingHelper, mode string, interval time.Duration) {
+	sw.stop()
+	if *sw.lastRestored != "" {
+		helper.lastRestored = *sw.lastRestored
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	*sw.cancel = cancel
+	go sw.watch(ctx, helper, mode, interval)
+}
+
+func (sw SnapshotShippingWatcher) stop() {
+	if *sw.cancel != nil {
+		(*sw.cancel)()
+		*sw.cancel = nil
+	}
+}
+
+func (sw SnapshotShippingWatcher) watch(ctx context.Context, helper SnapshotShippingHelper, mode string, interval time.Duration) {
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+			sw.lock.Lock()
+			if ctx.Err() == nil {
+				helper.lastRestored = helper.ship(ctx, mode)
+				*sw.lastRestored = helper.lastRestored
+			}
+			sw.lock.Unlock()
+		}
+	}
+	helper.logger.Info("Snapshot Shipping Watcher is stopped, exit from watch loop")
+}
+
+// ship takes a new snapshot in `active` mode or restores the latest snapshot in `standby` mode
+// and returns the name of the last restored snapshot. Shipping is interrupted when the context is cancelled,
+// for example, by switchover.
+func (helper SnapshotShippingHelper) ship(ctx context.Context, mode string) string {
+	if mode == "active" {
+		snapshot, err := helper.takeSnapshot(ctx)
+		if ctx.Err() != nil {
+			return helper.lastRestored
+		}
+		if err != nil {
+			helper.logger.Error(err, "Unable to take Disaster Recovery snapshot")
+			helper.updateStatus(mode, nil, err)
+			return helper.lastRestored
+		}
+		helper.deleteExpiredSnapshots()
+		helper.updateStatus(mode, &snapshot, nil)
+		return helper.lastRestored
+	}
+	snapshot, err := helper.restoreLatestSnapshot(ctx)
+	if ctx.Err() != nil {
+		return helper.lastRestored
+	}
+	if err != nil {
+		helper.logger.Error(err, "Unable to restore Disaster Recovery snapshot")
+		helper.updateStatus(mode, nil, err)
+		return helper.lastRestored
+	}
+	helper.updateStatus(mode, &snapshot, nil)
+	return snapshot.Snapshot
+}
+
+// takeSnapshot creates the snapshot of replicated indices and waits until it is finished.
+// Snapshots are incremental, so only segments changed since the previous snapshot are copied to the repository.
+func (helper SnapshotShippingHelper) takeSnapshot(ctx context.Context) (drSnapshot, error) {
+	name := drSnapshotPrefix + time.Now().UTC().Format(drSnapshotNameLayout)
+	helper.logger.Info(fmt.Sprintf("Take Disaster Recovery snapshot [%s]", name))
+	body, _ := json.Marshal(map[string]interface{}{
+		"indices":              helper.indices,
+		"ignore_unavailable":   true,
+		"include_global_state": false,
+	})
+	path := fmt.Sprintf("_snapshot/%s/%s", helper.repository, name)
+	if _, err := helper.restClient.SendRequestWithStatusCodeCheck(http.MethodPut, path, bytes.NewReader(body)); err != nil {
+		return drSnapshot{}, err
+	}
+	var snapshot drSnapshot
+	err := wait.PollUntilContextTimeout(ctx, interval, snapshotShippingTimeout, true, func(context.Context) (bool, error) {
+		snapshots, err := helper.getSnapshots(name)
+		if err != nil || len(snapshots) == 0 {
+			helper.logger.Error(err, fmt.Sprintf("Unable to get state of [%s] snapshot", name))
+			return false, nil
+		}
+		snapshot = snapshots[0]
+		return snapshot.State != snapshotInProgressState, nil
+	})
+	if err != nil {
+		return snapshot, fmt.Errorf("snapshot [%s] is not finished: %w", name, err)
+	}
+	if snapshot.State != snapshotSuccessState {
+		return snapshot, fmt.Errorf("snapshot [%s] is finished with [%s] state", name, snapshot.State)
+	}
+	return snapshot, nil
+}
+
+// restoreLatestSnapshot restores the latest successful snapshot if it has not been restored yet.
+// Existing indices are closed before the restore to be replaced with the ones from the snapshot.
+func (helper SnapshotShippingHelper) restoreLatestSnapshot(ctx context.Context) (drSnapshot, error) {
+	snapshots, err := helper.getSnapshots(drSnapshotPrefix + "*")
+	if err != nil {
+		return drSnapshot{}, err
+	}
+	snapshots = successfulSnapshots(snapshots)
+	if len(snapshots) == 0 {
+		return drSnapshot{}, fmt.Errorf("there are no Disaster Recovery snapshots in [%s] repository", helper.repository)
+	}
+	latest := snapshots[len(snapshots)-1]
+	if latest.Snapshot == helper.lastRestored {
+		return latest, nil
+	}
+	if err = helper.restoreSnapshot(ctx, latest); err != nil {
+		return drSnapshot{}, err
+	}
+	return latest, nil
+}
+
+// restoreSnapshot restores indices of the snapshot over the existing closed ones, so the data is not lost
+// if the restore is failed. Indices are reopened if the restore is not accepted.
+func (helper SnapshotShippingHelper) restoreSnapshot(ctx context.Context, snapshot drSnapshot) error {
+	helper.logger.Info(fmt.Sprintf("Restore Disaster Recovery snapshot [%s]", snapshot.Snapshot))
+	targetIndices, err := renameIndices(snapshot.Indices, helper.renamePattern, helper.renameReplacement)
+	if err != nil {
+		return err
+	}
+	if len(targetIndices) == 0 {
+		return nil
+	}
+	targets := strings.Join(targetIndices, ",")
+	if _, err = helper.restClient.SendRequestWithStatusCodeCheck(http.MethodPost,
+		fmt.Sprintf("%s/_close?ignore_unavailable=true", targets), nil); err != nil {
+		return fmt.Errorf("unable to close indices replaced by [%s] snapshot: %w", snapshot.Snapshot, err)
+	}
+	request := map[string]interface{}{
+		"indices":              strings.Join(snapshot.Indices, ","),
+		"include_global_state": false,
+	}
+	if helper.renamePattern != "" {
+		request["rename_pattern"] = helper.renamePattern
+		request["rename_replacement"] = helper.renameReplacement
+	}
+	body, _ := json.Marshal(request)
+	responseBody, err := helper.restClient.SendRequestWithStatusCodeCheck(http.MethodPost,
+		fmt.Sprintf("_snapshot/%s/%s/_restore", helper.repository, snapshot.Snapshot), bytes.NewReader(body))
+	if err == nil {
+		var response restoreResponse
+		if err = json.Unmarshal(responseBody, &response); err != nil || !response.Accepted {
+			err = fmt.Errorf("restore of [%s] snapshot is not accepted: %s", snapshot.Snapshot, responseBody)
+		}
+	}
+	if err != nil {
+		if _, openErr := helper.restClient.SendRequestWithStatusCodeCheck(http.MethodPost,
+			fmt.Sprintf("%s/_open?ignore_unavailable=true", targets), nil); openErr != nil {
+			helper.logger.Error(openErr, "Unable to reopen indices after failed restore")
+		}
+		return err
+	}
+	// Restored indices are red until all their primary shards are recovered from the repository
+	return wait.PollUntilContextTimeout(ctx, interval, snapshotShippingTimeout, true, func(context.Context) (bool, error) {
+		responseBody, err = helper.restClient.SendRequestWithStatusCodeCheck(http.MethodGet,
+			fmt.Sprintf("_cluster/health/%s", targets), nil)
+		if err != nil {
+			helper.logger.Error(err, "Unable to get health of restored indices")
+			return false, nil
+		}
+		var health indicesHealth
+		if err = json.Unmarshal(responseBody, &health); err != nil {
+			return false, err
+		}
+		return health.Status == "green" || health.Status == "yellow", nil
+	})
+}
+
+// deleteExpiredSnapshots removes the oldest Disaster Recovery snapshots keeping only the configured number of them
+func (helper SnapshotShippingHelper) deleteExpiredSnapshots() {
+	snapshots, err := helper.getSnapshots(drSnapshotPrefix + "*")
+	if err != nil {
+		helper.logger.Error(err, "Unable to get Disaster Recovery snapshots")
+		return
+	}
+	for _, snapshot := range expiredSnapshots(snapshots, helper.retention) {
+		helper.logger.Info(fmt.Sprintf("Delete expired Disaster Recovery snapshot [%s]", snapshot.Snapshot))
+		if _, err = helper.restClient.SendRequestWithStatusCodeCheck(http.MethodDelete,
+			fmt.Sprintf("_snapshot/%s/%s", helper.repository, snapshot.Snapshot), nil); err != nil {
+			helper.logger.Error(err, fmt.Sprintf("Unable to delete [%s] snapshot", snapshot.Snapshot))
+		}
+	}
+}
+
+// getSnapshots returns snapshots matching the pattern sorted by start time
+func (helper SnapshotShippingHelper) getSnapshots(pattern string) ([]drSnapshot, error) {
+	responseBody, err := helper.restClient.SendRequestWithStatusCodeCheck(http.MethodGet,
+		fmt.Sprintf("_snapshot/%s/%s", helper.repository, pattern), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Snapshots []drSnapshot `json:"snapshots"`
+	}
+	if err = json.Unmarshal(responseBody, &response); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(response.Snapshots, func(i, j int) bool {
+		return response.Snapshots[i].StartTimeInMillis < response.Snapshots[j].StartTimeInMillis
+	})
+	return response.Snapshots, nil
+}
+
+func (helper SnapshotShippingHelper) updateStatus(mode string, snapshot *drSnapshot, shippingErr error) {
+	err := helper.statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		status := opensearchservice.SnapshotShippingStatus{Mode: mode}
+		if current := instance.Status.DisasterRecoveryStatus.SnapshotShipping; current != nil && current.Mode == mode {
+			status = *current
+		}
+		status.Error = ""
+		if snapshot != nil {
+			status.Snapshot = snapshot.Snapshot
+			status.SnapshotTime = time.UnixMilli(snapshot.StartTimeInMillis).UTC().Format(time.RFC3339)
+		}
+		if shippingErr != nil {
+			status.Error = shippingErr.Error()
+		} else {
+			status.LastSyncTime = time.Now().UTC().Format(time.RFC3339)
+		}
+		instance.Status.DisasterRecoveryStatus.SnapshotShipping = &status
+	})
+	if err != nil {
+		helper.logger.Error(err, "Unable to update snapshot shipping status")
+	}
+}
+
+func successfulSnapshots(snapshots []drSnapshot) []drSnapshot {
+	var successful []drSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot.State == snapshotSuccessState {
+			successful = append(successful, snapshot)
+		}
+	}
+	return successful
+}
+
+// expiredSnapshots returns the snapshots older than the last retention ones,
+// the snapshot in progress is never expired
+func expiredSnapshots(snapshots []drSnapshot, retention int) []drSnapshot {
+	var finished []drSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot.State != snapshotInProgressState {
+			finished = append(finished, snapshot)
+		}
+	}
+	if retention <= 0 || len(finished) <= retention {
+		return nil
+	}
+	return finished[:len(finished)-retention]
+}
+
+// renameIndices returns names of indices after the restore with the rename pattern and replacement
+func renameIndices(indices []string, renamePattern string, renameReplacement string) ([]string, error) {
+	if renamePattern == "" {
+		return indices, nil
+	}
+	pattern, err := regexp.Compile(renamePattern)
+	if err != nil {
+		return nil, fmt.Errorf("rename pattern [%s] is invalid: %w", renamePattern, err)
+	}
+	renamed := make([]string, 0, len(indices))
+	for _, index := range indices {
+		renamed = append(renamed, pattern.ReplaceAllString(index, renameReplacement))
+	}
+	return renamed, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+)
+
+const testSnapshots = `{"snapshots":[
+{"snapshot":"dr-snapshot-2","state":"FAILED","indices":["orders"],"start_time_in_millis":2000},
+{"snapshot":"dr-snapshot-1","state":"SUCCESS","indices":["orders","users"],"start_time_in_millis":1000}]}`
+
+func newSnapshotShippingHelper(server *httptest.Server, lastRestored string) SnapshotShippingHelper {
+	return SnapshotShippingHelper{
+		logger:            logr.Discard(),
+		restClient:        *util.NewRestClient(server.URL, http.Client{}, util.Credentials{}),
+		repository:        "dr",
+		indices:           "*,-.*",
+		retention:         2,
+		renamePattern:     "(.+)",
+		renameReplacement: "restored-$1",
+		lastRestored:      lastRestored,
+	}
+}
+
+func TestRestoreLatestSnapshot_ReplacesRenamedIndices(t *testing.T) {
+	var lock sync.Mutex
+	var requests []string
+	var restoreRequest map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/_snapshot/dr/dr-snapshot-*":
+			_, _ = w.Write([]byte(testSnapshots))
+		case r.Method == http.MethodPost && r.URL.Path == "/_snapshot/dr/dr-snapshot-1/_restore":
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &restoreRequest)
+			_, _ = w.Write([]byte(`{"accepted":true}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/_cluster/health/restored-orders,restored-users":
+			_, _ = w.Write([]byte(`{"status":"green"}`))
+		default:
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}))
+	defer server.Close()
+
+	snapshot, err := newSnapshotShippingHelper(server, "").restoreLatestSnapshot(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot.Snapshot != "dr-snapshot-1" {
+		t.Errorf("expected the latest successful snapshot to be restored, got %s", snapshot.Snapshot)
+	}
+	if len(requests) < 2 || requests[1] != "POST /restored-orders,restored-users/_close" {
+		t.Errorf("expected renamed indices to be closed before restore, got %v", requests)
+	}
+	for _, request := range requests {
+		if strings.HasPrefix(request, http.MethodDelete) {
+			t.Errorf("expected indices not to be removed, got %s", request)
+		}
+	}
+	if restoreRequest["indices"] != "orders,users" || restoreRequest["rename_replacement"] != "restored-$1" {
+		t.Errorf("unexpected restore request: %v", restoreRequest)
+	}
+}
+
+func TestRestoreLatestSnapshot_SkipsRestoredSnapshot(t *testing.T) {
+	restored := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			restored = true
+		}
+		_, _ = w.Write([]byte(testSnapshots))
+	}))
+	defer server.Close()
+
+	snapshot, err := newSnapshotShippingHelper(server, "dr-snapshot-1").restoreLatestSnapshot(context.Background())
+	if err != nil || snapshot.Snapshot != "dr-snapshot-1" {
+		t.Fatalf("unexpected result: %+v, %v", snapshot, err)
+	}
+	if restored {
+		t.Error("expected the already restored snapshot not to be restored again")
+	}
+}
+
+func TestShip_StopsOnCancelledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"snapshots":[{"snapshot":"dr-snapshot-3","state":"IN_PROGRESS"}]}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan string)
+	go func() {
+		done <- newSnapshotShippingHelper(server, "dr-snapshot-1").ship(ctx, "active")
+	}()
+	cancel()
+	select {
+	case lastRestored := <-done:
+		if lastRestored != "dr-snapshot-1" {
+			t.Errorf("expected the last restored snapshot to be kept, got %s", lastRestored)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected shipping to be interrupted by cancelled context")
+	}
+}
+
+func TestSwitchoverSnapshotShipping_DisableOnlyStopsWatcher(t *testing.T) {
+	watcher := NewSnapshotShippingWatcher(&sync.Mutex{})
+	_, cancel := context.WithCancel(context.Background())
+	*watcher.cancel = cancel
+	r := DisasterRecoveryReconciler{
+		cr: &opensearchservice.OpenSearchService{
+			Spec: opensearchservice.OpenSearchServiceSpec{
+				DisasterRecovery: &opensearchservice.DisasterRecovery{
+					Mode:             "disable",
+					SnapshotShipping: &opensearchservice.SnapshotShipping{Enabled: true},
+				},
+			},
+			Status: opensearchservice.OpenSearchServiceStatus{
+				DisasterRecoveryStatus: opensearchservice.DisasterRecoveryStatus{Mode: "standby"},
+			},
+		},
+		logger:     logr.Discard(),
+		reconciler: &OpenSearchServiceReconciler{SnapshotShippingWatcher: watcher},
+	}
+	message, err := r.switchoverSnapshotShipping(ReplicationManager{}, &opensearchservice.SwitchoverHistoryEntry{})
+	if err != nil || message != "The snapshot shipping has stopped successfully" {
+		t.Errorf("unexpected result: %s, %v", message, err)
+	}
+	if watcher.isRunning() {
+		t.Error("expected snapshot shipping watcher to be stopped")
+	}
+}
+
+func TestExpiredSnapshots_KeepsRetentionAndInProgress(t *testing.T) {
+	snapshots := []drSnapshot{
+		{Snapshot: "1", State: snapshotSuccessState},
+		{Snapshot: "2", State: "PARTIAL"},
+		{Snapshot: "3", State: snapshotSuccessState},
+		{Snapshot: "4", State: snapshotInProgressState},
+	}
+	expired := expiredSnapshots(snapshots, 2)
+	if len(expired) != 1 || expired[0].Snapshot != "1" {
+		t.Errorf("unexpected expired snapshots: %+v", expired)
+	}
+	if expired = expiredSnapshots(snapshots, 5); len(expired) != 0 {
+		t.Errorf("expected no expired snapshots, got %+v", expired)
+	}
+}
+
+func TestSnapshotIndices_ExcludesServiceIndices(t *testing.T) {
+	rules := []ReplicationRule{
+		{Patterns: []string{"orders-*"}, ExcludePatterns: []string{"orders-tmp"}},
+		{Patterns: []string{"users"}},
+	}
+	if indices := snapshotIndices(rules); indices != "orders-*,-orders-tmp,users,-.*" {
+		t.Errorf("unexpected indices expression: %s", indices)
+	}
+}
+
+func TestRenameIndices_InvalidPattern(t *testing.T) {
+	if _, err := renameIndices([]string{"orders"}, "(", "$1"); err == nil {
+		t.Error("expected error for invalid rename pattern")
+	}
+}
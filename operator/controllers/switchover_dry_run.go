@@ -0,0 +1,250 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/disasterrecovery"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+)
+
+const (
+	connectivityCheckName        = "connectivity"
+	autofollowRulesCheckName     = "autofollowRules"
+	replicationLagCheckName      = "replicationLag"
+	documentsCountCheckName      = "documentsCount"
+	usersRecoveryCheckName       = "usersRecovery"
+	clientServicesCheckName      = "clientServices"
+	resyncCheckName              = "resync"
+	replicationLagSampleInterval = 5 * time.Second
+	maxReportedIndices           = 10
+)
+
+// isSwitchoverDryRunRequested checks whether the dry-run annotation is changed since the last report
+func (r DisasterRecoveryReconciler) isSwitchoverDryRunRequested() (string, bool) {
+	request := r.cr.Annotations[util.SwitchoverDryRunAnnotationKey]
+	if request == "" {
+		return "", false
+	}
+	report := r.cr.Status.DisasterRecoveryStatus.SwitchoverReport
+	return request, report == nil || report.Request != request
+}
+
+// runSwitchoverDryRun performs switchover checks from the current mode to the opposite one
+// without any changes of replication, client services and users
+func (r DisasterRecoveryReconciler) runSwitchoverDryRun(request string) opensearchservice.SwitchoverReport {
+	targetMode := "active"
+	if r.cr.Spec.DisasterRecovery.Mode == "active" {
+		targetMode = "standby"
+	}
+	r.logger.Info(fmt.Sprintf("Start switchover dry-run to [%s] mode", targetMode))
+	report := opensearchservice.SwitchoverReport{
+		Request:    request,
+		TargetMode: targetMode,
+	}
+	if targetMode == "standby" {
+		report.Checks = append(report.Checks, newSwitchoverCheck(connectivityCheckName, r.checkConnectionWithOtherSide()))
+		report.Checks = append(report.Checks, newSwitchoverCheck(resyncCheckName, r.checkResync()))
+	} else {
+		replicationManager, err := r.getReplicationManager()
+		if err != nil {
+			report.Checks = append(report.Checks, newSwitchoverCheck(autofollowRulesCheckName, err))
+		} else {
+			report.Checks = append(report.Checks, r.checkAutofollowRules(replicationManager))
+			lagCheck, catchUp := checkReplicationLag(replicationManager, replicationLagSampleInterval)
+			report.Checks = append(report.Checks, lagCheck, checkDocumentsCount(replicationManager))
+			report.EstimatedCatchUpSeconds = catchUp
+		}
+		if r.cr.Spec.DbaasAdapter != nil {
+			report.Checks = append(report.Checks, newSwitchoverCheck(usersRecoveryCheckName, r.checkUsersRecoveryReadiness()))
+		}
+	}
+	report.Checks = append(report.Checks, newSwitchoverCheck(clientServicesCheckName, r.checkClientServices()))
+
+	report.Ready = true
+	for _, check := range report.Checks {
+		report.Ready = report.Ready && check.Passed
+	}
+	report.Time = time.Now().UTC().Format(time.RFC3339)
+	r.logger.Info(fmt.Sprintf("Switchover dry-run to [%s] mode is finished, ready: %t", targetMode, report.Ready))
+	return report
+}
+
+func (r DisasterRecoveryReconciler) updateSwitchoverReport(report opensearchservice.SwitchoverReport) error {
+	statusUpdater := util.NewStatusUpdater(r.reconciler.Client, r.cr)
+	return statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		instance.Status.DisasterRecoveryStatus.SwitchoverReport = &report
+	})
+}
+
+func (r DisasterRecoveryReconciler) checkAutofollowRules(replicationManager ReplicationManager) opensearchservice.SwitchoverCheck {
+	status, err := disasterrecovery.NewReplicationCheckerWithClient(replicationManager.restClient, replicationManager.matches).
+		CheckReplication()
+	if err != nil {
+		return newSwitchoverCheck(autofollowRulesCheckName, err)
+	}
+	if status != disasterrecovery.UP {
+		return newSwitchoverCheck(autofollowRulesCheckName, fmt.Errorf("replication is %s", status))
+	}
+	return newSwitchoverCheck(autofollowRulesCheckName, nil)
+}
+
+// checkUsersRecoveryReadiness checks that DBaaS aggregator and adapter required for users recovery are available
+func (r DisasterRecoveryReconciler) checkUsersRecoveryReadiness() error {
+	statusCode, _, err := r.buildAggregatorRestClient().SendRequest(http.MethodGet, "health", nil)
+	if err != nil {
+		return fmt.Errorf("DBaaS aggregator is not available: %w", err)
+	}
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("DBaaS aggregator is not available: [%d] status code", statusCode)
+	}
+	progress, err := getUsersRecoveryProgress(r.buildAdapterRestClient())
+	if err != nil {
+		return fmt.Errorf("DBaaS adapter is not available: %w", err)
+	}
+	if progress.State == usersRecoveryRunningState {
+		return fmt.Errorf("users recovery is already running")
+	}
+	return nil
+}
+
+// checkResync checks that the other side has been resynchronized after failover and contains actual data
+func (r DisasterRecoveryReconciler) checkResync() error {
+	failoverStatus := r.cr.Status.DisasterRecoveryStatus.Failover
+	if failoverStatus != nil && failoverStatus.ResyncRequired {
+		return fmt.Errorf("the other side has not been resynchronized after failover at %s", failoverStatus.Time)
+	}
+	return nil
+}
+
+func (r DisasterRecoveryReconciler) checkClientServices() error {
+	names := []string{r.cr.Name}
+	if len(r.opensearchGKEServiceName) != 0 {
+		names = append(names, r.opensearchGKEServiceName)
+	}
+	for _, name := range names {
+		if _, err := r.reconciler.findService(name, r.cr.Namespace, r.logger); err != nil {
+			return fmt.Errorf("unable to find [%s] client service: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// checkReplicationLag measures the difference between leader and follower checkpoints twice
+// and estimates the time of the final catch-up by the follower progress between measurements
+func checkReplicationLag(replicationManager ReplicationManager, sampleInterval time.Duration) (opensearchservice.SwitchoverCheck, *int64) {
+	indexNames, err := replicationManager.GetRulesIndices()
+	if err != nil {
+		return newSwitchoverCheck(replicationLagCheckName, err), nil
+	}
+	before, failedIndices, err := replicationManager.getReplicationProgress(indexNames)
+	if err != nil {
+		return newSwitchoverCheck(replicationLagCheckName, err), nil
+	}
+	if len(failedIndices) > 0 {
+		sort.Strings(failedIndices)
+		return newSwitchoverCheck(replicationLagCheckName,
+			fmt.Errorf("replication is not running for indices: %s", joinIndices(failedIndices))), nil
+	}
+	time.Sleep(sampleInterval)
+	after, _, err := replicationManager.getReplicationProgress(indexNames)
+	if err != nil {
+		return newSwitchoverCheck(replicationLagCheckName, err), nil
+	}
+	lag, catchUp := estimateCatchUp(before, after, sampleInterval)
+	if catchUp == nil {
+		return newSwitchoverCheck(replicationLagCheckName,
+			fmt.Errorf("follower indices are %d operations behind and replication does not progress", lag)), nil
+	}
+	check := newSwitchoverCheck(replicationLagCheckName, nil)
+	check.Message = fmt.Sprintf("follower indices are %d operations behind", lag)
+	return check, catchUp
+}
+
+// estimateCatchUp returns the current replication lag in operations and the estimated time in seconds
+// to replicate it. The time is nil if there is a lag but followers did not progress during the interval.
+func estimateCatchUp(before map[string]IndexDetails, after map[string]IndexDetails, interval time.Duration) (int64, *int64) {
+	var lag, progress int64
+	for index, details := range after {
+		if details.LeaderCheckpoint > details.FollowerCheckpoint {
+			lag += int64(details.LeaderCheckpoint - details.FollowerCheckpoint)
+		}
+		if previous, ok := before[index]; ok && details.FollowerCheckpoint > previous.FollowerCheckpoint {
+			progress += int64(details.FollowerCheckpoint - previous.FollowerCheckpoint)
+		}
+	}
+	seconds := int64(0)
+	if lag == 0 {
+		return lag, &seconds
+	}
+	if progress == 0 {
+		return lag, nil
+	}
+	seconds = int64(math.Ceil(float64(lag) * interval.Seconds() / float64(progress)))
+	return lag, &seconds
+}
+
+// checkDocumentsCount compares the number of documents in follower indices and leader ones
+func checkDocumentsCount(replicationManager ReplicationManager) opensearchservice.SwitchoverCheck {
+	indexNames, err := replicationManager.GetRulesIndices()
+	if err != nil {
+		return newSwitchoverCheck(documentsCountCheckName, err)
+	}
+	var mismatchedIndices []string
+	for _, index := range indexNames {
+		followerCount, err := replicationManager.getDocumentsCount(index)
+		if err != nil {
+			return newSwitchoverCheck(documentsCountCheckName, err)
+		}
+		leaderCount, err := replicationManager.getDocumentsCount(leaderIndex(index))
+		if err != nil {
+			return newSwitchoverCheck(documentsCountCheckName, err)
+		}
+		if followerCount != leaderCount {
+			mismatchedIndices = append(mismatchedIndices, index)
+		}
+	}
+	if len(mismatchedIndices) > 0 {
+		sort.Strings(mismatchedIndices)
+		return newSwitchoverCheck(documentsCountCheckName,
+			fmt.Errorf("number of documents differs for indices: %s", joinIndices(mismatchedIndices)))
+	}
+	check := newSwitchoverCheck(documentsCountCheckName, nil)
+	check.Message = fmt.Sprintf("number of documents is equal for %d indices", len(indexNames))
+	return check
+}
+
+func newSwitchoverCheck(name string, err error) opensearchservice.SwitchoverCheck {
+	check := opensearchservice.SwitchoverCheck{Name: name, Passed: err == nil}
+	if err != nil {
+		check.Message = err.Error()
+	}
+	return check
+}
+
+// joinIndices returns the list of indices limited by maxReportedIndices to keep the status short
+func joinIndices(indices []string) string {
+	if len(indices) > maxReportedIndices {
+		return fmt.Sprintf("%s and %d more", strings.Join(indices[:maxReportedIndices], ", "), len(indices)-maxReportedIndices)
+	}
+	return strings.Join(indices, ", ")
+}
@@ -0,0 +1,175 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEstimateCatchUp(t *testing.T) {
+	before := map[string]IndexDetails{
+		"a": {LeaderCheckpoint: 100, FollowerCheckpoint: 50},
+		"b": {LeaderCheckpoint: 10, FollowerCheckpoint: 10},
+	}
+	after := map[string]IndexDetails{
+		"a": {LeaderCheckpoint: 120, FollowerCheckpoint: 70},
+		"b": {LeaderCheckpoint: 10, FollowerCheckpoint: 10},
+	}
+	lag, seconds := estimateCatchUp(before, after, 5*time.Second)
+	if lag != 50 {
+		t.Errorf("expected lag 50, got %d", lag)
+	}
+	// 20 operations are replicated in 5 seconds, so 50 operations require 12.5 seconds
+	if seconds == nil || *seconds != 13 {
+		t.Errorf("expected 13 seconds to catch up, got %v", seconds)
+	}
+}
+
+func TestEstimateCatchUp_NoLag(t *testing.T) {
+	details := map[string]IndexDetails{"a": {LeaderCheckpoint: 10, FollowerCheckpoint: 10}}
+	lag, seconds := estimateCatchUp(details, details, 5*time.Second)
+	if lag != 0 || seconds == nil || *seconds != 0 {
+		t.Errorf("expected no lag, got %d and %v", lag, seconds)
+	}
+}
+
+func TestEstimateCatchUp_StuckReplication(t *testing.T) {
+	details := map[string]IndexDetails{"a": {LeaderCheckpoint: 20, FollowerCheckpoint: 10}}
+	lag, seconds := estimateCatchUp(details, details, 5*time.Second)
+	if lag != 10 || seconds != nil {
+		t.Errorf("expected lag without estimation, got %d and %v", lag, seconds)
+	}
+}
+
+func TestCheckDocumentsCount_ReportsMismatchedIndices(t *testing.T) {
+	responses := map[string]string{
+		"/_cat/indices/*":               `[{"index":"equal"},{"index":"behind"}]`,
+		"/equal/_count":                 `{"count":10}`,
+		"/leader-cluster:equal/_count":  `{"count":10}`,
+		"/behind/_count":                `{"count":8}`,
+		"/leader-cluster:behind/_count": `{"count":9}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+	replicationManager := NewReplicationManager(*util.NewRestClient(server.URL, http.Client{}, util.Credentials{}), "",
+		[]ReplicationRule{{Patterns: []string{"*"}}}, logr.Discard())
+
+	check := checkDocumentsCount(*replicationManager)
+	if check.Passed {
+		t.Fatalf("expected failed check, got %+v", check)
+	}
+	if !strings.Contains(check.Message, "behind") || strings.Contains(check.Message, "equal") {
+		t.Errorf("unexpected message: %s", check.Message)
+	}
+}
+
+func TestJoinIndices_LimitsNumberOfIndices(t *testing.T) {
+	indices := make([]string, maxReportedIndices+2)
+	for i := range indices {
+		indices[i] = "index"
+	}
+	if message := joinIndices(indices); !strings.HasSuffix(message, "and 2 more") {
+		t.Errorf("unexpected message: %s", message)
+	}
+}
+
+func newConnectionCheckReconciler(disasterRecovery *opensearchservice.DisasterRecovery, remoteCluster string) DisasterRecoveryReconciler {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "opensearch-replication-config", Namespace: "opensearch-service"},
+		Data:       map[string]string{replicationRemoteServiceKey: remoteCluster},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote-credentials", Namespace: "opensearch-service"},
+		Data:       map[string][]byte{"username": []byte("remote"), "password": []byte("secret")},
+	}
+	disasterRecovery.ConfigMapName = configMap.Name
+	return DisasterRecoveryReconciler{
+		cr: &opensearchservice.OpenSearchService{
+			ObjectMeta: metav1.ObjectMeta{Name: "opensearch", Namespace: "opensearch-service"},
+			Spec:       opensearchservice.OpenSearchServiceSpec{DisasterRecovery: disasterRecovery},
+		},
+		logger:     logr.Discard(),
+		reconciler: &OpenSearchServiceReconciler{Client: fake.NewClientBuilder().WithObjects(configMap, secret).Build()},
+	}
+}
+
+func TestCheckConnectionWithOtherSide_UsesConfigurationSyncCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "remote" || password != "secret" || r.URL.Path != "/_cluster/health" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"green"}`))
+	}))
+	defer server.Close()
+	configurationSync := &opensearchservice.ConfigurationSync{RemoteUrl: server.URL + "/", SecretName: "remote-credentials"}
+	reconciler := newConnectionCheckReconciler(&opensearchservice.DisasterRecovery{ConfigurationSync: configurationSync}, "")
+	if err := reconciler.checkConnectionWithOtherSide(); err != nil {
+		t.Errorf("expected connection with other side, got %v", err)
+	}
+
+	configurationSync.SecretName = "absent"
+	if err := reconciler.checkConnectionWithOtherSide(); err == nil {
+		t.Errorf("expected failed check without remote credentials")
+	}
+}
+
+func TestCheckConnectionWithOtherSide_DoesNotSendDataToReplicationService(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	received := make(chan int, 1)
+	go func() {
+		connection, err := listener.Accept()
+		if err != nil {
+			received <- -1
+			return
+		}
+		defer connection.Close()
+		_ = connection.SetReadDeadline(time.Now().Add(time.Second))
+		buffer := make([]byte, 16)
+		n, _ := connection.Read(buffer)
+		received <- n
+	}()
+	reconciler := newConnectionCheckReconciler(&opensearchservice.DisasterRecovery{}, listener.Addr().String())
+	if err = reconciler.checkConnectionWithOtherSide(); err != nil {
+		t.Errorf("expected connection with other side, got %v", err)
+	}
+	if n := <-received; n != 0 {
+		t.Errorf("expected no data sent to replication service, got %d bytes", n)
+	}
+
+	_ = listener.Close()
+	if err = reconciler.checkConnectionWithOtherSide(); err == nil {
+		t.Errorf("expected failed check for closed replication service")
+	}
+}
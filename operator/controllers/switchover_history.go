@@ -0,0 +1,129 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	switchoverHistoryConfigMapTemplate = "%s-switchover-history"
+	switchoverHistoryKey               = "history"
+	maxSwitchoverHistory               = 10
+	maxAuditedSwitchovers              = 100
+	replicationCheckPassed             = "passed"
+	replicationCheckFailed             = "failed"
+	replicationCheckSkipped            = "skipped"
+)
+
+func newSwitchoverHistoryEntry(mode string) *opensearchservice.SwitchoverHistoryEntry {
+	return &opensearchservice.SwitchoverHistoryEntry{
+		Mode:             mode,
+		StartTime:        time.Now().UTC().Format(time.RFC3339),
+		ReplicationCheck: replicationCheckSkipped,
+		Attempts:         1,
+	}
+}
+
+// finishSwitchoverHistoryEntry fills the result of the switchover in the entry
+func finishSwitchoverHistoryEntry(entry *opensearchservice.SwitchoverHistoryEntry, result string,
+	usersRecoveryState string, switchoverErr error) {
+	entry.EndTime = time.Now().UTC().Format(time.RFC3339)
+	entry.Result = result
+	entry.UsersRecoveryState = usersRecoveryState
+	if switchoverErr != nil {
+		entry.Error = switchoverErr.Error()
+	}
+}
+
+func replicationCheckResult(err error) string {
+	if err != nil {
+		return replicationCheckFailed
+	}
+	return replicationCheckPassed
+}
+
+// recordSwitchover adds the entry to the history in status keeping only the last maxSwitchoverHistory entries
+// and mirrors it to the config map keeping the last maxAuditedSwitchovers entries
+func (r DisasterRecoveryReconciler) recordSwitchover(entry opensearchservice.SwitchoverHistoryEntry) {
+	statusUpdater := util.NewStatusUpdater(r.reconciler.Client, r.cr)
+	err := statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		instance.Status.DisasterRecoveryStatus.History =
+			appendSwitchoverHistory(instance.Status.DisasterRecoveryStatus.History, entry, maxSwitchoverHistory)
+	})
+	if err != nil {
+		r.logger.Error(err, "Unable to record switchover in status")
+	}
+	if err = r.mirrorSwitchover(entry); err != nil {
+		r.logger.Error(err, "Unable to record switchover in history config map")
+	}
+}
+
+// mirrorSwitchover appends the entry to the config map which is not owned by the custom resource
+// to keep the history for audit after the custom resource is removed
+func (r DisasterRecoveryReconciler) mirrorSwitchover(entry opensearchservice.SwitchoverHistoryEntry) error {
+	name := fmt.Sprintf(switchoverHistoryConfigMapTemplate, r.cr.Name)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := r.reconciler.findConfigMap(name, r.cr.Namespace, r.logger)
+		if errors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: r.cr.Namespace}}
+		} else if err != nil {
+			return err
+		}
+		var history []opensearchservice.SwitchoverHistoryEntry
+		if data := configMap.Data[switchoverHistoryKey]; data != "" {
+			if err = json.Unmarshal([]byte(data), &history); err != nil {
+				r.logger.Error(err, "Unable to parse switchover history, it is started from scratch")
+				history = nil
+			}
+		}
+		data, err := json.MarshalIndent(appendSwitchoverHistory(history, entry, maxAuditedSwitchovers), "", "  ")
+		if err != nil {
+			return err
+		}
+		configMap.Data = map[string]string{switchoverHistoryKey: string(data)}
+		if configMap.ResourceVersion == "" {
+			return r.reconciler.Client.Create(context.TODO(), configMap)
+		}
+		return r.reconciler.Client.Update(context.TODO(), configMap)
+	})
+}
+
+// appendSwitchoverHistory adds the entry to the history. The retry of the failed switchover to the same mode
+// replaces the last entry keeping the start time of the first attempt and counting attempts.
+func appendSwitchoverHistory(history []opensearchservice.SwitchoverHistoryEntry,
+	entry opensearchservice.SwitchoverHistoryEntry, limit int) []opensearchservice.SwitchoverHistoryEntry {
+	if last := len(history) - 1; last >= 0 && history[last].Mode == entry.Mode && history[last].Result == "failed" {
+		entry.StartTime = history[last].StartTime
+		entry.Attempts = max(history[last].Attempts, 1) + 1
+		history[last] = entry
+		return history
+	}
+	history = append(history, entry)
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	return history
+}
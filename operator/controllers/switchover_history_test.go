@@ -0,0 +1,99 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAppendSwitchoverHistory_KeepsLastEntries(t *testing.T) {
+	var history []opensearchservice.SwitchoverHistoryEntry
+	for i := 0; i < 5; i++ {
+		history = appendSwitchoverHistory(history, opensearchservice.SwitchoverHistoryEntry{StartTime: fmt.Sprint(i)}, 3)
+	}
+	if len(history) != 3 || history[0].StartTime != "2" || history[2].StartTime != "4" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+func TestAppendSwitchoverHistory_UpdatesLastEntryOnRetry(t *testing.T) {
+	history := []opensearchservice.SwitchoverHistoryEntry{
+		{Mode: "standby", StartTime: "1", Result: "done", Attempts: 1},
+		{Mode: "active", StartTime: "2", Result: "failed", Attempts: 1},
+	}
+	history = appendSwitchoverHistory(history,
+		opensearchservice.SwitchoverHistoryEntry{Mode: "active", StartTime: "3", Result: "failed", Attempts: 1}, 10)
+	history = appendSwitchoverHistory(history,
+		opensearchservice.SwitchoverHistoryEntry{Mode: "active", StartTime: "4", EndTime: "5", Result: "done", Attempts: 1}, 10)
+	if len(history) != 2 || history[1].StartTime != "2" || history[1].EndTime != "5" ||
+		history[1].Result != "done" || history[1].Attempts != 3 {
+		t.Errorf("expected retries to update the last entry, got %+v", history)
+	}
+
+	history = appendSwitchoverHistory(history,
+		opensearchservice.SwitchoverHistoryEntry{Mode: "active", StartTime: "6", Result: "done", Attempts: 1}, 10)
+	history = appendSwitchoverHistory(history,
+		opensearchservice.SwitchoverHistoryEntry{Mode: "standby", StartTime: "7", Result: "failed", Attempts: 1}, 10)
+	if len(history) != 4 || history[2].StartTime != "6" || history[3].Mode != "standby" {
+		t.Errorf("expected new entries after successful switchover and for other mode, got %+v", history)
+	}
+}
+
+func TestFinishSwitchoverHistoryEntry(t *testing.T) {
+	entry := newSwitchoverHistoryEntry("active")
+	entry.ReplicationCheck = replicationCheckResult(errors.New("replication is down"))
+	finishSwitchoverHistoryEntry(entry, "failed", usersRecoveryDoneState, errors.New("replication is down"))
+	if entry.Mode != "active" || entry.Result != "failed" || entry.ReplicationCheck != replicationCheckFailed ||
+		entry.Error != "replication is down" || entry.UsersRecoveryState != usersRecoveryDoneState || entry.EndTime == "" {
+		t.Errorf("unexpected history entry: %+v", entry)
+	}
+}
+
+func TestMirrorSwitchover_AppendsToConfigMap(t *testing.T) {
+	reconciler := DisasterRecoveryReconciler{
+		cr: &opensearchservice.OpenSearchService{
+			ObjectMeta: metav1.ObjectMeta{Name: "opensearch", Namespace: "opensearch-service"},
+		},
+		logger:     logr.Discard(),
+		reconciler: &OpenSearchServiceReconciler{Client: fake.NewClientBuilder().Build()},
+	}
+	for _, mode := range []string{"standby", "active"} {
+		if err := reconciler.mirrorSwitchover(opensearchservice.SwitchoverHistoryEntry{Mode: mode, Result: "done"}); err != nil {
+			t.Fatalf("unable to mirror switchover: %v", err)
+		}
+	}
+
+	configMap, err := reconciler.reconciler.findConfigMap("opensearch-switchover-history", "opensearch-service", logr.Discard())
+	if err != nil {
+		t.Fatalf("unable to find history config map: %v", err)
+	}
+	var history []opensearchservice.SwitchoverHistoryEntry
+	if err = json.Unmarshal([]byte(configMap.Data[switchoverHistoryKey]), &history); err != nil {
+		t.Fatalf("unable to parse history: %v", err)
+	}
+	if len(history) != 2 || history[0].Mode != "standby" || history[1].Mode != "active" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
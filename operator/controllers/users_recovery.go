@@ -0,0 +1,94 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+)
+
+const (
+	usersRecoveryStatePath = "api/v2/dbaas/adapter/opensearch/users/restore-password/state"
+	usersRecoveryRetryPath = "api/v2/dbaas/adapter/opensearch/users/restore-password/retry"
+	// maxUsersRecoveryRetries is the number of retries of failed users during one switchover
+	maxUsersRecoveryRetries = 1
+)
+
+// usersRecoveryProgress is the state of users recovery returned by DBaaS adapter
+type usersRecoveryProgress struct {
+	State string `json:"state"`
+	opensearchservice.UsersRecoveryStatus
+}
+
+// parseUsersRecoveryProgress parses the progress of users recovery.
+// Previous versions of DBaaS adapter return only the state as a plain string, so the progress is empty for them.
+func parseUsersRecoveryProgress(response []byte) usersRecoveryProgress {
+	var progress usersRecoveryProgress
+	if err := json.Unmarshal(response, &progress); err != nil || progress.State == "" {
+		return usersRecoveryProgress{State: strings.TrimSpace(string(response))}
+	}
+	return progress
+}
+
+// getUsersRecoveryProgress requests the progress of users recovery from DBaaS adapter
+func getUsersRecoveryProgress(adapterRestClient *util.RestClient) (usersRecoveryProgress, error) {
+	statusCode, response, err := adapterRestClient.SendRequest(http.MethodGet, usersRecoveryStatePath, nil)
+	if err != nil {
+		return usersRecoveryProgress{}, err
+	}
+	if statusCode != http.StatusOK {
+		return usersRecoveryProgress{}, fmt.Errorf("[%d] %s", statusCode, string(response))
+	}
+	return parseUsersRecoveryProgress(response), nil
+}
+
+// retryFailedUsers runs recovery of users failed during the last recovery in DBaaS adapter
+func retryFailedUsers(adapterRestClient *util.RestClient) error {
+	statusCode, response, err := adapterRestClient.SendRequest(http.MethodPost, usersRecoveryRetryPath, nil)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("[%d] %s", statusCode, string(response))
+	}
+	return nil
+}
+
+// updateUsersRecoveryProgress updates the progress of users recovery in status if it is changed
+func (r DisasterRecoveryReconciler) updateUsersRecoveryProgress(previous *opensearchservice.UsersRecoveryStatus,
+	progress usersRecoveryProgress) *opensearchservice.UsersRecoveryStatus {
+	if progress.Total == 0 && progress.Batches == 0 {
+		return previous
+	}
+	current := progress.UsersRecoveryStatus
+	if previous != nil && reflect.DeepEqual(*previous, current) {
+		return previous
+	}
+	statusUpdater := util.NewStatusUpdater(r.reconciler.Client, r.cr)
+	err := statusUpdater.UpdateStatusWithRetry(func(instance *opensearchservice.OpenSearchService) {
+		instance.Status.DisasterRecoveryStatus.UsersRecovery = current.DeepCopy()
+	})
+	if err != nil {
+		r.logger.Error(err, "Unable to update users recovery progress")
+		return previous
+	}
+	return &current
+}
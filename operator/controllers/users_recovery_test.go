@@ -0,0 +1,41 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package controllers
+
+import (
+	"testing"
+)
+
+func TestParseUsersRecoveryProgress_Json(t *testing.T) {
+	progress := parseUsersRecoveryProgress([]byte(`{"state":"running","total":250,"processed":100,"currentBatch":2,"batches":3,
+		"failedUsers":[{"username":"user1","reason":"invalid user"}]}`))
+	if progress.State != usersRecoveryRunningState || progress.Total != 250 || progress.Processed != 100 ||
+		progress.CurrentBatch != 2 || progress.Batches != 3 {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+	if len(progress.FailedUsers) != 1 || progress.FailedUsers[0].Username != "user1" ||
+		progress.FailedUsers[0].Reason != "invalid user" {
+		t.Errorf("unexpected failed users: %+v", progress.FailedUsers)
+	}
+}
+
+func TestParseUsersRecoveryProgress_PlainState(t *testing.T) {
+	progress := parseUsersRecoveryProgress([]byte("done"))
+	if progress.State != usersRecoveryDoneState || progress.Total != 0 || len(progress.FailedUsers) != 0 {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+}
@@ -16,12 +16,16 @@ package disasterrecovery
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"net/http"
 	"os"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+	"time"
 )
 
 const (
@@ -31,8 +35,14 @@ const (
 	DOWN                = "down"
 	UP                  = "up"
 	replicationName     = "dr-replication"
+	serverAddress       = ":8069"
+	// localServerAddress serves the disaster recovery daemon in the same pod without TLS and authentication
+	localServerAddress = "127.0.0.1:8070"
 )
 
+// statusSeverity orders replication states from the best to the worst one
+var statusSeverity = map[string]int{UP: 0, DEGRADED: 1, DOWN: 2}
+
 var (
 	verbose = GetEnv("DEBUG", "false")
 	log     = logf.Log.WithName("dr_health_server")
@@ -40,27 +50,82 @@ var (
 
 type ServerContext struct {
 	replicationChecker ReplicationChecker
+	watcherInfo        *WatcherInfo
+	switchoverDryRun   SwitchoverDryRun
+	fencing            Fencing
+	security           ServerSecurity
+	snapshotShipping   SnapshotShipping
 }
 
 type ClusterState struct {
-	Status string `json:"status"`
+	Status           string                                    `json:"status"`
+	Details          *ReplicationDetails                       `json:"details,omitempty"`
+	SnapshotShipping *opensearchservice.SnapshotShippingStatus `json:"snapshotShipping,omitempty"`
+}
+
+type DryRunRequest struct {
+	Request string `json:"request"`
 }
 
-func StartServer(replicationChecker ReplicationChecker) error {
-	serverContext := ServerContext{replicationChecker: replicationChecker}
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+func StartServer(replicationChecker ReplicationChecker, watcherInfo *WatcherInfo, switchoverDryRun SwitchoverDryRun,
+	fencing Fencing, security ServerSecurity, snapshotShipping SnapshotShipping) error {
+	if err := security.Validate(); err != nil {
+		return err
+	}
+	serverContext := ServerContext{
+		replicationChecker: replicationChecker,
+		watcherInfo:        watcherInfo,
+		switchoverDryRun:   switchoverDryRun,
+		fencing:            fencing,
+		security:           security,
+		snapshotShipping:   snapshotShipping,
+	}
 	server := &http.Server{
-		Addr:    ":8069",
+		Addr:    serverAddress,
 		Handler: ServerHandlers(serverContext),
 	}
-	return server.ListenAndServe()
+	if security.Enabled() {
+		localContext := serverContext
+		localContext.security = ServerSecurity{}
+		go func() {
+			localServer := &http.Server{
+				Addr:    localServerAddress,
+				Handler: ServerHandlers(localContext),
+			}
+			if err := localServer.ListenAndServe(); err != nil {
+				log.Error(err, "Local disaster recovery REST server is stopped")
+			}
+		}()
+	}
+	if !security.TLSEnabled {
+		return server.ListenAndServe()
+	}
+	tlsConfig, err := security.ServerTLSConfig()
+	if err != nil {
+		return err
+	}
+	server.TLSConfig = tlsConfig
+	return server.ListenAndServeTLS("", "")
 }
 
 func ServerHandlers(serverContext ServerContext) http.Handler {
 	r := mux.NewRouter()
-	r.Handle("/healthz", http.HandlerFunc(serverContext.GetClusterHealthStatus())).Methods("GET")
+	r.Handle("/healthz", serverContext.authenticate(serverContext.GetClusterHealthStatus())).Methods("GET")
+	r.Handle("/switchover/dry-run", serverContext.authenticate(serverContext.RequestSwitchoverDryRun())).Methods("POST")
+	r.Handle("/switchover/dry-run", serverContext.authenticate(serverContext.GetSwitchoverDryRunReport())).Methods("GET")
+	// Fencing requests are authenticated by the token shared for split-brain protection
+	r.Handle("/fencing", http.HandlerFunc(serverContext.GetFencingState())).Methods("GET")
 	return JsonContentType(handlers.CompressHandler(r))
 }
 
+func (serverContext ServerContext) authenticate(handler func(w http.ResponseWriter, r *http.Request)) http.Handler {
+	return serverContext.security.Authenticate(http.HandlerFunc(handler))
+}
+
 func (serverContext ServerContext) GetClusterHealthStatus() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		mode, ok := r.URL.Query()["mode"]
@@ -81,6 +146,22 @@ func (serverContext ServerContext) GetClusterHealthStatus() func(w http.Response
 			sendFailedHealthResponse(w)
 			return
 		}
+		if serverContext.snapshotShipping != nil {
+			shippingStatus, shippingInterval, err := serverContext.snapshotShipping.Status()
+			if err == nil {
+				clusterState := ClusterState{Status: snapshotShippingHealth(shippingStatus, shippingInterval, time.Now())}
+				if r.URL.Query().Get("verbose") == "true" {
+					clusterState.SnapshotShipping = shippingStatus
+				}
+				sendSuccessfulResponse(w, clusterState)
+				return
+			}
+			if !errors.Is(err, ErrSnapshotShippingDisabled) {
+				log.Error(err, "Unable to get snapshot shipping status")
+				sendFailedHealthResponse(w)
+				return
+			}
+		}
 		status, err := serverContext.replicationChecker.CheckReplication()
 		if err != nil {
 			sendFailedHealthResponse(w)
@@ -88,10 +169,83 @@ func (serverContext ServerContext) GetClusterHealthStatus() func(w http.Response
 		}
 
 		clusterState := ClusterState{Status: status}
+		if r.URL.Query().Get("verbose") == "true" {
+			details, err := serverContext.replicationChecker.GetReplicationDetails()
+			if err != nil {
+				log.Error(err, "Unable to collect replication details")
+			}
+			if serverContext.watcherInfo != nil {
+				if lastRestart := serverContext.watcherInfo.LastRestart(); !lastRestart.IsZero() {
+					details.LastWatcherRestart = lastRestart.UTC().Format(time.RFC3339)
+				}
+			}
+			clusterState.Details = &details
+		}
 		sendSuccessfulResponse(w, clusterState)
 	}
 }
 
+// RequestSwitchoverDryRun triggers switchover checks without side effects,
+// the report is available in the custom resource status when checks are finished
+func (serverContext ServerContext) RequestSwitchoverDryRun() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		request, err := serverContext.switchoverDryRun.Request()
+		if err != nil {
+			log.Error(err, "Unable to request switchover dry-run")
+			sendResponse(w, InternalServerError, ErrorResponse{Message: err.Error()})
+			return
+		}
+		sendResponse(w, http.StatusAccepted, DryRunRequest{Request: request})
+	}
+}
+
+func (serverContext ServerContext) GetSwitchoverDryRunReport() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report, err := serverContext.switchoverDryRun.Report()
+		if err != nil {
+			log.Error(err, "Unable to get switchover dry-run report")
+			sendResponse(w, InternalServerError, ErrorResponse{Message: err.Error()})
+			return
+		}
+		if report == nil {
+			sendResponse(w, http.StatusNotFound, ErrorResponse{Message: "switchover dry-run has not been performed yet"})
+			return
+		}
+		sendSuccessfulResponse(w, report)
+	}
+}
+
+// GetFencingState returns the epoch and mode of the current side to the other side
+// authenticated by the Bearer token shared between sides
+func (serverContext ServerContext) GetFencingState() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if serverContext.fencing == nil {
+			sendResponse(w, http.StatusNotFound, ErrorResponse{Message: ErrFencingDisabled.Error()})
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if err := serverContext.fencing.Authorize(token); err != nil {
+			switch {
+			case errors.Is(err, ErrFencingDisabled):
+				sendResponse(w, http.StatusNotFound, ErrorResponse{Message: err.Error()})
+			case errors.Is(err, ErrUnauthorized):
+				sendResponse(w, http.StatusUnauthorized, ErrorResponse{Message: err.Error()})
+			default:
+				log.Error(err, "Unable to authorize fencing request")
+				sendResponse(w, InternalServerError, ErrorResponse{Message: err.Error()})
+			}
+			return
+		}
+		state, err := serverContext.fencing.State()
+		if err != nil {
+			log.Error(err, "Unable to get fencing state")
+			sendResponse(w, InternalServerError, ErrorResponse{Message: err.Error()})
+			return
+		}
+		sendSuccessfulResponse(w, state)
+	}
+}
+
 func sendFailedHealthResponse(w http.ResponseWriter) {
 	response := ClusterState{
 		Status: DOWN,
@@ -0,0 +1,331 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package disasterrecovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+)
+
+// newOpenSearchStub emulates OpenSearch endpoints used by the replication checker
+// with one paused index and one syncing index.
+func newOpenSearchStub() *httptest.Server {
+	responses := map[string]string{
+		"/_plugins/_replication/autofollow_stats": `{"autofollow_stats":[{"name":"dr-replication","pattern":"test*",
+			"num_success_start_replication":2,"num_failed_start_replication":0,"failed_indices":[]}]}`,
+		"/_cat/indices/test*":                    `[{"index":"test-1","health":"green"},{"index":"test-2","health":"red"}]`,
+		"/test*":                                 `{"test-1":{},"test-2":{},".hidden":{}}`,
+		"/_plugins/_replication/test-1/_status":  `{"status":"SYNCING","syncing_details":{"leader_checkpoint":5,"follower_checkpoint":3,"seq_no":4}}`,
+		"/_plugins/_replication/test-2/_status":  `{"status":"PAUSED","reason":"network issue"}`,
+		"/_plugins/_replication/.hidden/_status": `{"status":"FAILED"}`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+}
+
+func TestGetClusterHealthStatus_DefaultResponseHasNoDetails(t *testing.T) {
+	server := newOpenSearchStub()
+	defer server.Close()
+	checker := NewReplicationCheckerWithClient(*util.NewRestClient(server.URL, http.Client{}, util.Credentials{}), nil)
+	handler := ServerHandlers(ServerContext{replicationChecker: checker, watcherInfo: NewWatcherInfo()})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz?mode=standby", nil))
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("could not parse response: %v", err)
+	}
+	if _, ok := response["details"]; ok {
+		t.Errorf("expected no details in default response, got %s", recorder.Body.String())
+	}
+	if response["status"] != DEGRADED {
+		t.Errorf("expected %q status, got %v", DEGRADED, response["status"])
+	}
+}
+
+func TestGetClusterHealthStatus_VerboseResponseContainsDetails(t *testing.T) {
+	server := newOpenSearchStub()
+	defer server.Close()
+	checker := NewReplicationCheckerWithClient(*util.NewRestClient(server.URL, http.Client{}, util.Credentials{}), nil)
+	watcherInfo := NewWatcherInfo()
+	restartTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	watcherInfo.RecordRestart(restartTime)
+	handler := ServerHandlers(ServerContext{replicationChecker: checker, watcherInfo: watcherInfo})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz?mode=standby&verbose=true", nil))
+
+	var response ClusterState
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("could not parse response: %v", err)
+	}
+	details := response.Details
+	if details == nil {
+		t.Fatalf("expected details in verbose response, got %s", recorder.Body.String())
+	}
+	if len(details.AutofollowRules) != 1 || details.AutofollowRules[0].Pattern != "test*" {
+		t.Errorf("unexpected autofollow rules: %+v", details.AutofollowRules)
+	}
+	if len(details.UnhealthyIndices) != 1 || details.UnhealthyIndices[0] != "test-2" {
+		t.Errorf("unexpected unhealthy indices: %v", details.UnhealthyIndices)
+	}
+	if len(details.PausedIndices) != 1 || details.PausedIndices[0] != "test-2" {
+		t.Errorf("unexpected paused indices: %v", details.PausedIndices)
+	}
+	if len(details.Indices) != 2 {
+		t.Errorf("expected two indices without service ones, got %v", details.Indices)
+	}
+	if syncing := details.Indices["test-1"].SyncingDetails; syncing == nil || syncing.LeaderCheckpoint != 5 {
+		t.Errorf("unexpected syncing details: %+v", syncing)
+	}
+	if details.LastWatcherRestart != restartTime.Format(time.RFC3339) {
+		t.Errorf("unexpected last watcher restart: %q", details.LastWatcherRestart)
+	}
+}
+
+func TestCheckReplication_WorstStatusOfAllRules(t *testing.T) {
+	responses := map[string]string{
+		"/_plugins/_replication/autofollow_stats": `{"autofollow_stats":[
+			{"name":"dr-replication","pattern":"a*","num_success_start_replication":1,"failed_indices":[]},
+			{"name":"dr-replication-b","pattern":"b*","num_success_start_replication":0,"failed_indices":["b-1"]},
+			{"name":"custom","pattern":"c*","num_success_start_replication":0,"failed_indices":["c-1"]}]}`,
+		"/_cat/indices/a*":                   `[{"index":"a-1","health":"green"}]`,
+		"/a*":                                `{"a-1":{}}`,
+		"/_plugins/_replication/a-1/_status": `{"status":"SYNCING"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+	checker := NewReplicationCheckerWithClient(*util.NewRestClient(server.URL, http.Client{}, util.Credentials{}), nil)
+
+	status, err := checker.CheckReplication()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != DOWN {
+		t.Errorf("expected %q status, got %q", DOWN, status)
+	}
+}
+
+func TestCheckReplication_IgnoresExcludedIndices(t *testing.T) {
+	responses := map[string]string{
+		"/_plugins/_replication/autofollow_stats": `{"autofollow_stats":[{"name":"dr-replication","pattern":"*",
+			"num_success_start_replication":0,"num_failed_start_replication":1,"failed_indices":["logs-1"]}]}`,
+		"/_cat/indices/*":                       `[{"index":"data-1","health":"green"},{"index":"logs-1","health":"red"}]`,
+		"/*":                                    `{"data-1":{},"logs-1":{}}`,
+		"/_plugins/_replication/data-1/_status": `{"status":"SYNCING"}`,
+		"/_plugins/_replication/logs-1/_status": `{"status":"FAILED"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+	watcherInfo := NewWatcherInfo()
+	watcherInfo.SetIndexMatcher(func(index string) bool {
+		return !strings.HasPrefix(index, "logs-")
+	})
+	checker := NewReplicationCheckerWithClient(*util.NewRestClient(server.URL, http.Client{}, util.Credentials{}),
+		watcherInfo.Matches)
+
+	status, err := checker.CheckReplication()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != UP {
+		t.Errorf("expected %q status, got %q", UP, status)
+	}
+	details, err := checker.GetReplicationDetails()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(details.FailedIndices) != 0 || len(details.UnhealthyIndices) != 0 {
+		t.Errorf("expected no failed and unhealthy indices, got %v and %v", details.FailedIndices, details.UnhealthyIndices)
+	}
+	if _, ok := details.Indices["logs-1"]; ok || len(details.Indices) != 1 {
+		t.Errorf("expected only replicated indices, got %v", details.Indices)
+	}
+}
+
+type switchoverDryRunStub struct {
+	requested bool
+	report    *opensearchservice.SwitchoverReport
+}
+
+func (stub *switchoverDryRunStub) Request() (string, error) {
+	stub.requested = true
+	return "request-1", nil
+}
+
+func (stub *switchoverDryRunStub) Report() (*opensearchservice.SwitchoverReport, error) {
+	return stub.report, nil
+}
+
+func TestSwitchoverDryRun_RequestAndReport(t *testing.T) {
+	stub := &switchoverDryRunStub{}
+	handler := ServerHandlers(ServerContext{switchoverDryRun: stub})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/switchover/dry-run", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected %d status code without report, got %d", http.StatusNotFound, recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/switchover/dry-run", nil))
+	if recorder.Code != http.StatusAccepted || !stub.requested {
+		t.Fatalf("expected dry-run to be requested, got %d status code", recorder.Code)
+	}
+	var request DryRunRequest
+	if err := json.Unmarshal(recorder.Body.Bytes(), &request); err != nil || request.Request != "request-1" {
+		t.Errorf("unexpected response: %s", recorder.Body.String())
+	}
+
+	stub.report = &opensearchservice.SwitchoverReport{Request: "request-1", TargetMode: "active", Ready: true}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/switchover/dry-run", nil))
+	var report opensearchservice.SwitchoverReport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+		t.Fatalf("could not parse response: %v", err)
+	}
+	if report.Request != "request-1" || !report.Ready {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+type fencingStub struct {
+	token string
+	state FencingState
+}
+
+func (stub fencingStub) Authorize(token string) error {
+	if token != stub.token {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+func (stub fencingStub) State() (FencingState, error) {
+	return stub.state, nil
+}
+
+func TestGetFencingState_RequiresToken(t *testing.T) {
+	stub := fencingStub{token: "secret", state: FencingState{Epoch: 3, Mode: "active"}}
+	handler := ServerHandlers(ServerContext{fencing: stub})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fencing", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d status code without token, got %d", http.StatusUnauthorized, recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/fencing", nil)
+	request.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(recorder, request)
+	var state FencingState
+	if err := json.Unmarshal(recorder.Body.Bytes(), &state); err != nil {
+		t.Fatalf("could not parse response: %v", err)
+	}
+	if recorder.Code != http.StatusOK || state != stub.state {
+		t.Errorf("unexpected response: %d %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestGetFencingState_NotFoundWithoutFencing(t *testing.T) {
+	handler := ServerHandlers(ServerContext{})
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fencing", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected %d status code, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+type snapshotShippingStub struct {
+	status *opensearchservice.SnapshotShippingStatus
+	err    error
+}
+
+func (stub snapshotShippingStub) Status() (*opensearchservice.SnapshotShippingStatus, time.Duration, error) {
+	return stub.status, time.Minute, stub.err
+}
+
+func TestGetClusterHealthStatus_SnapshotShipping(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		name   string
+		status *opensearchservice.SnapshotShippingStatus
+		health string
+	}{
+		{name: "nothing restored", status: nil, health: DOWN},
+		{name: "recent check", status: &opensearchservice.SnapshotShippingStatus{Mode: "standby", Snapshot: "dr-snapshot-1",
+			LastSyncTime: now.Format(time.RFC3339)}, health: UP},
+		{name: "failed restore", status: &opensearchservice.SnapshotShippingStatus{Mode: "standby", Snapshot: "dr-snapshot-1",
+			LastSyncTime: now.Format(time.RFC3339), Error: "restore failed"}, health: DEGRADED},
+		{name: "late check", status: &opensearchservice.SnapshotShippingStatus{Mode: "standby", Snapshot: "dr-snapshot-1",
+			LastSyncTime: now.Add(-time.Hour).Format(time.RFC3339)}, health: DEGRADED},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := ServerHandlers(ServerContext{snapshotShipping: snapshotShippingStub{status: test.status}})
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz?mode=standby&verbose=true", nil))
+			var state ClusterState
+			if err := json.Unmarshal(recorder.Body.Bytes(), &state); err != nil {
+				t.Fatalf("could not parse response: %v", err)
+			}
+			if recorder.Code != http.StatusOK || state.Status != test.health {
+				t.Errorf("expected %s health, got %d %s", test.health, recorder.Code, recorder.Body.String())
+			}
+			if test.status != nil && state.SnapshotShipping == nil {
+				t.Error("expected snapshot shipping status in verbose response")
+			}
+		})
+	}
+}
+
+func TestGetClusterHealthStatus_ReplicationWithoutSnapshotShipping(t *testing.T) {
+	server := newOpenSearchStub()
+	defer server.Close()
+	checker := NewReplicationCheckerWithClient(*util.NewRestClient(server.URL, http.Client{}, util.Credentials{}), nil)
+	handler := ServerHandlers(ServerContext{replicationChecker: checker,
+		snapshotShipping: snapshotShippingStub{err: ErrSnapshotShippingDisabled}})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz?mode=standby", nil))
+	var state ClusterState
+	if err := json.Unmarshal(recorder.Body.Bytes(), &state); err != nil {
+		t.Fatalf("could not parse response: %v", err)
+	}
+	if state.Status != DEGRADED {
+		t.Errorf("expected replication health to be checked, got %s", recorder.Body.String())
+	}
+}
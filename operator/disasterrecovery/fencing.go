@@ -0,0 +1,123 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disasterrecovery
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FencingTokenKey is the key of the token in the secret shared by both sides
+const FencingTokenKey = "token"
+
+var (
+	// ErrFencingDisabled is returned when split-brain protection is not enabled for the current side
+	ErrFencingDisabled = errors.New("fencing is not enabled")
+	// ErrUnauthorized is returned when the token of the fencing request does not match the shared one
+	ErrUnauthorized = errors.New("fencing token is invalid")
+)
+
+// FencingState is the epoch of the side with its Disaster Recovery mode.
+// The side with not empty conflict is fenced and does not serve clients even in `active` mode.
+type FencingState struct {
+	Epoch    int64  `json:"epoch"`
+	Mode     string `json:"mode"`
+	Conflict string `json:"conflict,omitempty"`
+}
+
+// IsActive checks whether the side serves clients in `active` mode
+func (state FencingState) IsActive() bool {
+	return state.Mode == "active" && state.Conflict == ""
+}
+
+// Fencing provides the fencing state of the current side to the other one
+type Fencing interface {
+	Authorize(token string) error
+	State() (FencingState, error)
+}
+
+// CustomResourceFencing reads the fencing state from the status of OpenSearchService custom resource
+// and the token from the secret specified in it
+type CustomResourceFencing struct {
+	client    client.Client
+	name      string
+	namespace string
+}
+
+func NewCustomResourceFencing(client client.Client, name string, namespace string) CustomResourceFencing {
+	return CustomResourceFencing{
+		client:    client,
+		name:      name,
+		namespace: namespace,
+	}
+}
+
+// Authorize checks that the token is equal to the one from the fencing secret
+func (f CustomResourceFencing) Authorize(token string) error {
+	instance, err := f.getCustomResource()
+	if err != nil {
+		return err
+	}
+	fencing := getFencing(instance)
+	if fencing == nil {
+		return ErrFencingDisabled
+	}
+	secret := &corev1.Secret{}
+	if err = f.client.Get(context.TODO(), types.NamespacedName{Name: fencing.SecretName, Namespace: f.namespace}, secret); err != nil {
+		return err
+	}
+	expected := secret.Data[FencingTokenKey]
+	if len(expected) == 0 || subtle.ConstantTimeCompare(expected, []byte(token)) != 1 {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// State returns the epoch and Disaster Recovery mode of the current side
+func (f CustomResourceFencing) State() (FencingState, error) {
+	instance, err := f.getCustomResource()
+	if err != nil {
+		return FencingState{}, err
+	}
+	if getFencing(instance) == nil {
+		return FencingState{}, ErrFencingDisabled
+	}
+	state := FencingState{Mode: instance.Status.DisasterRecoveryStatus.Mode}
+	if fencingStatus := instance.Status.DisasterRecoveryStatus.Fencing; fencingStatus != nil {
+		state.Epoch = fencingStatus.Epoch
+		state.Conflict = fencingStatus.Conflict
+	}
+	return state, nil
+}
+
+func (f CustomResourceFencing) getCustomResource() (*opensearchservice.OpenSearchService, error) {
+	instance := &opensearchservice.OpenSearchService{}
+	err := f.client.Get(context.TODO(), types.NamespacedName{Name: f.name, Namespace: f.namespace}, instance)
+	return instance, err
+}
+
+func getFencing(instance *opensearchservice.OpenSearchService) *opensearchservice.Fencing {
+	if instance.Spec.DisasterRecovery == nil || instance.Spec.DisasterRecovery.Fencing == nil ||
+		!instance.Spec.DisasterRecovery.Fencing.Enabled {
+		return nil
+	}
+	return instance.Spec.DisasterRecovery.Fencing
+}
@@ -22,7 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -31,9 +31,10 @@ import (
 
 const (
 	certificateFilePath           = "/certs/crt.pem"
-	catIndicesPath                = "_cat/indices?h=index,health&format=json"
+	catIndicesPattern             = "_cat/indices/%s?h=index,health&format=json"
 	indexReplicationStatusPattern = "_plugins/_replication/%s/_status"
 	failedStatus                  = "FAILED"
+	pausedStatus                  = "PAUSED"
 	opensearchHostEnvVar          = "OPENSEARCH_HOST"
 	opensearchUsernameKey         = "OPENSEARCH_USERNAME"
 	opensearchPasswordKey         = "OPENSEARCH_PASSWORD"
@@ -54,7 +55,25 @@ type AutofollowStats struct {
 }
 
 type IndexReplicationStatus struct {
-	Status string `json:"status"`
+	Status         string          `json:"status"`
+	Reason         string          `json:"reason,omitempty"`
+	SyncingDetails *SyncingDetails `json:"syncing_details,omitempty"`
+}
+
+type SyncingDetails struct {
+	LeaderCheckpoint   int `json:"leader_checkpoint"`
+	FollowerCheckpoint int `json:"follower_checkpoint"`
+	Seq                int `json:"seq_no"`
+}
+
+// ReplicationDetails describes the replication state of standby side in details
+type ReplicationDetails struct {
+	AutofollowRules    []RuleStats                       `json:"autofollowRules,omitempty"`
+	FailedIndices      []string                          `json:"failedIndices,omitempty"`
+	UnhealthyIndices   []string                          `json:"unhealthyIndices,omitempty"`
+	PausedIndices      []string                          `json:"pausedIndices,omitempty"`
+	Indices            map[string]IndexReplicationStatus `json:"indices,omitempty"`
+	LastWatcherRestart string                            `json:"lastWatcherRestart,omitempty"`
 }
 
 type Index struct {
@@ -62,17 +81,23 @@ type Index struct {
 	Health string `json:"health"`
 }
 
-func NewReplicationChecker(opensearchName string, opensearchProtocol string) ReplicationChecker {
+// IndexMatcher checks whether the index is replicated by replication rules, that is matches include patterns
+// and does not match exclude patterns of any rule
+type IndexMatcher func(index string) bool
+
+func NewReplicationChecker(opensearchName string, opensearchProtocol string, matcher IndexMatcher) ReplicationChecker {
 	url := createUrl(opensearchProtocol, opensearchName, 9200)
 	restClient := util.NewRestClient(url, configureClient(), readOpenSearchCredentials())
 	return ReplicationChecker{
 		restClient: *restClient,
+		matcher:    matcher,
 	}
 }
 
-func NewReplicationCheckerWithClient(restClient util.RestClient) ReplicationChecker {
+func NewReplicationCheckerWithClient(restClient util.RestClient, matcher IndexMatcher) ReplicationChecker {
 	return ReplicationChecker{
 		restClient: restClient,
+		matcher:    matcher,
 	}
 }
 
@@ -89,70 +114,158 @@ func readOpenSearchCredentials() util.Credentials {
 
 type ReplicationChecker struct {
 	restClient util.RestClient
+	matcher    IndexMatcher
 }
 
 func (rc ReplicationChecker) CheckReplication() (string, error) {
 	// read credentials on every check to rotate them without restarting the operator
 	rc.restClient.SetCredentials(readOpenSearchCredentials())
 
-	statusCode, responseBody, err := rc.restClient.SendRequest(http.MethodGet, "_plugins/_replication/autofollow_stats", nil)
+	autofollowStats, err := rc.getAutofollowStats()
 	if err != nil {
-		log.Error(err, "An error occurred during autofollow_stats HTTP request")
 		return "", err
 	}
-	if statusCode >= 500 {
-		log.Error(err, "Opensearch returned status code more than 500")
-		return "", fmt.Errorf("internal server error")
+	status := ""
+	for _, rule := range autofollowStats.AutofollowRuleStats {
+		if !isDisasterRecoveryRule(rule.Name) {
+			continue
+		}
+		ruleStatus, err := rc.checkRule(rule)
+		if err != nil {
+			return "", err
+		}
+		if status == "" || statusSeverity[ruleStatus] > statusSeverity[status] {
+			status = ruleStatus
+		}
 	}
-	var autofollowStats AutofollowStats
-	err = json.Unmarshal(responseBody, &autofollowStats)
+	if status == "" {
+		log.Info("Can not recognize replication state")
+		return DOWN, nil
+	}
+	return status, nil
+}
+
+// checkRule returns the replication state for indices of the autofollow rule.
+// Indices matching exclude patterns of replication rules are not taken into account
+func (rc ReplicationChecker) checkRule(rule RuleStats) (string, error) {
+	failedIndices := rc.failedIndices(rule)
+	if len(failedIndices) == 0 {
+		// Failed starts of replication are caused by excluded indices if there are any of them among failed indices
+		if rule.FailedStart > 0 && len(util.FilterSlice(rule.FailedIndices, rc.isExcluded)) == 0 {
+			return DEGRADED, nil
+		}
+	} else {
+		if rule.SuccessStart > 0 {
+			return DEGRADED, nil
+		} else {
+			return DOWN, nil
+		}
+	}
+	unhealthyIndices, err := rc.listUnhealthyIndices(rule.Pattern)
+	if err != nil {
+		return "", err
+	}
+	if len(unhealthyIndices) > 0 {
+		log.Info(fmt.Sprintf("The following indices are not healthy: %v", unhealthyIndices))
+		return DEGRADED, nil
+	}
+	failedReplicationsFound, err := rc.areFailedReplicationsFound(rule.Pattern)
 	if err != nil {
-		log.Error(err, "An error occurred during unmarshalling autofollow_stats HTTP response")
 		return "", err
 	}
+	if failedReplicationsFound {
+		log.Info(fmt.Sprintf("The replication failed for some indices of [%s] rule", rule.Name))
+		return DEGRADED, nil
+	}
+	return UP, nil
+}
+
+// GetReplicationDetails collects autofollow rules statistics and replication state of each index
+// matching the replication rules patterns
+func (rc ReplicationChecker) GetReplicationDetails() (ReplicationDetails, error) {
+	rc.restClient.SetCredentials(readOpenSearchCredentials())
+
+	var details ReplicationDetails
+	autofollowStats, err := rc.getAutofollowStats()
+	if err != nil {
+		return details, err
+	}
 	for _, rule := range autofollowStats.AutofollowRuleStats {
-		if rule.Name == replicationName {
-			failedIndices := util.FilterSlice(rule.FailedIndices, func(s string) bool {
-				return !strings.HasPrefix(s, ".")
-			})
-			if len(failedIndices) == 0 {
-				if rule.FailedStart > 0 {
-					return DEGRADED, nil
-				}
-			} else {
-				if rule.SuccessStart > 0 {
-					return DEGRADED, nil
-				} else {
-					return DOWN, nil
-				}
-			}
+		if !isDisasterRecoveryRule(rule.Name) {
+			continue
 		}
+		details.AutofollowRules = append(details.AutofollowRules, rule)
+		details.FailedIndices = append(details.FailedIndices, rc.failedIndices(rule)...)
 		unhealthyIndices, err := rc.listUnhealthyIndices(rule.Pattern)
 		if err != nil {
-			return "", err
+			return details, err
 		}
-		if len(unhealthyIndices) > 0 {
-			log.Info(fmt.Sprintf("The following indices are not healthy: %v", unhealthyIndices))
-			return DEGRADED, nil
-		}
-		failedReplicationsFound, err := rc.areFailedReplicationsFound(rule.Pattern)
+		details.UnhealthyIndices = append(details.UnhealthyIndices, unhealthyIndices...)
+		indices, err := rc.listIndices(rule.Pattern)
 		if err != nil {
-			return "", err
+			return details, err
 		}
-		if failedReplicationsFound {
-			log.Info("The replication failed for some indices")
-			return DEGRADED, nil
-		} else {
-			return UP, nil
+		if details.Indices == nil {
+			details.Indices = make(map[string]IndexReplicationStatus, len(indices))
+		}
+		for _, index := range indices {
+			if _, ok := details.Indices[index]; ok {
+				continue
+			}
+			replicationStatus, err := rc.getIndexReplicationStatus(index)
+			if err != nil {
+				log.Error(err, fmt.Sprintf("Cannot get replication status of [%s] index", index))
+				continue
+			}
+			details.Indices[index] = replicationStatus
+			if replicationStatus.Status == pausedStatus {
+				details.PausedIndices = append(details.PausedIndices, index)
+			}
 		}
 	}
-	log.Info("Can not recognize replication state")
-	return DOWN, nil
+	return details, nil
+}
+
+// isDisasterRecoveryRule checks whether autofollow rule with the given name is managed by the operator
+func isDisasterRecoveryRule(name string) bool {
+	return name == replicationName || strings.HasPrefix(name, replicationName+"-")
+}
+
+// failedIndices returns non-service failed indices of the autofollow rule which are not excluded from the replication
+func (rc ReplicationChecker) failedIndices(rule RuleStats) []string {
+	return util.FilterSlice(rule.FailedIndices, func(s string) bool {
+		return !strings.HasPrefix(s, ".") && !rc.isExcluded(s)
+	})
+}
+
+// isExcluded checks whether the index is not replicated by replication rules.
+// All indices are replicated if the matcher is not specified
+func (rc ReplicationChecker) isExcluded(index string) bool {
+	return rc.matcher != nil && !rc.matcher(index)
+}
+
+func (rc ReplicationChecker) getAutofollowStats() (AutofollowStats, error) {
+	var autofollowStats AutofollowStats
+	statusCode, responseBody, err := rc.restClient.SendRequest(http.MethodGet, "_plugins/_replication/autofollow_stats", nil)
+	if err != nil {
+		log.Error(err, "An error occurred during autofollow_stats HTTP request")
+		return autofollowStats, err
+	}
+	if statusCode >= 500 {
+		log.Error(err, "Opensearch returned status code more than 500")
+		return autofollowStats, fmt.Errorf("internal server error")
+	}
+	err = json.Unmarshal(responseBody, &autofollowStats)
+	if err != nil {
+		log.Error(err, "An error occurred during unmarshalling autofollow_stats HTTP response")
+		return autofollowStats, err
+	}
+	return autofollowStats, nil
 }
 
 func (rc ReplicationChecker) listUnhealthyIndices(pattern string) ([]string, error) {
 	var indices []string
-	responseBody, err := rc.restClient.SendRequestWithStatusCodeCheck(http.MethodGet, catIndicesPath, nil)
+	responseBody, err := rc.restClient.SendRequestWithStatusCodeCheck(http.MethodGet, fmt.Sprintf(catIndicesPattern, pattern), nil)
 	if err != nil {
 		log.Error(err, "An error occurred during getting OpenSearch indices")
 		return indices, err
@@ -163,9 +276,8 @@ func (rc ReplicationChecker) listUnhealthyIndices(pattern string) ([]string, err
 		log.Error(err, "An error occurred during unmarshalling OpenSearch indices response")
 		return indices, err
 	}
-	re := regexp.MustCompile(strings.ReplaceAll(pattern, "*", ".*"))
 	for _, index := range allIndices {
-		if re.MatchString(index.Index) && index.Health == "red" {
+		if index.Health == "red" && !rc.isExcluded(index.Index) {
 			indices = append(indices, index.Index)
 		}
 	}
@@ -173,21 +285,11 @@ func (rc ReplicationChecker) listUnhealthyIndices(pattern string) ([]string, err
 }
 
 func (rc ReplicationChecker) areFailedReplicationsFound(pattern string) (bool, error) {
-	responseBody, err := rc.restClient.SendRequestWithStatusCodeCheck(http.MethodGet, pattern, nil)
+	indices, err := rc.listIndices(pattern)
 	if err != nil {
-		log.Error(err, "An error occurred during getting OpenSearch indices")
-		return true, err
-	}
-	var indices map[string]interface{}
-	err = json.Unmarshal(responseBody, &indices)
-	if err != nil {
-		log.Error(err, "An error occurred during unmarshalling OpenSearch indices response")
 		return true, err
 	}
-	for index := range indices {
-		if strings.HasPrefix(index, ".") {
-			continue
-		}
+	for _, index := range indices {
 		replicationStatus, err := rc.getIndexReplicationStatus(index)
 		if err != nil {
 			log.Error(err, fmt.Sprintf("Cannot get replication status of [%s] index", index))
@@ -201,6 +303,29 @@ func (rc ReplicationChecker) areFailedReplicationsFound(pattern string) (bool, e
 	return false, nil
 }
 
+// listIndices returns names of non-service indices matching the given pattern which are not excluded from the replication
+func (rc ReplicationChecker) listIndices(pattern string) ([]string, error) {
+	responseBody, err := rc.restClient.SendRequestWithStatusCodeCheck(http.MethodGet, pattern, nil)
+	if err != nil {
+		log.Error(err, "An error occurred during getting OpenSearch indices")
+		return nil, err
+	}
+	var indices map[string]interface{}
+	err = json.Unmarshal(responseBody, &indices)
+	if err != nil {
+		log.Error(err, "An error occurred during unmarshalling OpenSearch indices response")
+		return nil, err
+	}
+	var names []string
+	for index := range indices {
+		if !strings.HasPrefix(index, ".") && !rc.isExcluded(index) {
+			names = append(names, index)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 func (rc ReplicationChecker) getIndexReplicationStatus(indexName string) (IndexReplicationStatus, error) {
 	var indexReplicationStatus IndexReplicationStatus
 	path := fmt.Sprintf(indexReplicationStatusPattern, indexName)
@@ -0,0 +1,197 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disasterrecovery
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	BasicAuthType  = "basic"
+	TokenAuthType  = "token"
+	tlsCertificate = "tls.crt"
+	tlsKey         = "tls.key"
+	tlsCA          = "ca.crt"
+)
+
+// ServerSecurity defines TLS and authentication of Disaster Recovery server. The same certificates
+// are used by the operator as the trust configuration for requests to the other side.
+type ServerSecurity struct {
+	TLSEnabled       bool
+	CertificatesPath string
+	ClientAuth       bool
+	AuthType         string
+	Username         string
+	Password         string
+	Token            string
+}
+
+// NewServerSecurity reads the security configuration of Disaster Recovery server from environment variables
+func NewServerSecurity() ServerSecurity {
+	return ServerSecurity{
+		TLSEnabled:       strings.EqualFold(os.Getenv("DR_SERVER_TLS_ENABLED"), "true"),
+		CertificatesPath: GetEnv("DR_SERVER_CERTS_PATH", "/drServerTls"),
+		ClientAuth:       strings.EqualFold(os.Getenv("DR_SERVER_CLIENT_AUTH"), "true"),
+		AuthType:         strings.ToLower(os.Getenv("DR_SERVER_AUTH_TYPE")),
+		Username:         os.Getenv("DR_SERVER_USERNAME"),
+		Password:         os.Getenv("DR_SERVER_PASSWORD"),
+		Token:            os.Getenv("DR_SERVER_TOKEN"),
+	}
+}
+
+// Enabled checks whether TLS or authentication is configured
+func (s ServerSecurity) Enabled() bool {
+	return s.TLSEnabled || s.AuthType != ""
+}
+
+// Validate checks that the authentication type is supported and its credentials are specified
+func (s ServerSecurity) Validate() error {
+	switch s.AuthType {
+	case "":
+	case BasicAuthType:
+		if s.Username == "" || s.Password == "" {
+			return fmt.Errorf("username and password must be specified for %s authentication", BasicAuthType)
+		}
+	case TokenAuthType:
+		if s.Token == "" {
+			return fmt.Errorf("token must be specified for %s authentication", TokenAuthType)
+		}
+	default:
+		return fmt.Errorf("unsupported authentication type [%s], must be %s or %s", s.AuthType, BasicAuthType, TokenAuthType)
+	}
+	return nil
+}
+
+// ServerTLSConfig returns TLS configuration of Disaster Recovery server. The certificate is read
+// on each handshake to pick up renewed certificates, client certificates are required for mutual TLS.
+func (s ServerSecurity) ServerTLSConfig() (*tls.Config, error) {
+	if _, err := s.loadCertificate(); err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.loadCertificate()
+		},
+	}
+	if s.ClientAuth {
+		caPool, err := s.loadCA()
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = caPool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// ClientTLSConfig returns TLS configuration for requests to Disaster Recovery server on the other side.
+// The certificate of the current side is presented as the client one for mutual TLS.
+func (s ServerSecurity) ClientTLSConfig() (*tls.Config, error) {
+	caPool, err := s.loadCA()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    caPool,
+	}
+	if s.ClientAuth {
+		certificate, err := s.loadCertificate()
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{*certificate}
+	}
+	return tlsConfig, nil
+}
+
+// NewClient returns HTTP client for requests to Disaster Recovery server on the other side
+func (s ServerSecurity) NewClient(timeout time.Duration) (http.Client, error) {
+	httpClient := http.Client{Timeout: timeout}
+	if !s.TLSEnabled {
+		return httpClient, nil
+	}
+	tlsConfig, err := s.ClientTLSConfig()
+	if err != nil {
+		return httpClient, err
+	}
+	httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	return httpClient, nil
+}
+
+// Authenticate rejects requests without the configured basic credentials or Bearer token
+func (s ServerSecurity) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAuthorized(r) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`%s realm="disaster-recovery"`, s.scheme()))
+			sendResponse(w, http.StatusUnauthorized, ErrorResponse{Message: "unauthorized"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s ServerSecurity) isAuthorized(r *http.Request) bool {
+	switch s.AuthType {
+	case BasicAuthType:
+		username, password, ok := r.BasicAuth()
+		return ok && equal(username, s.Username) && equal(password, s.Password)
+	case TokenAuthType:
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return ok && equal(token, s.Token)
+	}
+	return true
+}
+
+func (s ServerSecurity) scheme() string {
+	if s.AuthType == TokenAuthType {
+		return "Bearer"
+	}
+	return "Basic"
+}
+
+func (s ServerSecurity) loadCertificate() (*tls.Certificate, error) {
+	certificate, err := tls.LoadX509KeyPair(filepath.Join(s.CertificatesPath, tlsCertificate),
+		filepath.Join(s.CertificatesPath, tlsKey))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load Disaster Recovery server certificate: %w", err)
+	}
+	return &certificate, nil
+}
+
+func (s ServerSecurity) loadCA() (*x509.CertPool, error) {
+	caCert, err := os.ReadFile(filepath.Join(s.CertificatesPath, tlsCA))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Disaster Recovery CA certificate: %w", err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no certificates found in %s", filepath.Join(s.CertificatesPath, tlsCA))
+	}
+	return caPool, nil
+}
+
+func equal(actual string, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1
+}
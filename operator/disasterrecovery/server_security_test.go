@@ -0,0 +1,162 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package disasterrecovery
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCertificates creates the self-signed certificate which is used as CA, server and client certificate
+func writeSelfSignedCertificates(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "disaster-recovery"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %v", err)
+	}
+	path := t.TempDir()
+	certificatePem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})
+	files := map[string][]byte{
+		tlsCertificate: certificatePem,
+		tlsCA:          certificatePem,
+		tlsKey:         pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}),
+	}
+	for name, content := range files {
+		if err = os.WriteFile(filepath.Join(path, name), content, 0600); err != nil {
+			t.Fatalf("unable to write %s: %v", name, err)
+		}
+	}
+	return path
+}
+
+// newSecuredServer starts the server with TLS listener configured by the security,
+// httptest.StartTLS is not used because it replaces the certificate with its own one
+func newSecuredServer(t *testing.T, security ServerSecurity) (*httptest.Server, string) {
+	tlsConfig, err := security.ServerTLSConfig()
+	if err != nil {
+		t.Fatalf("unable to configure server TLS: %v", err)
+	}
+	server := httptest.NewUnstartedServer(ServerHandlers(ServerContext{
+		switchoverDryRun: &switchoverDryRunStub{},
+		security:         security,
+	}))
+	server.Listener = tls.NewListener(server.Listener, tlsConfig)
+	server.Start()
+	return server, "https://" + server.Listener.Addr().String()
+}
+
+func TestServerSecurity_MutualTLS(t *testing.T) {
+	security := ServerSecurity{TLSEnabled: true, CertificatesPath: writeSelfSignedCertificates(t), ClientAuth: true}
+	server, url := newSecuredServer(t, security)
+	defer server.Close()
+
+	client, err := security.NewClient(5 * time.Second)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	response, err := client.Post(url+"/switchover/dry-run", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request with client certificate failed: %v", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusAccepted {
+		t.Errorf("expected %d status code, got %d", http.StatusAccepted, response.StatusCode)
+	}
+
+	withoutCertificate := security
+	withoutCertificate.ClientAuth = false
+	client, _ = withoutCertificate.NewClient(5 * time.Second)
+	if response, err = client.Post(url+"/switchover/dry-run", "application/json", nil); err == nil {
+		_ = response.Body.Close()
+		t.Error("expected request without client certificate to fail")
+	}
+}
+
+func TestServerSecurity_Authentication(t *testing.T) {
+	tests := []struct {
+		name       string
+		security   ServerSecurity
+		authorize  func(r *http.Request)
+		statusCode int
+	}{
+		{name: "no authentication", security: ServerSecurity{}, authorize: func(r *http.Request) {},
+			statusCode: http.StatusAccepted},
+		{name: "valid basic credentials", security: ServerSecurity{AuthType: BasicAuthType, Username: "dr", Password: "secret"},
+			authorize: func(r *http.Request) { r.SetBasicAuth("dr", "secret") }, statusCode: http.StatusAccepted},
+		{name: "invalid basic credentials", security: ServerSecurity{AuthType: BasicAuthType, Username: "dr", Password: "secret"},
+			authorize: func(r *http.Request) { r.SetBasicAuth("dr", "wrong") }, statusCode: http.StatusUnauthorized},
+		{name: "valid token", security: ServerSecurity{AuthType: TokenAuthType, Token: "secret"},
+			authorize: func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") }, statusCode: http.StatusAccepted},
+		{name: "missing token", security: ServerSecurity{AuthType: TokenAuthType, Token: "secret"},
+			authorize: func(r *http.Request) {}, statusCode: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := ServerHandlers(ServerContext{switchoverDryRun: &switchoverDryRunStub{}, security: test.security})
+			request := httptest.NewRequest(http.MethodPost, "/switchover/dry-run", nil)
+			test.authorize(request)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != test.statusCode {
+				t.Errorf("expected %d status code, got %d", test.statusCode, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestServerSecurity_Validate(t *testing.T) {
+	if err := (ServerSecurity{AuthType: BasicAuthType, Username: "dr"}).Validate(); err == nil {
+		t.Error("expected error for basic authentication without password")
+	}
+	if err := (ServerSecurity{AuthType: "digest"}).Validate(); err == nil {
+		t.Error("expected error for unsupported authentication type")
+	}
+	if err := (ServerSecurity{AuthType: TokenAuthType, Token: "secret"}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
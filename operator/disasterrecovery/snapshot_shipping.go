@@ -0,0 +1,89 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disasterrecovery
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultSnapshotShippingInterval is used if the interval of snapshot shipping is not specified
+	DefaultSnapshotShippingInterval = 300 * time.Second
+	// maxMissedSnapshotChecks is the number of intervals without successful restore check
+	// after which the standby side is considered degraded
+	maxMissedSnapshotChecks = 3
+)
+
+// ErrSnapshotShippingDisabled is returned when Disaster Recovery is based on the cross cluster replication
+var ErrSnapshotShippingDisabled = errors.New("snapshot shipping is not enabled")
+
+// SnapshotShipping provides the state of snapshots restore on the standby side
+type SnapshotShipping interface {
+	// Status returns the status of snapshot shipping and the interval between restores
+	Status() (*opensearchservice.SnapshotShippingStatus, time.Duration, error)
+}
+
+// CustomResourceSnapshotShipping reads the status of snapshot shipping from OpenSearchService custom resource
+type CustomResourceSnapshotShipping struct {
+	client    client.Client
+	name      string
+	namespace string
+}
+
+func NewCustomResourceSnapshotShipping(client client.Client, name string, namespace string) CustomResourceSnapshotShipping {
+	return CustomResourceSnapshotShipping{
+		client:    client,
+		name:      name,
+		namespace: namespace,
+	}
+}
+
+func (s CustomResourceSnapshotShipping) Status() (*opensearchservice.SnapshotShippingStatus, time.Duration, error) {
+	instance := &opensearchservice.OpenSearchService{}
+	if err := s.client.Get(context.TODO(), types.NamespacedName{Name: s.name, Namespace: s.namespace}, instance); err != nil {
+		return nil, 0, err
+	}
+	if instance.Spec.DisasterRecovery == nil || instance.Spec.DisasterRecovery.SnapshotShipping == nil ||
+		!instance.Spec.DisasterRecovery.SnapshotShipping.Enabled {
+		return nil, 0, ErrSnapshotShippingDisabled
+	}
+	interval := DefaultSnapshotShippingInterval
+	if seconds := instance.Spec.DisasterRecovery.SnapshotShipping.Interval; seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	return instance.Status.DisasterRecoveryStatus.SnapshotShipping, interval, nil
+}
+
+// snapshotShippingHealth returns `down` if no snapshot has been restored on the standby side yet,
+// `degraded` if the last restore failed or there was no successful check for new snapshots for a long time
+func snapshotShippingHealth(status *opensearchservice.SnapshotShippingStatus, interval time.Duration, now time.Time) string {
+	if status == nil || status.Mode != "standby" || status.Snapshot == "" {
+		return DOWN
+	}
+	if status.Error != "" {
+		return DEGRADED
+	}
+	lastSyncTime, err := time.Parse(time.RFC3339, status.LastSyncTime)
+	if err != nil || now.Sub(lastSyncTime) > maxMissedSnapshotChecks*interval {
+		return DEGRADED
+	}
+	return UP
+}
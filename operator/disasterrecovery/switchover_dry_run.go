@@ -0,0 +1,82 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disasterrecovery
+
+import (
+	"context"
+	"time"
+
+	opensearchservice "github.com/Netcracker/qubership-opensearch/operator/api/v1"
+	"github.com/Netcracker/qubership-opensearch/operator/util"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SwitchoverDryRun requests switchover checks without side effects and provides their last report
+type SwitchoverDryRun interface {
+	Request() (string, error)
+	Report() (*opensearchservice.SwitchoverReport, error)
+}
+
+// CustomResourceDryRun requests switchover dry-run through the annotation of OpenSearchService custom resource,
+// the dry-run itself is performed by the operator during reconciliation
+type CustomResourceDryRun struct {
+	client    client.Client
+	name      string
+	namespace string
+}
+
+func NewCustomResourceDryRun(client client.Client, name string, namespace string) CustomResourceDryRun {
+	return CustomResourceDryRun{
+		client:    client,
+		name:      name,
+		namespace: namespace,
+	}
+}
+
+// Request sets the unique value of the dry-run annotation and returns it
+func (dr CustomResourceDryRun) Request() (string, error) {
+	request := time.Now().UTC().Format(time.RFC3339Nano)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		instance, err := dr.getCustomResource()
+		if err != nil {
+			return err
+		}
+		annotations := instance.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[util.SwitchoverDryRunAnnotationKey] = request
+		instance.SetAnnotations(annotations)
+		return dr.client.Update(context.TODO(), instance)
+	})
+	return request, err
+}
+
+// Report returns the last switchover dry-run report or nil if dry-run has never been performed
+func (dr CustomResourceDryRun) Report() (*opensearchservice.SwitchoverReport, error) {
+	instance, err := dr.getCustomResource()
+	if err != nil {
+		return nil, err
+	}
+	return instance.Status.DisasterRecoveryStatus.SwitchoverReport, nil
+}
+
+func (dr CustomResourceDryRun) getCustomResource() (*opensearchservice.OpenSearchService, error) {
+	instance := &opensearchservice.OpenSearchService{}
+	err := dr.client.Get(context.TODO(), types.NamespacedName{Name: dr.name, Namespace: dr.namespace}, instance)
+	return instance, err
+}
@@ -0,0 +1,61 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disasterrecovery
+
+import (
+	"sync"
+	"time"
+)
+
+// WatcherInfo keeps the replication watcher activity which is shared between
+// the replication watcher and the disaster recovery health server
+type WatcherInfo struct {
+	lock        sync.RWMutex
+	lastRestart time.Time
+	matcher     IndexMatcher
+}
+
+func NewWatcherInfo() *WatcherInfo {
+	return &WatcherInfo{}
+}
+
+func (wi *WatcherInfo) RecordRestart(restartTime time.Time) {
+	wi.lock.Lock()
+	defer wi.lock.Unlock()
+	wi.lastRestart = restartTime
+}
+
+// LastRestart returns the time of the last replication restart performed by the watcher
+// or zero time if there were no restarts
+func (wi *WatcherInfo) LastRestart() time.Time {
+	wi.lock.RLock()
+	defer wi.lock.RUnlock()
+	return wi.lastRestart
+}
+
+// SetIndexMatcher remembers the matcher of the current replication rules
+func (wi *WatcherInfo) SetIndexMatcher(matcher IndexMatcher) {
+	wi.lock.Lock()
+	defer wi.lock.Unlock()
+	wi.matcher = matcher
+}
+
+// Matches checks whether the index is replicated by the current replication rules.
+// All indices are considered replicated until replication rules are known
+func (wi *WatcherInfo) Matches(index string) bool {
+	wi.lock.RLock()
+	defer wi.lock.RUnlock()
+	return wi.matcher == nil || wi.matcher(index)
+}
@@ -110,13 +110,20 @@ func main() {
 	var mutex sync.Mutex
 	var mutexTwo sync.Mutex
 	var mutexThree sync.Mutex
+	var mutexFour sync.Mutex
+	var mutexFive sync.Mutex
+	var mutexSix sync.Mutex
+	watcherInfo := disasterrecovery.NewWatcherInfo()
 	if err = (&controllers.OpenSearchServiceReconciler{
-		Client:                mgr.GetClient(),
-		Scheme:                mgr.GetScheme(),
-		ResourceHashes:        map[string]string{},
-		ReplicationWatcher:    controllers.NewReplicationWatcher(&mutex),
-		SlowLogIndicesWatcher: controllers.NewSlowLogIndicesWatcher(&mutexTwo),
-		IndexSettingsWatcher:  controllers.NewIndexSettingsWatcher(&mutexThree),
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		ResourceHashes:           map[string]string{},
+		ReplicationWatcher:       controllers.NewReplicationWatcher(&mutex, watcherInfo),
+		SlowLogIndicesWatcher:    controllers.NewSlowLogIndicesWatcher(&mutexTwo),
+		IndexSettingsWatcher:     controllers.NewIndexSettingsWatcher(&mutexThree),
+		ConfigurationSyncWatcher: controllers.NewConfigurationSyncWatcher(&mutexFour),
+		ConsistencyWatcher:       controllers.NewConsistencyWatcher(&mutexFive),
+		SnapshotShippingWatcher:  controllers.NewSnapshotShippingWatcher(&mutexSix),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenSearchService")
 		os.Exit(1)
@@ -138,11 +145,16 @@ func main() {
 		os.Exit(1)
 	}
 	opensearchProtocol := os.Getenv(opensearchProtocolEnvVar)
-	replicationChecker := disasterrecovery.NewReplicationChecker(opensearchName, opensearchProtocol)
+	replicationChecker := disasterrecovery.NewReplicationChecker(opensearchName, opensearchProtocol, watcherInfo.Matches)
 
 	setupLog.Info("Starting disaster recovery REST server.")
 	go func() {
-		if err = disasterrecovery.StartServer(replicationChecker); err != nil {
+		switchoverDryRun := disasterrecovery.NewCustomResourceDryRun(mgr.GetClient(), opensearchName, namespace)
+		fencing := disasterrecovery.NewCustomResourceFencing(mgr.GetClient(), opensearchName, namespace)
+		security := disasterrecovery.NewServerSecurity()
+		snapshotShipping := disasterrecovery.NewCustomResourceSnapshotShipping(mgr.GetClient(), opensearchName, namespace)
+		if err = disasterrecovery.StartServer(replicationChecker, watcherInfo, switchoverDryRun, fencing, security,
+			snapshotShipping); err != nil {
 			setupLog.Error(err, "Disaster recovery REST server cannot be created because of error")
 			os.Exit(1)
 		}
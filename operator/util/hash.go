@@ -21,8 +21,10 @@ import (
 )
 
 const (
-	SwitchoverAnnotationKey = "switchoverRetry"
-	RetryFailedComment      = "retry failed"
+	SwitchoverAnnotationKey       = "switchoverRetry"
+	SwitchoverDryRunAnnotationKey = "switchoverDryRun"
+	ConsistencyCheckAnnotationKey = "consistencyCheck"
+	RetryFailedComment            = "retry failed"
 )
 
 // Hash returns hash SHA-256 of object